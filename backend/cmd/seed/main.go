@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/cli"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/config"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/seed"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/service"
+)
+
+func main() {
+	defaults := seed.DefaultGenerateOptions()
+
+	opts := defaults
+	flag.Int64Var(&opts.Seed, "seed", defaults.Seed, "random seed; the same seed generates the same data")
+	flag.IntVar(&opts.Users, "users", defaults.Users, "number of users to generate")
+	flag.IntVar(&opts.Articles, "articles", defaults.Articles, "number of articles to generate")
+	flag.IntVar(&opts.Follows, "follows", defaults.Follows, "number of follows to generate")
+	flag.IntVar(&opts.Favorites, "favorites", defaults.Favorites, "number of favorites to generate")
+	flag.IntVar(&opts.Comments, "comments", defaults.Comments, "number of comments to generate")
+	flag.StringVar(&opts.Password, "password", defaults.Password, "password of every generated user")
+	fixturePath := flag.String("fixture", "", "load data from a YAML or JSON fixture instead of generating it")
+	dump := flag.Bool("dump", false, "print the data as a YAML fixture instead of writing it to the database")
+	flag.Parse()
+
+	// Build the data set
+	var fixture *seed.Fixture
+	if *fixturePath != "" {
+		loaded, err := seed.LoadFixture(*fixturePath)
+		if err != nil {
+			log.Fatal("Failed to load fixture: ", err)
+		}
+		fixture = loaded
+	} else {
+		fixture = seed.Generate(opts)
+	}
+
+	if *dump {
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(fixture); err != nil {
+			log.Fatal("Failed to write fixture: ", err)
+		}
+		return
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+
+	// Initialize database
+	database, err := cli.OpenDatabase(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+	defer database.Close()
+
+	if err := database.Migrate(); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(database)
+	articleRepo := repository.NewArticleRepository(database)
+	tagRepo := repository.NewTagRepository(database)
+	commentRepo := repository.NewCommentRepository(database)
+	uow := repository.NewUnitOfWork(database)
+
+	// Initialize services
+	tagService := service.NewTagService(tagRepo)
+	services := seed.Services{
+		Users:    service.NewUserService(userRepo),
+		Articles: service.NewArticleService(uow, articleRepo, userRepo, tagService),
+		Profiles: service.NewProfileService(userRepo),
+		Comments: service.NewCommentService(commentRepo, articleRepo, userRepo),
+	}
+
+	summary, err := seed.Apply(context.Background(), services, fixture)
+	if err != nil {
+		log.Fatal("Failed to seed database: ", err)
+	}
+
+	log.Printf("Seeded %d users, %d articles, %d follows, %d favorites and %d comments",
+		summary.Users, summary.Articles, summary.Follows, summary.Favorites, summary.Comments)
+}
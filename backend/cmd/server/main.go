@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/gorilla/mux"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/cli"
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/config"
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/handler"
@@ -22,46 +26,80 @@ func main() {
 		log.Fatal("Failed to load configuration:", err)
 	}
 
+	// "server migrate ...", "server backup ..." etc. run a maintenance
+	// command without starting the server
+	if len(os.Args) > 1 {
+		if command, ok := cli.Commands[os.Args[1]]; ok {
+			if err := command(cfg, os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("%s failed: %v", os.Args[1], err)
+			}
+			return
+		}
+	}
+
+	noMigrate := flag.Bool("no-migrate", false, "start without applying pending migrations (run \"server migrate up\" separately)")
+	flag.Parse()
+
 	// Initialize database
-	database, err := db.NewDatabase(cfg.DatabaseURL)
+	if cfg.MigrationsDir != "" {
+		log.Printf("Loading migrations from %s", cfg.MigrationsDir)
+	}
+	database, err := cli.OpenDatabase(cfg)
 	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 	defer database.Close()
 
 	// Run migrations
-	log.Println("Running database migrations...")
-	if err := database.Migrate(); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	if *noMigrate {
+		status, err := database.MigrationStatus()
+		if err != nil {
+			log.Fatal("Failed to read migration status:", err)
+		}
+		if pending := status.Count(db.MigrationPending); pending > 0 {
+			log.Printf("WARNING: starting with %d pending migrations", pending)
+		}
+	} else {
+		log.Println("Running database migrations...")
+		if err := database.Migrate(); err != nil {
+			log.Fatal("Failed to run migrations:", err)
+		}
+		log.Println("Database migrations completed successfully")
 	}
-	log.Println("Database migrations completed successfully")
+
+	// Record statement timings for the slow-query log and /api/admin/queries
+	database.SetQueryStats(db.NewQueryStats(cfg.SlowQueryThreshold))
 
 	// Create router
 	router := mux.NewRouter()
 
-	// Apply CORS middleware
+	// Apply logging and CORS middleware
+	router.Use(middleware.Logging)
 	router.Use(middleware.CORS)
 
 	// Health check endpoint
-	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, `{"status":"ok","service":"realworld-backend"}`)
-	}).Methods("GET")
+	healthHandler := handler.NewHealthHandler(database)
+	router.HandleFunc("/health", healthHandler.Check).Methods("GET")
 
 	// Initialize repositories
-	userRepo := repository.NewUserRepository(database.DB)
-	articleRepo := repository.NewArticleRepository(database.DB)
-	tagRepo := repository.NewTagRepository(database.DB)
-	commentRepo := repository.NewCommentRepository(database.DB)
+	userRepo := repository.NewUserRepository(database)
+	articleRepo := repository.NewArticleRepository(database)
+	tagRepo := repository.NewTagRepository(database)
+	commentRepo := repository.NewCommentRepository(database)
+	uow := repository.NewUnitOfWork(database)
 
 	// Initialize services
 	userService := service.NewUserService(userRepo)
 	tagService := service.NewTagService(tagRepo)
-	articleService := service.NewArticleService(articleRepo, userRepo, tagService)
-	commentService := service.NewCommentService(commentRepo, userRepo)
+	articleService := service.NewArticleService(uow, articleRepo, userRepo, tagService)
+	commentService := service.NewCommentService(commentRepo, articleRepo, userRepo)
 	profileService := service.NewProfileService(userRepo)
 
+	// Publish scheduled articles in the background
+	publisherCtx, stopPublisher := context.WithCancel(context.Background())
+	defer stopPublisher()
+	go articleService.RunPublisher(publisherCtx, cfg.PublishInterval)
+
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(cfg.JWTSecret)
 	userHandler := handler.NewUserHandler(userService, cfg.JWTSecret)
@@ -74,8 +112,21 @@ func main() {
 	jwtMiddleware := middleware.JWTMiddleware(cfg.JWTSecret)
 	optionalJwtMiddleware := middleware.OptionalJWTMiddleware(cfg.JWTSecret)
 
+	// Admin endpoints (require ADMIN_TOKEN); registered before the API
+	// routes so that backups are not cut short by the request deadline
+	if cfg.AdminToken != "" {
+		adminHandler := handler.NewAdminHandler(database)
+		admin := router.PathPrefix("/api/admin").Subrouter()
+		admin.Use(middleware.AdminTokenMiddleware(cfg.AdminToken))
+		admin.HandleFunc("/backup", adminHandler.Backup).Methods("GET")
+		admin.HandleFunc("/export", adminHandler.Export).Methods("GET")
+		admin.HandleFunc("/queries", adminHandler.QueryStats).Methods("GET")
+		admin.HandleFunc("/queries", adminHandler.ResetQueryStats).Methods("DELETE")
+	}
+
 	// API routes
 	api := router.PathPrefix("/api").Subrouter()
+	api.Use(middleware.Timeout(cfg.DBRequestTimeout))
 
 	// Public endpoints
 	api.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
@@ -104,6 +155,16 @@ func main() {
 		jwtMiddleware(http.HandlerFunc(articleHandler.GetArticlesFeed)).ServeHTTP(w, r)
 	}).Methods("GET", "OPTIONS")
 
+	// The author's drafts and scheduled articles - also before /articles/{slug}
+	api.HandleFunc("/articles/drafts", func(w http.ResponseWriter, r *http.Request) {
+		jwtMiddleware(http.HandlerFunc(articleHandler.GetDrafts)).ServeHTTP(w, r)
+	}).Methods("GET", "OPTIONS")
+
+	// Search endpoint (optional auth) - also before /articles/{slug}
+	api.HandleFunc("/articles/search", func(w http.ResponseWriter, r *http.Request) {
+		optionalJwtMiddleware(http.HandlerFunc(articleHandler.SearchArticles)).ServeHTTP(w, r)
+	}).Methods("GET", "OPTIONS")
+
 	// Public article endpoints (optional auth) - general routes
 	api.HandleFunc("/articles", func(w http.ResponseWriter, r *http.Request) {
 		optionalJwtMiddleware(http.HandlerFunc(articleHandler.GetArticles)).ServeHTTP(w, r)
@@ -129,6 +190,20 @@ func main() {
 		jwtMiddleware(http.HandlerFunc(articleHandler.UnfavoriteArticle)).ServeHTTP(w, r)
 	}).Methods("DELETE", "OPTIONS")
 
+	// Revision history (author only); diff before the numbered revisions
+	api.HandleFunc("/articles/{slug}/revisions", func(w http.ResponseWriter, r *http.Request) {
+		jwtMiddleware(http.HandlerFunc(articleHandler.GetRevisions)).ServeHTTP(w, r)
+	}).Methods("GET", "OPTIONS")
+	api.HandleFunc("/articles/{slug}/revisions/diff", func(w http.ResponseWriter, r *http.Request) {
+		jwtMiddleware(http.HandlerFunc(articleHandler.DiffRevisions)).ServeHTTP(w, r)
+	}).Methods("GET", "OPTIONS")
+	api.HandleFunc("/articles/{slug}/revisions/{revision:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		jwtMiddleware(http.HandlerFunc(articleHandler.GetRevision)).ServeHTTP(w, r)
+	}).Methods("GET", "OPTIONS")
+	api.HandleFunc("/articles/{slug}/revisions/{revision:[0-9]+}/restore", func(w http.ResponseWriter, r *http.Request) {
+		jwtMiddleware(http.HandlerFunc(articleHandler.RestoreRevision)).ServeHTTP(w, r)
+	}).Methods("POST", "OPTIONS")
+
 	// Tag endpoints (public)
 	api.HandleFunc("/tags", tagHandler.GetTags).Methods("GET", "OPTIONS")
 
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/config"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/dump"
+)
+
+// Backup runs "backup PATH", writing a consistent SQLite snapshot to PATH
+func Backup(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: server backup PATH")
+	}
+
+	database, err := OpenDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	if err := database.Backup(context.Background(), args[0]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Backed up database to %s\n", args[0])
+	return nil
+}
+
+// Restore runs "restore PATH", replacing the database with a snapshot
+// written by Backup after checking its migrations
+func Restore(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: server restore PATH")
+	}
+
+	database, err := OpenDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	if err := database.Restore(context.Background(), args[0]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Restored database from %s\n", args[0])
+	return nil
+}
+
+// Export runs "export [PATH]", writing an NDJSON dump to PATH or stdout
+func Export(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: server export [PATH]")
+	}
+
+	database, err := OpenDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	// Without a path the dump goes to out, so report counts elsewhere
+	w, report := out, io.Writer(os.Stderr)
+	if len(args) == 1 && args[0] != "-" {
+		file, err := os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer file.Close()
+		w, report = file, out
+	}
+
+	counts, err := dump.Export(database, w)
+	if err != nil {
+		return err
+	}
+
+	printCounts(report, "Exported", counts)
+	return nil
+}
+
+// Import runs "import PATH", loading an NDJSON dump into an empty database
+func Import(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: server import PATH")
+	}
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open dump: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	database, err := OpenDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	counts, err := dump.Import(database, r)
+	if err != nil {
+		return err
+	}
+
+	printCounts(out, "Imported", counts)
+	return nil
+}
+
+// printCounts writes the number of rows per table
+func printCounts(out io.Writer, verb string, counts dump.Counts) {
+	tables := make([]string, 0, len(counts))
+	for table := range counts {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		fmt.Fprintf(out, "%s %d %s\n", verb, counts[table], table)
+	}
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/config"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+)
+
+// ReconcileCounters runs "reconcile-counters", recomputing the denormalized
+// articles.favorites_count column from the favorites table
+func ReconcileCounters(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: server reconcile-counters")
+	}
+
+	database, err := OpenDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	repaired, err := repository.NewArticleRepository(database).ReconcileFavoritesCounts(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Repaired favorites_count of %d articles\n", repaired)
+	return nil
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+)
+
+func TestReconcileCounters(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig(t)
+
+	var out strings.Builder
+	if err := Migrate(cfg, []string{"up"}, &out); err != nil {
+		t.Fatalf("migrate up error = %v", err)
+	}
+
+	database, err := OpenDatabase(cfg)
+	if err != nil {
+		t.Fatalf("OpenDatabase() error = %v", err)
+	}
+	defer database.Close()
+
+	user := &model.User{Email: "jake@example.com", Username: "jake", PasswordHash: "hash"}
+	if err := repository.NewUserRepository(database).Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	articleRepo := repository.NewArticleRepository(database)
+	article := &model.Article{Slug: "dragons", Title: "Dragons", Description: "d", Body: "b", AuthorID: user.ID}
+	if err := articleRepo.Create(ctx, article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	if err := articleRepo.FavoriteArticle(ctx, user.ID, article.ID); err != nil {
+		t.Fatalf("Failed to favorite article: %v", err)
+	}
+	if _, err := database.Exec("UPDATE articles SET favorites_count = 0"); err != nil {
+		t.Fatalf("Failed to corrupt counter: %v", err)
+	}
+
+	out.Reset()
+	if err := ReconcileCounters(cfg, nil, &out); err != nil {
+		t.Fatalf("reconcile-counters error = %v", err)
+	}
+	if !strings.Contains(out.String(), "of 1 articles") {
+		t.Errorf("reconcile-counters output = %q, want 1 article repaired", out.String())
+	}
+	if count, err := articleRepo.GetFavoritesCount(ctx, article.ID); err != nil || count != 1 {
+		t.Errorf("favorites count after reconcile = %d, %v; want 1", count, err)
+	}
+
+	if err := ReconcileCounters(cfg, []string{"extra"}, &out); err == nil {
+		t.Error("Expected reconcile-counters with arguments to fail")
+	}
+}
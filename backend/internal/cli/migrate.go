@@ -0,0 +1,191 @@
+// Package cli implements the subcommands of the server binary
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/config"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+const migrateUsage = `Usage: server migrate [flags] <command>
+
+Commands:
+  status       show applied, pending and drifted migrations
+  up           apply all pending migrations
+  down [N]     revert the last N migrations (default 1)
+  to N         migrate up or down to version N (0 reverts everything)
+  new NAME     create an empty migration file
+
+Flags:
+`
+
+// Command is a subcommand of the server binary
+type Command func(cfg *config.Config, args []string, out io.Writer) error
+
+// Commands maps subcommand names to their implementations
+var Commands = map[string]Command{
+	"migrate":            Migrate,
+	"backup":             Backup,
+	"restore":            Restore,
+	"export":             Export,
+	"import":             Import,
+	"reconcile-counters": ReconcileCounters,
+}
+
+// OpenDatabase connects to the configured database and optional read replica
+// and applies the pool and migration settings from cfg
+func OpenDatabase(cfg *config.Config) (*db.Database, error) {
+	driftPolicy, err := db.ParseDriftPolicy(cfg.MigrationDriftPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := db.NewDatabase(cfg.DatabaseURL, db.Options{
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+		JournalMode:     cfg.SQLiteJournalMode,
+		BusyTimeout:     cfg.SQLiteBusyTimeout,
+		Synchronous:     cfg.SQLiteSynchronous,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DatabaseReplicaURL != "" {
+		if err := database.ConnectReplica(cfg.DatabaseReplicaURL); err != nil {
+			database.Close()
+			return nil, err
+		}
+	}
+
+	// Use migrations from disk instead of the embedded ones if configured
+	if cfg.MigrationsDir != "" {
+		database.SetMigrationsDir(cfg.MigrationsDir)
+	}
+	database.SetDriftPolicy(driftPolicy)
+	database.SetMigrationLockTimeout(cfg.MigrationLockTimeout)
+
+	return database, nil
+}
+
+// Migrate runs the "migrate" subcommand with the arguments following it
+func Migrate(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(out)
+	dryRun := flags.Bool("dry-run", false, "print the SQL that would be executed without running it")
+	dir := flags.String("dir", "", "directory for new migration files (default MIGRATIONS_DIR or ./migrations)")
+	flags.Usage = func() {
+		fmt.Fprint(out, migrateUsage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("missing migrate command")
+	}
+
+	// Allow flags after the command as well, e.g. "migrate up --dry-run"
+	command := flags.Arg(0)
+	if err := flags.Parse(flags.Args()[1:]); err != nil {
+		return err
+	}
+	params := flags.Args()
+
+	if command == "new" {
+		if len(params) != 1 {
+			return fmt.Errorf("usage: server migrate new NAME")
+		}
+		migrationsDir := *dir
+		if migrationsDir == "" {
+			migrationsDir = cfg.MigrationsDir
+		}
+		if migrationsDir == "" {
+			migrationsDir = "migrations"
+		}
+
+		path, err := db.CreateMigrationFile(migrationsDir, params[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Created %s\n", path)
+		return nil
+	}
+
+	database, err := OpenDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	if *dryRun {
+		database.SetDryRun(out)
+	}
+
+	switch command {
+	case "status":
+		return printMigrationStatus(database, out)
+	case "up":
+		return database.Migrate()
+	case "down":
+		steps := 1
+		if len(params) > 0 {
+			steps, err = strconv.Atoi(params[0])
+			if err != nil {
+				return fmt.Errorf("invalid number of steps: %s", params[0])
+			}
+		}
+		return database.Rollback(steps)
+	case "to":
+		if len(params) != 1 {
+			return fmt.Errorf("usage: server migrate to N")
+		}
+		version, err := strconv.Atoi(params[0])
+		if err != nil {
+			return fmt.Errorf("invalid migration version: %s", params[0])
+		}
+		return database.MigrateTo(version)
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown migrate command: %s", command)
+	}
+}
+
+// printMigrationStatus writes a table of migrations and fails on drift so
+// release pipelines can gate on it
+func printMigrationStatus(database *db.Database, out io.Writer) error {
+	status, err := database.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tMIGRATION\tSTATE\tAPPLIED AT")
+	for _, entry := range status.Migrations {
+		appliedAt := "-"
+		if entry.AppliedAt != nil {
+			appliedAt = entry.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", entry.ID, entry.Filename, entry.State, appliedAt)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d applied, %d pending\n", status.Count(db.MigrationApplied), status.Count(db.MigrationPending))
+
+	if status.Drifted() {
+		return fmt.Errorf("migration drift detected: modified %v, missing %v",
+			status.Filenames(db.MigrationModified), status.Filenames(db.MigrationMissing))
+	}
+
+	return nil
+}
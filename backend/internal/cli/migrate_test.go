@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/config"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	return &config.Config{
+		DatabaseURL:          filepath.Join(t.TempDir(), "test.db"),
+		MigrationDriftPolicy: "error",
+		MigrationLockTimeout: time.Second,
+	}
+}
+
+func TestMigrateCommands(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	var out strings.Builder
+	if err := Migrate(cfg, []string{"up", "--dry-run"}, &out); err != nil {
+		t.Fatalf("migrate up --dry-run error = %v", err)
+	}
+	if !strings.Contains(out.String(), "CREATE TABLE IF NOT EXISTS users") {
+		t.Errorf("dry run output = %q, want migration SQL", out.String())
+	}
+
+	out.Reset()
+	if err := Migrate(cfg, []string{"status"}, &out); err != nil {
+		t.Fatalf("migrate status error = %v", err)
+	}
+	if !strings.Contains(out.String(), "0 applied") {
+		t.Errorf("status after dry run = %q, want nothing applied", out.String())
+	}
+
+	steps := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"up"}, "0 pending"},
+		{[]string{"down", "2"}, "2 pending"},
+		{[]string{"to", "3"}, "3 applied"},
+	}
+
+	for _, step := range steps {
+		if err := Migrate(cfg, step.args, &out); err != nil {
+			t.Fatalf("migrate %v error = %v", step.args, err)
+		}
+
+		out.Reset()
+		if err := Migrate(cfg, []string{"status"}, &out); err != nil {
+			t.Fatalf("migrate status error = %v", err)
+		}
+		if !strings.Contains(out.String(), step.expected) {
+			t.Errorf("status after migrate %v = %q, want %q", step.args, out.String(), step.expected)
+		}
+	}
+}
+
+func TestMigrateNew(t *testing.T) {
+	cfg := newTestConfig(t)
+	dir := t.TempDir()
+
+	var out strings.Builder
+	if err := Migrate(cfg, []string{"new", "--dir", dir, "add_widgets"}, &out); err != nil {
+		t.Fatalf("migrate new error = %v", err)
+	}
+	if !strings.Contains(out.String(), filepath.Join(dir, "001_add_widgets.sql")) {
+		t.Errorf("migrate new output = %q, want created file path", out.String())
+	}
+}
+
+func TestMigrateInvalidArguments(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	for _, args := range [][]string{{}, {"sideways"}, {"down", "many"}, {"to"}, {"new"}} {
+		var out strings.Builder
+		if err := Migrate(cfg, args, &out); err == nil {
+			t.Errorf("migrate %v: expected error", args)
+		}
+	}
+}
+
+func TestMigrateStatusIsReadOnly(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	for _, args := range [][]string{{"up", "--dry-run"}, {"down", "--dry-run", "1"}, {"status"}, {"status", "--dry-run"}} {
+		var out strings.Builder
+		if err := Migrate(cfg, args, &out); err != nil {
+			t.Fatalf("migrate %v error = %v", args, err)
+		}
+	}
+
+	database, err := db.Open("sqlite3", cfg.DatabaseURL, db.SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	var tables int
+	err = database.QueryRow("SELECT COUNT(*) FROM sqlite_master").Scan(&tables)
+	database.Close()
+	if err != nil || tables != 0 {
+		t.Fatalf("schema after dry runs and status = %d entries, %v; want none", tables, err)
+	}
+
+	// Status works without write access, as --no-migrate needs at startup
+	var out strings.Builder
+	if err := Migrate(cfg, []string{"up"}, &out); err != nil {
+		t.Fatalf("migrate up error = %v", err)
+	}
+	readOnly := *cfg
+	readOnly.DatabaseURL = "file:" + cfg.DatabaseURL + "?mode=ro"
+	out.Reset()
+	if err := Migrate(&readOnly, []string{"status"}, &out); err != nil || !strings.Contains(out.String(), "0 pending") {
+		t.Errorf("migrate status on a read-only database = %q, %v; want nothing pending", out.String(), err)
+	}
+}
@@ -3,14 +3,52 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 // Config holds the application configuration
 type Config struct {
 	Port        string
 	DatabaseURL string
-	JWTSecret   string
-	Environment string
+	// DatabaseReplicaURL optionally points read-only queries at a replica
+	DatabaseReplicaURL string
+	JWTSecret          string
+	Environment        string
+	MigrationsDir      string // Optional directory overriding the embedded migrations
+	// MigrationDriftPolicy is "error" to refuse to start or "warn" to log when
+	// applied migrations were modified or removed
+	MigrationDriftPolicy string
+	// MigrationLockTimeout is how long to wait for another instance to finish
+	// migrating before giving up
+	MigrationLockTimeout time.Duration
+
+	// Connection pool limits
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+
+	// DBRequestTimeout bounds the database work of a single API request;
+	// zero disables the deadline
+	DBRequestTimeout time.Duration
+
+	// SlowQueryThreshold logs repository statements that take at least this
+	// long; zero disables the slow-query log
+	SlowQueryThreshold time.Duration
+
+	// PublishInterval is the longest the publisher waits before checking
+	// for scheduled articles that are due
+	PublishInterval time.Duration
+
+	// SQLite connection settings, applied to every pooled connection
+	SQLiteJournalMode string
+	SQLiteBusyTimeout time.Duration
+	SQLiteSynchronous string
+
+	// AdminToken enables the /api/admin endpoints when set; requests must
+	// send it as a Bearer token
+	AdminToken string
 }
 
 // Load loads configuration from environment variables
@@ -18,8 +56,47 @@ func Load() (*Config, error) {
 	cfg := &Config{
 		Port:        getEnv("PORT", "8080"),
 		DatabaseURL: buildDatabaseURL(),
-		JWTSecret:   getEnv("JWT_SECRET", "your-secret-key"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+
+		DatabaseReplicaURL: getEnv("DATABASE_REPLICA_URL", ""),
+		JWTSecret:          getEnv("JWT_SECRET", "your-secret-key"),
+		Environment:        getEnv("ENVIRONMENT", "development"),
+		MigrationsDir:      getEnv("MIGRATIONS_DIR", ""),
+
+		MigrationDriftPolicy: getEnv("MIGRATION_DRIFT_POLICY", "error"),
+
+		SQLiteJournalMode: getEnv("SQLITE_JOURNAL_MODE", "WAL"),
+		SQLiteSynchronous: getEnv("SQLITE_SYNCHRONOUS", "NORMAL"),
+
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
+	}
+
+	var err error
+	if cfg.MigrationLockTimeout, err = getDurationEnv("MIGRATION_LOCK_TIMEOUT", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.DBMaxOpenConns, err = getIntEnv("DB_MAX_OPEN_CONNS", 25); err != nil {
+		return nil, err
+	}
+	if cfg.DBMaxIdleConns, err = getIntEnv("DB_MAX_IDLE_CONNS", 25); err != nil {
+		return nil, err
+	}
+	if cfg.DBConnMaxLifetime, err = getDurationEnv("DB_CONN_MAX_LIFETIME", 30*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.DBConnMaxIdleTime, err = getDurationEnv("DB_CONN_MAX_IDLE_TIME", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.DBRequestTimeout, err = getDurationEnv("DB_REQUEST_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.SlowQueryThreshold, err = getDurationEnv("SLOW_QUERY_THRESHOLD", 200*time.Millisecond); err != nil {
+		return nil, err
+	}
+	if cfg.PublishInterval, err = getDurationEnv("PUBLISH_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.SQLiteBusyTimeout, err = getDurationEnv("SQLITE_BUSY_TIMEOUT", 5*time.Second); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
@@ -55,3 +132,31 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getIntEnv parses an integer from an environment variable
+func getIntEnv(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return number, nil
+}
+
+// getDurationEnv parses a duration such as "30s" from an environment variable
+func getDurationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return duration, nil
+}
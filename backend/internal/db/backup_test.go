@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestDatabase opens and migrates a SQLite file with the project migrations
+func newTestDatabase(t *testing.T, path string) *Database {
+	t.Helper()
+
+	database, err := Open("sqlite3", path, SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := database.Migrate(); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	return database
+}
+
+// countUsers returns the number of rows in the users table
+func countUsers(t *testing.T, database *Database) int {
+	t.Helper()
+
+	var count int
+	if err := database.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		t.Fatalf("Failed to count users: %v", err)
+	}
+	return count
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	source := newTestDatabase(t, filepath.Join(dir, "source.db"))
+
+	if _, err := source.Exec("INSERT INTO users (email, username, password_hash) VALUES ('jake@example.com', 'jake', 'hash')"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	snapshot := filepath.Join(dir, "snapshot.db")
+	if err := source.Backup(context.Background(), snapshot); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if err := source.Backup(context.Background(), snapshot); err == nil {
+		t.Error("Expected Backup() to refuse to overwrite an existing file")
+	}
+
+	// Changes after the backup are not part of the snapshot
+	if _, err := source.Exec("INSERT INTO users (email, username, password_hash) VALUES ('jane@example.com', 'jane', 'hash')"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	target := newTestDatabase(t, filepath.Join(dir, "target.db"))
+	if err := target.Restore(context.Background(), snapshot); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if got := countUsers(t, target); got != 1 {
+		t.Errorf("restored users = %d, want 1", got)
+	}
+	status, err := target.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus() error = %v", err)
+	}
+	if status.Count(MigrationPending) != 0 || status.Drifted() {
+		t.Errorf("restored migration status = %+v, want all applied", status.Migrations)
+	}
+}
+
+func TestBackupAndRestoreEscapePaths(t *testing.T) {
+	dir := t.TempDir()
+	source := newTestDatabase(t, filepath.Join(dir, "source.db"))
+
+	if _, err := source.Exec("INSERT INTO users (email, username, password_hash) VALUES ('jake@example.com', 'jake', 'hash')"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	snapshot := filepath.Join(dir, "snap?mode=memory#1 %41.db")
+	if err := source.Backup(context.Background(), snapshot); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if _, err := os.Stat(snapshot); err != nil {
+		t.Fatalf("Backup() did not write %q: %v", snapshot, err)
+	}
+
+	target := newTestDatabase(t, filepath.Join(dir, "target.db"))
+	if err := target.Restore(context.Background(), snapshot); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := countUsers(t, target); got != 1 {
+		t.Errorf("restored users = %d, want 1", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to list %s: %v", dir, err)
+	}
+	// Only the databases and their WAL files: nothing at a truncated path
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "source.db") && !strings.HasPrefix(name, "target.db") && !strings.HasPrefix(name, filepath.Base(snapshot)) {
+			t.Errorf("unexpected file %q next to the snapshot", name)
+		}
+	}
+}
+
+func TestRestoreRejectsDriftedSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	source := newTestDatabase(t, filepath.Join(dir, "source.db"))
+
+	// Record a migration this binary does not have
+	if _, err := source.Exec("INSERT INTO migrations (id, filename, checksum) VALUES (999, '999_from_the_future.sql', 'abc')"); err != nil {
+		t.Fatalf("Failed to record migration: %v", err)
+	}
+
+	snapshot := filepath.Join(dir, "snapshot.db")
+	if err := source.Backup(context.Background(), snapshot); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	target := newTestDatabase(t, filepath.Join(dir, "target.db"))
+	if _, err := target.Exec("INSERT INTO users (email, username, password_hash) VALUES ('jake@example.com', 'jake', 'hash')"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	err := target.Restore(context.Background(), snapshot)
+	if err == nil || !strings.Contains(err.Error(), "999_from_the_future.sql") {
+		t.Fatalf("Restore() error = %v, want drift error", err)
+	}
+	if got := countUsers(t, target); got != 1 {
+		t.Errorf("users after rejected restore = %d, want 1 (unchanged)", got)
+	}
+}
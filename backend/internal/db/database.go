@@ -3,62 +3,188 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"strings"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/migrations"
 )
 
-// Database wraps the database connection and provides helper methods
+// Database wraps the database connection and provides helper methods.
+// The embedded *sql.DB is the primary; reads that tolerate replication lag
+// can go to an optional read replica through Reader.
 type Database struct {
 	*sql.DB
+	replica          *sql.DB
+	driverName       string
+	options          Options
+	dialect          Dialect
 	migrationManager *MigrationManager
+	migrations       fs.FS
+	queryStats       *QueryStats
 }
 
 // NewDatabase creates a new database connection
-func NewDatabase(databaseURL string) (*Database, error) {
-	var db *sql.DB
-	var err error
-
+func NewDatabase(databaseURL string, options Options) (*Database, error) {
 	// Determine database type based on URL
-	if strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://") {
-		// PostgreSQL connection
-		db, err = sql.Open("postgres", databaseURL)
-	} else {
-		// SQLite connection (default) - only available in dev/sqlite builds
-		db, err = sql.Open("sqlite3", databaseURL)
+	dialect := DialectForURL(databaseURL)
+
+	driverName := "sqlite3"
+	if dialect == Postgres {
+		driverName = "postgres"
+	}
+
+	return OpenWithOptions(driverName, databaseURL, dialect, options)
+}
+
+// Open opens a database with an explicit driver and dialect using DefaultOptions
+func Open(driverName, dataSourceName string, dialect Dialect) (*Database, error) {
+	return OpenWithOptions(driverName, dataSourceName, dialect, DefaultOptions())
+}
+
+// OpenWithOptions opens a database with an explicit driver, dialect and
+// connection settings
+func OpenWithOptions(driverName, dataSourceName string, dialect Dialect, options Options) (*Database, error) {
+	// Enable foreign key constraints and tuning for every SQLite connection
+	if driverName == "sqlite3" {
+		dataSourceName = sqliteDSN(dataSourceName, options)
 	}
 
+	db, err := sql.Open(driverName, dataSourceName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %v", err)
 	}
+	configurePool(db, options)
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %v", err)
 	}
 
-	// Enable foreign key constraints for SQLite
-	if !strings.HasPrefix(databaseURL, "postgres") {
-		if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
-			return nil, fmt.Errorf("failed to enable foreign keys: %v", err)
-		}
-	}
-
-	migrationManager := NewMigrationManager(db)
+	migrationManager := NewMigrationManager(db, dialect)
 
 	return &Database{
 		DB:               db,
+		driverName:       driverName,
+		options:          options,
+		dialect:          dialect,
 		migrationManager: migrationManager,
+		migrations:       migrations.FS,
 	}, nil
 }
 
+// ConnectReplica opens a read replica with the same driver and settings as
+// the primary. Queries routed through Reader use it from then on.
+func (d *Database) ConnectReplica(dataSourceName string) error {
+	if d.driverName == "sqlite3" {
+		dataSourceName = sqliteDSN(dataSourceName, d.options)
+	}
+
+	replica, err := sql.Open(d.driverName, dataSourceName)
+	if err != nil {
+		return fmt.Errorf("failed to open replica: %v", err)
+	}
+	configurePool(replica, d.options)
+
+	if err := replica.Ping(); err != nil {
+		replica.Close()
+		return fmt.Errorf("failed to ping replica: %v", err)
+	}
+
+	if d.replica != nil {
+		d.replica.Close()
+	}
+	d.replica = replica
+	return nil
+}
+
+// Reader returns the connection for read-only queries: the replica when one
+// is connected, otherwise the primary. Reads that must observe the caller's
+// own writes should use the primary instead.
+func (d *Database) Reader() *sql.DB {
+	if d.replica != nil {
+		return d.replica
+	}
+	return d.DB
+}
+
+// SetQueryStats records the statements run through Primary and ReadOnly in
+// stats. Connections handed out before the call are not affected.
+func (d *Database) SetQueryStats(stats *QueryStats) {
+	d.queryStats = stats
+}
+
+// QueryStats returns the statement recorder, or nil when none is set
+func (d *Database) QueryStats() *QueryStats {
+	return d.queryStats
+}
+
+// Primary returns the primary for the repositories, instrumented when query
+// stats are enabled
+func (d *Database) Primary() Conn {
+	return Instrument(d.DB, d.queryStats)
+}
+
+// ReadOnly returns Reader for the repositories, instrumented when query
+// stats are enabled
+func (d *Database) ReadOnly() Conn {
+	return Instrument(d.Reader(), d.queryStats)
+}
+
+// Dialect returns the SQL dialect of the connected database
+func (d *Database) Dialect() Dialect {
+	return d.dialect
+}
+
+// SetMigrationsDir replaces the embedded migrations with the SQL files in dir,
+// allowing hotfixed migrations to be shipped without rebuilding the binary
+func (d *Database) SetMigrationsDir(dir string) {
+	d.migrations = os.DirFS(dir)
+}
+
+// SetDriftPolicy sets how modified or missing applied migrations are handled
+func (d *Database) SetDriftPolicy(policy DriftPolicy) {
+	d.migrationManager.SetDriftPolicy(policy)
+}
+
+// SetMigrationLockTimeout sets how long Migrate waits for another process
+// holding the migration lock
+func (d *Database) SetMigrationLockTimeout(timeout time.Duration) {
+	d.migrationManager.SetLockTimeout(timeout)
+}
+
+// SetDryRun makes Migrate, Rollback and MigrateTo print the SQL they would
+// execute to out instead of running it
+func (d *Database) SetDryRun(out io.Writer) {
+	d.migrationManager.SetDryRun(out)
+}
+
+// MigrationStatus reports applied, pending and drifted migrations
+func (d *Database) MigrationStatus() (*MigrationStatus, error) {
+	return d.migrationManager.Status(d.migrations)
+}
+
 // Migrate runs database migrations
 func (d *Database) Migrate() error {
-	// Use absolute path for migrations directory
-	migrationsDir := "migrations"
+	return d.migrationManager.RunMigrations(d.migrations)
+}
 
-	return d.migrationManager.RunMigrations(migrationsDir)
+// Rollback reverts the given number of most recently applied migrations
+func (d *Database) Rollback(steps int) error {
+	return d.migrationManager.Rollback(d.migrations, steps)
 }
 
-// Close closes the database connection
+// MigrateTo migrates the schema up or down to the given version
+func (d *Database) MigrateTo(version int) error {
+	return d.migrationManager.MigrateTo(d.migrations, version)
+}
+
+// Close closes the database connections
 func (d *Database) Close() error {
+	if d.replica != nil {
+		d.replica.Close()
+	}
 	return d.DB.Close()
 }
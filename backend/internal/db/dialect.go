@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Executor is the subset of *sql.DB and *sql.Tx used by the repositories
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Dialect captures the SQL differences between the supported databases.
+// Repositories write queries with "?" placeholders and let the dialect
+// translate them for the driver in use.
+type Dialect interface {
+	// Name returns the dialect identifier ("sqlite" or "postgres")
+	Name() string
+
+	// Rebind rewrites "?" placeholders into the dialect's bind syntax
+	Rebind(query string) string
+
+	// InsertReturningID executes an INSERT statement and returns the generated id
+	InsertReturningID(ctx context.Context, exec Executor, query string, args ...interface{}) (int64, error)
+}
+
+// Supported dialects
+var (
+	SQLite   Dialect = sqliteDialect{}
+	Postgres Dialect = postgresDialect{}
+)
+
+// DialectForURL returns the dialect matching a database URL
+func DialectForURL(databaseURL string) Dialect {
+	if strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://") {
+		return Postgres
+	}
+	return SQLite
+}
+
+// sqliteDialect uses "?" placeholders and LastInsertId
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (sqliteDialect) Rebind(query string) string {
+	return query
+}
+
+func (sqliteDialect) InsertReturningID(ctx context.Context, exec Executor, query string, args ...interface{}) (int64, error) {
+	result, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// postgresDialect uses "$n" placeholders and RETURNING id, since lib/pq
+// does not support LastInsertId
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (postgresDialect) Rebind(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	inQuote := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			inQuote = !inQuote
+			b.WriteByte(c)
+		case c == '?' && !inQuote:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+func (d postgresDialect) InsertReturningID(ctx context.Context, exec Executor, query string, args ...interface{}) (int64, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), ";") + " RETURNING id"
+
+	var id int64
+	if err := exec.QueryRowContext(ctx, d.Rebind(query), args...).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,5 @@
+package db
+
+import (
+	_ "github.com/lib/pq"
+)
@@ -0,0 +1,287 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// statsSampleSize is how many recent timings are kept per statement for
+	// the percentiles
+	statsSampleSize = 1024
+
+	// statsMaxStatements bounds the number of distinct statements tracked;
+	// further statements are counted under otherStatements
+	statsMaxStatements = 500
+	otherStatements    = "(other statements)"
+)
+
+// Tx is a transaction the repositories can run statements in
+type Tx interface {
+	Executor
+	Commit() error
+	Rollback() error
+}
+
+// Conn is a connection pool the repositories can run statements and begin
+// transactions on
+type Conn interface {
+	Executor
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
+}
+
+// QueryStats records the timing of every statement run through an
+// instrumented connection and logs the slow ones
+type QueryStats struct {
+	slowThreshold time.Duration
+
+	mu         sync.Mutex
+	statements map[string]*statementTimings
+}
+
+// statementTimings accumulates the timings of one statement
+type statementTimings struct {
+	calls   int64
+	errors  int64
+	total   time.Duration
+	max     time.Duration
+	samples []time.Duration // ring buffer of the most recent timings
+	next    int
+}
+
+// StatementStats summarizes the timings of one statement
+type StatementStats struct {
+	Query  string
+	Calls  int64
+	Errors int64
+	Total  time.Duration
+	Max    time.Duration
+	P50    time.Duration
+	P95    time.Duration
+}
+
+// NewQueryStats creates a recorder that logs statements taking at least
+// slowThreshold; zero disables the slow-query log
+func NewQueryStats(slowThreshold time.Duration) *QueryStats {
+	return &QueryStats{
+		slowThreshold: slowThreshold,
+		statements:    make(map[string]*statementTimings),
+	}
+}
+
+// Record adds one execution of query to the stats
+func (s *QueryStats) Record(query string, args int, elapsed time.Duration, err error) {
+	query = normalizeQuery(query)
+
+	if s.slowThreshold > 0 && elapsed >= s.slowThreshold {
+		log.Printf("Slow query took %v (%d args): %s", elapsed, args, query)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	timings, ok := s.statements[query]
+	if !ok {
+		if len(s.statements) >= statsMaxStatements {
+			query = otherStatements
+			timings = s.statements[query]
+		}
+		if timings == nil {
+			timings = &statementTimings{}
+			s.statements[query] = timings
+		}
+	}
+
+	timings.calls++
+	if err != nil && err != sql.ErrNoRows {
+		timings.errors++
+	}
+	timings.total += elapsed
+	if elapsed > timings.max {
+		timings.max = elapsed
+	}
+	if len(timings.samples) < statsSampleSize {
+		timings.samples = append(timings.samples, elapsed)
+	} else {
+		timings.samples[timings.next] = elapsed
+		timings.next = (timings.next + 1) % statsSampleSize
+	}
+}
+
+// Snapshot returns the stats of every statement, most total time first
+func (s *QueryStats) Snapshot() []StatementStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]StatementStats, 0, len(s.statements))
+	for query, timings := range s.statements {
+		samples := append([]time.Duration(nil), timings.samples...)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+		stats = append(stats, StatementStats{
+			Query:  query,
+			Calls:  timings.calls,
+			Errors: timings.errors,
+			Total:  timings.total,
+			Max:    timings.max,
+			P50:    percentile(samples, 50),
+			P95:    percentile(samples, 95),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Query < stats[j].Query
+	})
+	return stats
+}
+
+// Reset discards every recorded timing
+func (s *QueryStats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statements = make(map[string]*statementTimings)
+}
+
+// percentile returns the nearest-rank percentile p of sorted samples
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// normalizeQuery collapses whitespace so the same statement written with
+// different indentation is counted once
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// Instrument returns conn with every statement recorded in stats. A nil
+// stats returns a Conn that runs statements unrecorded.
+func Instrument(conn *sql.DB, stats *QueryStats) Conn {
+	return &instrumentedDB{db: conn, stats: stats}
+}
+
+// instrumentedDB records the statements run on a connection pool
+type instrumentedDB struct {
+	db    *sql.DB
+	stats *QueryStats
+}
+
+// ExecContext runs a statement that returns no rows
+func (i *instrumentedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return recordExec(i.stats, query, args, func() (sql.Result, error) {
+		return i.db.ExecContext(ctx, query, args...)
+	})
+}
+
+// QueryContext runs a statement that returns rows
+func (i *instrumentedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return recordQuery(i.stats, query, args, func() (*sql.Rows, error) {
+		return i.db.QueryContext(ctx, query, args...)
+	})
+}
+
+// QueryRowContext runs a statement that returns at most one row
+func (i *instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return recordQueryRow(i.stats, query, args, func() *sql.Row {
+		return i.db.QueryRowContext(ctx, query, args...)
+	})
+}
+
+// BeginTx begins a transaction whose statements are recorded too
+func (i *instrumentedDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := i.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTx{tx: tx, stats: i.stats}, nil
+}
+
+// instrumentedTx records the statements run in a transaction
+type instrumentedTx struct {
+	tx    *sql.Tx
+	stats *QueryStats
+}
+
+// ExecContext runs a statement that returns no rows
+func (i *instrumentedTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return recordExec(i.stats, query, args, func() (sql.Result, error) {
+		return i.tx.ExecContext(ctx, query, args...)
+	})
+}
+
+// QueryContext runs a statement that returns rows
+func (i *instrumentedTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return recordQuery(i.stats, query, args, func() (*sql.Rows, error) {
+		return i.tx.QueryContext(ctx, query, args...)
+	})
+}
+
+// QueryRowContext runs a statement that returns at most one row
+func (i *instrumentedTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return recordQueryRow(i.stats, query, args, func() *sql.Row {
+		return i.tx.QueryRowContext(ctx, query, args...)
+	})
+}
+
+// Commit commits the transaction
+func (i *instrumentedTx) Commit() error {
+	return i.tx.Commit()
+}
+
+// Rollback aborts the transaction
+func (i *instrumentedTx) Rollback() error {
+	return i.tx.Rollback()
+}
+
+// recordExec times an Exec call
+func recordExec(stats *QueryStats, query string, args []interface{}, run func() (sql.Result, error)) (sql.Result, error) {
+	if stats == nil {
+		return run()
+	}
+
+	start := time.Now()
+	result, err := run()
+	stats.Record(query, len(args), time.Since(start), err)
+	return result, err
+}
+
+// recordQuery times a Query call up to the first row being available;
+// iterating the rows is not included
+func recordQuery(stats *QueryStats, query string, args []interface{}, run func() (*sql.Rows, error)) (*sql.Rows, error) {
+	if stats == nil {
+		return run()
+	}
+
+	start := time.Now()
+	rows, err := run()
+	stats.Record(query, len(args), time.Since(start), err)
+	return rows, err
+}
+
+// recordQueryRow times a QueryRow call
+func recordQueryRow(stats *QueryStats, query string, args []interface{}, run func() *sql.Row) *sql.Row {
+	if stats == nil {
+		return run()
+	}
+
+	start := time.Now()
+	row := run()
+	stats.Record(query, len(args), time.Since(start), row.Err())
+	return row
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryStatsPercentiles(t *testing.T) {
+	stats := NewQueryStats(0)
+	for i := 1; i <= 100; i++ {
+		stats.Record("SELECT 1", 0, time.Duration(i)*time.Millisecond, nil)
+	}
+	stats.Record("SELECT\n\t\t2", 1, time.Second, nil)
+
+	snapshot := stats.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("Snapshot() = %d statements, want 2", len(snapshot))
+	}
+
+	// Most total time first, with whitespace collapsed
+	if snapshot[0].Query != "SELECT 1" || snapshot[1].Query != "SELECT 2" {
+		t.Errorf("Snapshot() queries = %q, %q; want SELECT 1, SELECT 2", snapshot[0].Query, snapshot[1].Query)
+	}
+
+	first := snapshot[0]
+	if first.Calls != 100 || first.P50 != 50*time.Millisecond || first.P95 != 95*time.Millisecond || first.Max != 100*time.Millisecond {
+		t.Errorf("Snapshot()[0] = %+v, want 100 calls, p50 50ms, p95 95ms, max 100ms", first)
+	}
+	if snapshot[1].P50 != time.Second || snapshot[1].P95 != time.Second {
+		t.Errorf("Snapshot()[1] = %+v, want p50 and p95 of its only call", snapshot[1])
+	}
+
+	stats.Reset()
+	if got := stats.Snapshot(); len(got) != 0 {
+		t.Errorf("Snapshot() after Reset() = %v, want empty", got)
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	stats := NewQueryStats(10 * time.Millisecond)
+	stats.Record("SELECT fast FROM t WHERE a = ?", 1, time.Millisecond, nil)
+	stats.Record("SELECT slow FROM t WHERE a = ? AND b = ?", 2, 20*time.Millisecond, nil)
+
+	output := buf.String()
+	if strings.Contains(output, "fast") {
+		t.Errorf("log = %q, want fast statement not logged", output)
+	}
+	if !strings.Contains(output, "(2 args): SELECT slow FROM t WHERE a = ? AND b = ?") {
+		t.Errorf("log = %q, want slow statement with its argument count", output)
+	}
+}
+
+func TestInstrumentedConn(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDatabase(t, filepath.Join(t.TempDir(), "test.db"))
+	stats := NewQueryStats(0)
+	database.SetQueryStats(stats)
+	conn := database.Primary()
+
+	if _, err := conn.ExecContext(ctx, "INSERT INTO users (email, username, password_hash) VALUES (?, ?, ?)", "a@example.com", "a", "hash"); err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		t.Fatalf("QueryRowContext() error = %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO nowhere VALUES (1)"); err == nil {
+		t.Fatal("Expected inserting into a missing table to fail")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	recorded := map[string]StatementStats{}
+	for _, statement := range stats.Snapshot() {
+		recorded[statement.Query] = statement
+	}
+	for _, query := range []string{
+		"INSERT INTO users (email, username, password_hash) VALUES (?, ?, ?)",
+		"SELECT COUNT(*) FROM users",
+	} {
+		if recorded[query].Calls != 1 || recorded[query].Errors != 0 {
+			t.Errorf("stats for %q = %+v, want one successful call", query, recorded[query])
+		}
+	}
+	if recorded["INSERT INTO nowhere VALUES (1)"].Errors != 1 {
+		t.Errorf("stats for failed insert = %+v, want one error", recorded["INSERT INTO nowhere VALUES (1)"])
+	}
+
+	// Statements through an uninstrumented database are not recorded
+	database.SetQueryStats(nil)
+	if _, err := database.Primary().ExecContext(ctx, "DELETE FROM users"); err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+	if len(stats.Snapshot()) != 3 {
+		t.Errorf("Snapshot() = %d statements, want 3", len(stats.Snapshot()))
+	}
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// DefaultMigrationLockTimeout is how long to wait for another process to finish migrating
+	DefaultMigrationLockTimeout = time.Minute
+
+	// migrationAdvisoryLockID is the pg_advisory_lock key guarding migrations
+	migrationAdvisoryLockID = 7217421501
+
+	// lockPollInterval is the delay between lock attempts
+	lockPollInterval = 100 * time.Millisecond
+
+	// staleLockAge is the age after which a SQLite lock row left behind by a
+	// crashed process is taken over. The holder refreshes the row every
+	// lockHeartbeatInterval and as each migration commits, so only a crashed
+	// process lets it grow this old, and the others wait well under
+	// DefaultMigrationLockTimeout for it.
+	staleLockAge = 30 * time.Second
+)
+
+// lockHeartbeatInterval is how often the holder of the SQLite migration lock
+// refreshes it
+var lockHeartbeatInterval = 5 * time.Second
+
+// ErrMigrationLockTimeout is returned when the migration lock cannot be acquired in time
+var ErrMigrationLockTimeout = errors.New("timed out waiting for migration lock")
+
+// SetLockTimeout sets how long to wait for the migration lock
+func (m *MigrationManager) SetLockTimeout(timeout time.Duration) {
+	m.lockTimeout = timeout
+}
+
+// withLock runs fn while holding the cross-process migration lock, so that
+// replicas starting at the same time apply each migration exactly once. A
+// dry run changes nothing, so it runs without taking the lock.
+func (m *MigrationManager) withLock(fn func() error) error {
+	if m.dryRun != nil {
+		return fn()
+	}
+
+	release, err := m.acquireLock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return fn()
+}
+
+// acquireLock takes the migration lock and returns a function releasing it
+func (m *MigrationManager) acquireLock() (func(), error) {
+	if m.dialect == Postgres {
+		return m.acquireAdvisoryLock()
+	}
+	return m.acquireLockRow()
+}
+
+// acquireAdvisoryLock takes a session-level PostgreSQL advisory lock on a
+// dedicated connection, which is held until released
+func (m *MigrationManager) acquireAdvisoryLock() (func(), error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.lockTimeout)
+	defer cancel()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for migration lock: %v", err)
+	}
+
+	for {
+		// Only a lock held by another session is waited out
+		var locked bool
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationAdvisoryLockID).Scan(&locked)
+		if err != nil && ctx.Err() == nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if locked {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return nil, fmt.Errorf("%w after %v", ErrMigrationLockTimeout, m.lockTimeout)
+		case <-time.After(lockPollInterval):
+		}
+	}
+
+	release := func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationAdvisoryLockID)
+		conn.Close()
+	}
+
+	return release, nil
+}
+
+// acquireLockRow takes the SQLite migration lock by inserting the single row
+// of the migration_lock table; the primary key lets only one process succeed
+func (m *MigrationManager) acquireLockRow() (func(), error) {
+	query := `
+	CREATE TABLE IF NOT EXISTS migration_lock (
+		id INTEGER PRIMARY KEY,
+		owner TEXT NOT NULL,
+		locked_at TIMESTAMP NOT NULL
+	);
+	`
+	if _, err := m.db.Exec(query); err != nil {
+		return nil, fmt.Errorf("failed to create migration lock table: %v", err)
+	}
+
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d:%d", hostname, os.Getpid(), time.Now().UnixNano())
+	deadline := time.Now().Add(m.lockTimeout)
+
+	for {
+		// Only attempt the write when the lock looks free, keeping write
+		// contention with the migrating process to a minimum
+		var held int
+		err := m.db.QueryRow("SELECT COUNT(*) FROM migration_lock WHERE id = 1").Scan(&held)
+		if err == nil && held == 0 {
+			_, err = m.db.Exec("INSERT INTO migration_lock (id, owner, locked_at) VALUES (1, ?, ?)", owner, time.Now().UTC())
+			if err == nil {
+				break
+			}
+		}
+		if err != nil && !lockContended(err) {
+			return nil, fmt.Errorf("failed to acquire migration lock: %v", err)
+		}
+
+		// Take over locks abandoned by crashed processes
+		_, err = m.db.Exec("DELETE FROM migration_lock WHERE id = 1 AND locked_at < ?", time.Now().UTC().Add(-staleLockAge))
+		if err != nil && !lockContended(err) {
+			return nil, fmt.Errorf("failed to take over stale migration lock: %v", err)
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w after %v", ErrMigrationLockTimeout, m.lockTimeout)
+		}
+		time.Sleep(lockPollInterval)
+	}
+
+	// Refresh the lock until it is released. While a migration holds the
+	// database's write lock the refresh waits, and the migration refreshes
+	// the lock itself as it commits.
+	m.lockOwner = owner
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lockHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				m.db.Exec("UPDATE migration_lock SET locked_at = ? WHERE id = 1 AND owner = ?", time.Now().UTC(), owner)
+			}
+		}
+	}()
+
+	release := func() {
+		close(stop)
+		<-stopped
+		m.lockOwner = ""
+		m.db.Exec("DELETE FROM migration_lock WHERE id = 1 AND owner = ?", owner)
+	}
+
+	return release, nil
+}
+
+// refreshLock marks the SQLite migration lock as still held from within a
+// migration's transaction; it does nothing when no lock row is held
+func (m *MigrationManager) refreshLock(tx execer) error {
+	if m.lockOwner == "" {
+		return nil
+	}
+
+	query := "UPDATE migration_lock SET locked_at = ? WHERE id = 1 AND owner = ?"
+	if _, err := tx.ExecContext(context.Background(), query, time.Now().UTC(), m.lockOwner); err != nil {
+		return fmt.Errorf("failed to refresh migration lock: %v", err)
+	}
+	return nil
+}
+
+// lockContended reports whether err comes from another process holding the
+// lock row or the database, which is worth retrying; any other error is
+// returned at once
+func lockContended(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code {
+	case sqlite3.ErrConstraint, sqlite3.ErrBusy, sqlite3.ErrLocked:
+		return true
+	}
+	return false
+}
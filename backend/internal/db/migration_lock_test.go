@@ -0,0 +1,220 @@
+package db
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/migrations"
+)
+
+func TestConcurrentMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.db")
+	const replicas = 5
+
+	// Each replica opens its own connection pool, as separate processes would
+	managers := make([]*MigrationManager, replicas)
+	for i := range managers {
+		database, err := Open("sqlite3", path, SQLite)
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		defer database.Close()
+		managers[i] = database.migrationManager
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, replicas)
+	for _, m := range managers {
+		wg.Add(1)
+		go func(m *MigrationManager) {
+			defer wg.Done()
+			errs <- m.RunMigrations(migrations.FS)
+		}(m)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("RunMigrations() error = %v", err)
+		}
+	}
+
+	all, err := managers[0].LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	applied, err := managers[0].GetAppliedMigrations()
+	if err != nil {
+		t.Fatalf("GetAppliedMigrations() error = %v", err)
+	}
+	if len(applied) != len(all) {
+		t.Errorf("applied %d migrations, want %d exactly once", len(applied), len(all))
+	}
+
+	var locks int
+	if err := managers[0].db.QueryRow("SELECT COUNT(*) FROM migration_lock").Scan(&locks); err != nil {
+		t.Fatalf("Failed to read lock table: %v", err)
+	}
+	if locks != 0 {
+		t.Errorf("Expected lock to be released, found %d lock rows", locks)
+	}
+}
+
+func TestMigrationLockTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.db")
+
+	holder, err := Open("sqlite3", path, SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer holder.Close()
+
+	waiter, err := Open("sqlite3", path, SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer waiter.Close()
+
+	release, err := holder.migrationManager.acquireLock()
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+
+	waiter.migrationManager.SetLockTimeout(300 * time.Millisecond)
+	err = waiter.migrationManager.RunMigrations(migrations.FS)
+	if !errors.Is(err, ErrMigrationLockTimeout) {
+		t.Fatalf("RunMigrations() error = %v, want ErrMigrationLockTimeout", err)
+	}
+
+	release()
+	if err := waiter.migrationManager.RunMigrations(migrations.FS); err != nil {
+		t.Errorf("RunMigrations() after release error = %v", err)
+	}
+}
+
+func TestStaleMigrationLockIsTakenOver(t *testing.T) {
+	m := newTestMigrationManager(t)
+
+	release, err := m.acquireLock()
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+	defer release()
+
+	// Age the lock as if its owner crashed long ago
+	if _, err := m.db.Exec("UPDATE migration_lock SET locked_at = ?", time.Now().UTC().Add(-2*staleLockAge)); err != nil {
+		t.Fatalf("Failed to age lock: %v", err)
+	}
+
+	m.SetLockTimeout(time.Second)
+	if err := m.RunMigrations(migrationFS(t, reversibleMigrations)); err != nil {
+		t.Errorf("RunMigrations() with stale lock error = %v", err)
+	}
+}
+
+// lockAge returns how long ago the SQLite migration lock was last refreshed
+func lockAge(t *testing.T, m *MigrationManager) time.Duration {
+	t.Helper()
+
+	var lockedAt time.Time
+	if err := m.db.QueryRow("SELECT locked_at FROM migration_lock WHERE id = 1").Scan(&lockedAt); err != nil {
+		t.Fatalf("Failed to read lock: %v", err)
+	}
+	return time.Since(lockedAt)
+}
+
+func TestMigrationLockIsKeptFresh(t *testing.T) {
+	defer func(interval time.Duration) { lockHeartbeatInterval = interval }(lockHeartbeatInterval)
+	lockHeartbeatInterval = 10 * time.Millisecond
+
+	m := newTestMigrationManager(t)
+	ageLock := func() {
+		t.Helper()
+		if _, err := m.db.Exec("UPDATE migration_lock SET locked_at = ?", time.Now().UTC().Add(-2*staleLockAge)); err != nil {
+			t.Fatalf("Failed to age lock: %v", err)
+		}
+	}
+
+	// Each migration refreshes the lock as it commits
+	err := m.withLock(func() error {
+		ageLock()
+		if err := m.runMigrations(migrationFS(t, reversibleMigrations)); err != nil {
+			return err
+		}
+		if age := lockAge(t, m); age > staleLockAge/2 {
+			t.Errorf("lock age after migrating = %v, want it refreshed", age)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withLock() error = %v", err)
+	}
+
+	// The heartbeat refreshes it in between
+	release, err := m.acquireLock()
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+	defer release()
+	ageLock()
+	deadline := time.Now().Add(time.Second)
+	for lockAge(t, m) > staleLockAge/2 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the heartbeat to refresh the lock")
+		}
+		time.Sleep(lockHeartbeatInterval)
+	}
+}
+
+func TestMigrationLockErrorsAreNotRetried(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "readonly.db")
+
+	// Create the lock table, then migrate through a read-only connection
+	writable, err := Open("sqlite3", path, SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	release, err := writable.migrationManager.acquireLock()
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+	release()
+	writable.Close()
+
+	readOnly, err := Open("sqlite3", "file:"+path+"?mode=ro", SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer readOnly.Close()
+
+	readOnly.migrationManager.SetLockTimeout(time.Minute)
+	start := time.Now()
+	_, err = readOnly.migrationManager.acquireLock()
+	if err == nil || errors.Is(err, ErrMigrationLockTimeout) {
+		t.Fatalf("acquireLock() on a read-only database error = %v, want the write error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("acquireLock() took %v to fail, want no retries", elapsed)
+	}
+}
+
+func TestAdvisoryLockErrorsAreNotRetried(t *testing.T) {
+	// SQLite has no advisory locks, so every attempt fails as a broken
+	// PostgreSQL connection would
+	m := NewMigrationManager(newTestMigrationManager(t).db, Postgres)
+	m.SetLockTimeout(time.Minute)
+
+	start := time.Now()
+	_, err := m.acquireLock()
+	if err == nil || errors.Is(err, ErrMigrationLockTimeout) || !strings.Contains(err.Error(), "pg_try_advisory_lock") {
+		t.Fatalf("acquireLock() error = %v, want the query error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("acquireLock() took %v to fail, want no retries", elapsed)
+	}
+}
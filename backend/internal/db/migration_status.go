@@ -0,0 +1,259 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DriftPolicy controls what happens when applied migrations no longer match
+// the migration files
+type DriftPolicy string
+
+const (
+	// DriftError refuses to migrate while drift is detected
+	DriftError DriftPolicy = "error"
+	// DriftWarn logs the drift and carries on
+	DriftWarn DriftPolicy = "warn"
+)
+
+// ParseDriftPolicy converts a configuration value into a DriftPolicy
+func ParseDriftPolicy(value string) (DriftPolicy, error) {
+	switch DriftPolicy(strings.ToLower(value)) {
+	case DriftError:
+		return DriftError, nil
+	case DriftWarn:
+		return DriftWarn, nil
+	default:
+		return "", fmt.Errorf("invalid migration drift policy: %s", value)
+	}
+}
+
+// MigrationState describes how a migration file relates to the database
+type MigrationState string
+
+const (
+	MigrationPending  MigrationState = "pending"  // file exists but is not applied
+	MigrationApplied  MigrationState = "applied"  // applied and unchanged
+	MigrationModified MigrationState = "modified" // applied, but the file changed since
+	MigrationMissing  MigrationState = "missing"  // applied, but the file no longer exists
+)
+
+// MigrationStatusEntry reports the state of a single migration
+type MigrationStatusEntry struct {
+	ID        int            `json:"id"`
+	Filename  string         `json:"filename"`
+	State     MigrationState `json:"state"`
+	AppliedAt *time.Time     `json:"appliedAt,omitempty"`
+}
+
+// MigrationStatus reports the state of every known migration
+type MigrationStatus struct {
+	Migrations []MigrationStatusEntry `json:"migrations"`
+}
+
+// Count returns the number of migrations in the given state
+func (s *MigrationStatus) Count(state MigrationState) int {
+	count := 0
+	for _, entry := range s.Migrations {
+		if entry.State == state {
+			count++
+		}
+	}
+	return count
+}
+
+// Drifted reports whether any applied migration was modified or removed
+func (s *MigrationStatus) Drifted() bool {
+	return s.Count(MigrationModified) > 0 || s.Count(MigrationMissing) > 0
+}
+
+// Filenames returns the filenames of migrations in the given state
+func (s *MigrationStatus) Filenames(state MigrationState) []string {
+	filenames := []string{}
+	for _, entry := range s.Migrations {
+		if entry.State == state {
+			filenames = append(filenames, entry.Filename)
+		}
+	}
+	return filenames
+}
+
+// appliedRecord is a row of the migrations table
+type appliedRecord struct {
+	ID        int
+	Filename  string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Status compares the migration files with the migrations table. It only
+// reads the database, so it needs no migration lock: without the table
+// every migration is pending.
+func (m *MigrationManager) Status(fsys fs.FS) (*MigrationStatus, error) {
+	appliedMigrations, err := m.peekAppliedRecords()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %v", err)
+	}
+
+	allMigrations, err := m.LoadMigrations(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %v", err)
+	}
+
+	return buildStatus(allMigrations, appliedMigrations), nil
+}
+
+// Verify checks the applied migrations against fsys without changing the
+// database, applying the drift policy. It fails when the database has no
+// migrations table.
+func (m *MigrationManager) Verify(fsys fs.FS) error {
+	appliedMigrations, err := m.getAppliedRecords()
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %v", err)
+	}
+
+	allMigrations, err := m.LoadMigrations(fsys)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %v", err)
+	}
+
+	return m.checkDrift(buildStatus(allMigrations, appliedMigrations))
+}
+
+// getAppliedRecords returns the rows of the migrations table
+func (m *MigrationManager) getAppliedRecords() ([]appliedRecord, error) {
+	return m.queryAppliedRecords("checksum")
+}
+
+// peekAppliedRecords returns the rows of the migrations table without
+// creating or upgrading it: without the table nothing is applied, and a
+// table from before checksums were tracked has none
+func (m *MigrationManager) peekAppliedRecords() ([]appliedRecord, error) {
+	if _, err := m.db.Exec("SELECT id FROM migrations WHERE 1 = 0"); err != nil {
+		return nil, nil
+	}
+	if _, err := m.db.Exec("SELECT checksum FROM migrations WHERE 1 = 0"); err != nil {
+		return m.queryAppliedRecords("NULL")
+	}
+	return m.queryAppliedRecords("checksum")
+}
+
+// queryAppliedRecords returns the rows of the migrations table, reading
+// checksums from the given column expression
+func (m *MigrationManager) queryAppliedRecords(checksumColumn string) ([]appliedRecord, error) {
+	query := "SELECT id, filename, " + checksumColumn + ", applied_at FROM migrations ORDER BY id ASC"
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []appliedRecord
+	for rows.Next() {
+		var record appliedRecord
+		var checksum sql.NullString
+		if err := rows.Scan(&record.ID, &record.Filename, &checksum, &record.AppliedAt); err != nil {
+			return nil, err
+		}
+		record.Checksum = checksum.String
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// backfillChecksums stores the current checksum for applied migrations that
+// were recorded before checksums existed
+func (m *MigrationManager) backfillChecksums(allMigrations []Migration, applied []appliedRecord) error {
+	byFilename := make(map[string]Migration, len(allMigrations))
+	for _, migration := range allMigrations {
+		byFilename[migration.Filename] = migration
+	}
+
+	for i, record := range applied {
+		migration, ok := byFilename[record.Filename]
+		if record.Checksum != "" || !ok {
+			continue
+		}
+
+		query := m.dialect.Rebind("UPDATE migrations SET checksum = ? WHERE id = ?")
+		if _, err := m.db.Exec(query, migration.Checksum, record.ID); err != nil {
+			return err
+		}
+		applied[i].Checksum = migration.Checksum
+	}
+
+	return nil
+}
+
+// checkDrift applies the drift policy to a migration status
+func (m *MigrationManager) checkDrift(status *MigrationStatus) error {
+	if !status.Drifted() {
+		return nil
+	}
+
+	message := fmt.Sprintf("migration drift detected: modified %v, missing %v",
+		status.Filenames(MigrationModified), status.Filenames(MigrationMissing))
+
+	if m.driftPolicy == DriftWarn {
+		log.Printf("WARNING: %s", message)
+		return nil
+	}
+
+	return fmt.Errorf("%s", message)
+}
+
+// buildStatus merges migration files and applied records into a status report
+func buildStatus(allMigrations []Migration, applied []appliedRecord) *MigrationStatus {
+	appliedByFilename := make(map[string]appliedRecord, len(applied))
+	for _, record := range applied {
+		appliedByFilename[record.Filename] = record
+	}
+
+	status := &MigrationStatus{Migrations: []MigrationStatusEntry{}}
+	seen := make(map[string]bool, len(allMigrations))
+
+	for _, migration := range allMigrations {
+		seen[migration.Filename] = true
+		entry := MigrationStatusEntry{
+			ID:       migration.ID,
+			Filename: migration.Filename,
+			State:    MigrationPending,
+		}
+
+		if record, ok := appliedByFilename[migration.Filename]; ok {
+			appliedAt := record.AppliedAt
+			entry.AppliedAt = &appliedAt
+			entry.State = MigrationApplied
+			if record.Checksum != "" && record.Checksum != migration.Checksum {
+				entry.State = MigrationModified
+			}
+		}
+
+		status.Migrations = append(status.Migrations, entry)
+	}
+
+	for _, record := range applied {
+		if seen[record.Filename] {
+			continue
+		}
+		appliedAt := record.AppliedAt
+		status.Migrations = append(status.Migrations, MigrationStatusEntry{
+			ID:        record.ID,
+			Filename:  record.Filename,
+			State:     MigrationMissing,
+			AppliedAt: &appliedAt,
+		})
+	}
+
+	sort.Slice(status.Migrations, func(i, j int) bool {
+		return status.Migrations[i].ID < status.Migrations[j].ID
+	})
+
+	return status
+}
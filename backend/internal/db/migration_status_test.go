@@ -0,0 +1,162 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMigrationStatus(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrationFS(t, map[string]string{
+		"001_create_widgets.sql": "CREATE TABLE widgets (id INTEGER PRIMARY KEY);\n",
+	})
+
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	fsys.(fstest.MapFS)["002_create_gadgets.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE gadgets (id INTEGER PRIMARY KEY);\n")}
+
+	status, err := m.Status(fsys)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Count(MigrationApplied) != 1 || status.Count(MigrationPending) != 1 {
+		t.Errorf("Status() = %+v, want 1 applied and 1 pending", status.Migrations)
+	}
+	if status.Migrations[0].AppliedAt == nil {
+		t.Error("Expected applied migration to report applied time")
+	}
+	if status.Drifted() {
+		t.Error("Expected no drift")
+	}
+}
+
+func TestMigrationStatusIsReadOnly(t *testing.T) {
+	fsys := migrationFS(t, reversibleMigrations)
+
+	tests := []struct {
+		name    string
+		setup   string
+		applied int
+	}{
+		{"fresh database", "", 0},
+		{
+			// A migrations table from before checksums were tracked
+			name: "legacy migrations table",
+			setup: `CREATE TABLE migrations (id INTEGER PRIMARY KEY, filename TEXT NOT NULL, applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+				INSERT INTO migrations (id, filename) VALUES (1, '001_create_widgets.sql');`,
+			applied: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMigrationManager(t)
+			if tt.setup != "" {
+				if _, err := m.db.Exec(tt.setup); err != nil {
+					t.Fatalf("Failed to set up database: %v", err)
+				}
+			}
+			before := schemaSnapshot(t, m)
+
+			status, err := m.Status(fsys)
+			if err != nil {
+				t.Fatalf("Status() error = %v", err)
+			}
+			if status.Count(MigrationApplied) != tt.applied || status.Count(MigrationPending) != 3-tt.applied || status.Drifted() {
+				t.Errorf("Status() = %+v, want %d applied and the rest pending", status.Migrations, tt.applied)
+			}
+			if after := schemaSnapshot(t, m); after != before {
+				t.Errorf("Status() changed the database:\nbefore:\n%s\nafter:\n%s", before, after)
+			}
+		})
+	}
+}
+
+func TestMigrationDriftDetection(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrationFS(t, map[string]string{
+		"001_create_widgets.sql": "CREATE TABLE widgets (id INTEGER PRIMARY KEY);\n",
+		"002_create_gadgets.sql": "CREATE TABLE gadgets (id INTEGER PRIMARY KEY);\n",
+	})
+
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	// Edit one applied file and delete the other
+	drifted := migrationFS(t, map[string]string{
+		"001_create_widgets.sql": "CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT);\n",
+	})
+
+	status, err := m.Status(drifted)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.Drifted() {
+		t.Fatal("Expected drift to be detected")
+	}
+	if got := status.Filenames(MigrationModified); !reflect.DeepEqual(got, []string{"001_create_widgets.sql"}) {
+		t.Errorf("modified = %v, want [001_create_widgets.sql]", got)
+	}
+	if got := status.Filenames(MigrationMissing); !reflect.DeepEqual(got, []string{"002_create_gadgets.sql"}) {
+		t.Errorf("missing = %v, want [002_create_gadgets.sql]", got)
+	}
+
+	if err := m.RunMigrations(drifted); err == nil {
+		t.Error("Expected RunMigrations to refuse drifted migrations by default")
+	}
+
+	m.SetDriftPolicy(DriftWarn)
+	if err := m.RunMigrations(drifted); err != nil {
+		t.Errorf("RunMigrations() with warn policy error = %v", err)
+	}
+}
+
+func TestChecksumBackfill(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrationFS(t, map[string]string{
+		"001_create_widgets.sql": "CREATE TABLE widgets (id INTEGER PRIMARY KEY);\n",
+	})
+
+	// Simulate a migration recorded before checksums were tracked
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	if _, err := m.db.Exec("UPDATE migrations SET checksum = NULL"); err != nil {
+		t.Fatalf("Failed to clear checksum: %v", err)
+	}
+
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	var stored string
+	if err := m.db.QueryRow("SELECT checksum FROM migrations WHERE id = 1").Scan(&stored); err != nil {
+		t.Fatalf("Failed to read checksum: %v", err)
+	}
+	if stored != checksum([]byte("CREATE TABLE widgets (id INTEGER PRIMARY KEY);\n")) {
+		t.Errorf("backfilled checksum = %s, want file checksum", stored)
+	}
+}
+
+func TestParseDriftPolicy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected DriftPolicy
+		wantErr  bool
+	}{
+		{"error", DriftError, false},
+		{"WARN", DriftWarn, false},
+		{"ignore", "", true},
+	}
+
+	for _, tt := range tests {
+		policy, err := ParseDriftPolicy(tt.input)
+		if (err != nil) != tt.wantErr || policy != tt.expected {
+			t.Errorf("ParseDriftPolicy(%q) = %q, %v; want %q", tt.input, policy, err, tt.expected)
+		}
+	}
+}
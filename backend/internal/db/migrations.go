@@ -1,30 +1,68 @@
 package db
 
 import (
+	"bufio"
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
+)
+
+// Section markers that split a migration file into up and down SQL.
+// Files without markers are treated as up-only migrations.
+const (
+	upMarker   = "-- migrate:up"
+	downMarker = "-- migrate:down"
 )
 
 // Migration represents a database migration
 type Migration struct {
 	ID       int
 	Filename string
-	Content  string
+	Content  string // SQL applied when migrating up
+	Down     string // SQL applied when rolling back, empty if irreversible
+	Checksum string // SHA-256 of the migration file
 }
 
 // MigrationManager handles database migrations
 type MigrationManager struct {
-	db *sql.DB
+	db          *sql.DB
+	dialect     Dialect
+	driftPolicy DriftPolicy
+	lockTimeout time.Duration
+	lockOwner   string    // owner of the SQLite lock row while it is held
+	dryRun      io.Writer // when set, migration SQL is printed here instead of executed
 }
 
 // NewMigrationManager creates a new migration manager
-func NewMigrationManager(db *sql.DB) *MigrationManager {
-	return &MigrationManager{db: db}
+func NewMigrationManager(db *sql.DB, dialect Dialect) *MigrationManager {
+	return &MigrationManager{
+		db:          db,
+		dialect:     dialect,
+		driftPolicy: DriftError,
+		lockTimeout: DefaultMigrationLockTimeout,
+	}
+}
+
+// SetDriftPolicy sets how drift between applied and on-disk migrations is handled
+func (m *MigrationManager) SetDriftPolicy(policy DriftPolicy) {
+	m.driftPolicy = policy
+}
+
+// SetDryRun makes migrations print the SQL they would execute to out instead
+// of running it. Passing nil turns dry run off.
+func (m *MigrationManager) SetDryRun(out io.Writer) {
+	m.dryRun = out
 }
 
 // CreateMigrationsTable creates the migrations table to track applied migrations
@@ -33,11 +71,22 @@ func (m *MigrationManager) CreateMigrationsTable() error {
 	CREATE TABLE IF NOT EXISTS migrations (
 		id INTEGER PRIMARY KEY,
 		filename TEXT NOT NULL,
-		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		checksum TEXT,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
 	`
-	_, err := m.db.Exec(query)
-	return err
+	if _, err := m.db.Exec(query); err != nil {
+		return err
+	}
+
+	// Tables created before checksums were tracked lack the column
+	if _, err := m.db.Exec("SELECT checksum FROM migrations WHERE 1 = 0"); err != nil {
+		if _, err := m.db.Exec("ALTER TABLE migrations ADD COLUMN checksum TEXT"); err != nil {
+			return fmt.Errorf("failed to add checksum column: %v", err)
+		}
+	}
+
+	return nil
 }
 
 // GetAppliedMigrations returns a list of applied migration filenames
@@ -60,20 +109,33 @@ func (m *MigrationManager) GetAppliedMigrations() ([]string, error) {
 	return migrations, nil
 }
 
-// LoadMigrations loads all migration files from the migrations directory
-func (m *MigrationManager) LoadMigrations(migrationsDir string) ([]Migration, error) {
-	var migrations []Migration
+// LoadMigrations loads all migration files from the given file system.
+// A file named with a dialect suffix, such as "001_create_users_table.postgres.sql",
+// replaces the shared "001_create_users_table.sql" for that dialect and is
+// ignored by the others. Variants are recorded under the shared filename so
+// every dialect shares one migration history.
+func (m *MigrationManager) LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	byFilename := make(map[string]Migration)
+	variants := make(map[string]bool)
 
-	err := filepath.WalkDir(migrationsDir, func(path string, d fs.DirEntry, err error) error {
+	err := fs.WalkDir(fsys, ".", func(filePath string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if d.IsDir() || !strings.HasSuffix(path, ".sql") {
+		if d.IsDir() || !strings.HasSuffix(filePath, ".sql") {
 			return nil
 		}
 
-		filename := filepath.Base(path)
+		filename, dialect := migrationVariant(path.Base(filePath))
+		if dialect != "" && dialect != m.dialect.Name() {
+			return nil
+		}
+
+		// A shared file never replaces the dialect's own variant
+		if dialect == "" && variants[filename] {
+			return nil
+		}
 
 		// Extract migration ID from filename (e.g., "001_create_users_table.sql" -> 1)
 		parts := strings.Split(filename, "_")
@@ -87,16 +149,23 @@ func (m *MigrationManager) LoadMigrations(migrationsDir string) ([]Migration, er
 		}
 
 		// Read migration content
-		content, err := readFile(path)
+		content, err := fs.ReadFile(fsys, filePath)
 		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %v", filename, err)
+			return fmt.Errorf("failed to read migration file %s: %v", path.Base(filePath), err)
 		}
 
-		migrations = append(migrations, Migration{
+		up, down := splitMigration(string(content))
+
+		byFilename[filename] = Migration{
 			ID:       id,
 			Filename: filename,
-			Content:  string(content),
-		})
+			Content:  up,
+			Down:     down,
+			Checksum: checksum(content),
+		}
+		if dialect != "" {
+			variants[filename] = true
+		}
 
 		return nil
 	})
@@ -105,75 +174,384 @@ func (m *MigrationManager) LoadMigrations(migrationsDir string) ([]Migration, er
 		return nil, err
 	}
 
+	migrations := make([]Migration, 0, len(byFilename))
+	for _, migration := range byFilename {
+		migrations = append(migrations, migration)
+	}
+
 	// Sort migrations by ID
 	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].ID < migrations[j].ID
+		if migrations[i].ID != migrations[j].ID {
+			return migrations[i].ID < migrations[j].ID
+		}
+		return migrations[i].Filename < migrations[j].Filename
 	})
 
 	return migrations, nil
 }
 
+// migrationVariant splits a dialect suffix off a migration filename,
+// e.g. "001_users.postgres.sql" -> ("001_users.sql", "postgres")
+func migrationVariant(filename string) (string, string) {
+	base := strings.TrimSuffix(filename, ".sql")
+	for _, dialect := range []Dialect{SQLite, Postgres} {
+		suffix := "." + dialect.Name()
+		if strings.HasSuffix(base, suffix) {
+			return strings.TrimSuffix(base, suffix) + ".sql", dialect.Name()
+		}
+	}
+	return filename, ""
+}
+
 // ApplyMigration applies a single migration
 func (m *MigrationManager) ApplyMigration(migration Migration) error {
-	tx, err := m.db.Begin()
+	return m.inTransaction(func(tx execer) error {
+		// Execute migration SQL
+		if _, err := tx.ExecContext(context.Background(), migration.Content); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %v", migration.Filename, err)
+		}
+
+		// Record migration as applied
+		query := m.dialect.Rebind("INSERT INTO migrations (id, filename, checksum) VALUES (?, ?, ?)")
+		if _, err := tx.ExecContext(context.Background(), query, migration.ID, migration.Filename, migration.Checksum); err != nil {
+			return fmt.Errorf("failed to record migration %s: %v", migration.Filename, err)
+		}
+
+		return m.refreshLock(tx)
+	})
+}
+
+// RevertMigration rolls back a single migration using its down section
+func (m *MigrationManager) RevertMigration(migration Migration) error {
+	if strings.TrimSpace(migration.Down) == "" {
+		return fmt.Errorf("migration %s has no down section", migration.Filename)
+	}
+
+	return m.inTransaction(func(tx execer) error {
+		// Execute rollback SQL
+		if _, err := tx.ExecContext(context.Background(), migration.Down); err != nil {
+			return fmt.Errorf("failed to revert migration %s: %v", migration.Filename, err)
+		}
+
+		// Remove migration record
+		query := m.dialect.Rebind("DELETE FROM migrations WHERE id = ?")
+		if _, err := tx.ExecContext(context.Background(), query, migration.ID); err != nil {
+			return fmt.Errorf("failed to remove migration record %s: %v", migration.Filename, err)
+		}
+
+		return m.refreshLock(tx)
+	})
+}
+
+// execer is satisfied by *sql.Tx and *sql.Conn
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// inTransaction runs fn inside a transaction. SQLite transactions start with
+// BEGIN IMMEDIATE so the write lock is taken up front; upgrading a deferred
+// transaction while another process writes fails with "database is locked".
+func (m *MigrationManager) inTransaction(fn func(tx execer) error) error {
+	ctx := context.Background()
+
+	if m.dialect != SQLite {
+		tx, err := m.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	conn, err := m.db.Conn(ctx)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return err
+	}
 
-	// Execute migration SQL
-	if _, err := tx.Exec(migration.Content); err != nil {
-		return fmt.Errorf("failed to execute migration %s: %v", migration.Filename, err)
+	if err := fn(conn); err != nil {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return err
 	}
 
-	// Record migration as applied
-	if _, err := tx.Exec("INSERT INTO migrations (id, filename) VALUES (?, ?)", migration.ID, migration.Filename); err != nil {
-		return fmt.Errorf("failed to record migration %s: %v", migration.Filename, err)
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return err
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // RunMigrations runs all pending migrations
-func (m *MigrationManager) RunMigrations(migrationsDir string) error {
-	// Create migrations table if it doesn't exist
-	if err := m.CreateMigrationsTable(); err != nil {
-		return fmt.Errorf("failed to create migrations table: %v", err)
+func (m *MigrationManager) RunMigrations(fsys fs.FS) error {
+	return m.withLock(func() error {
+		return m.runMigrations(fsys)
+	})
+}
+
+// runMigrations applies pending migrations; the caller holds the lock
+func (m *MigrationManager) runMigrations(fsys fs.FS) error {
+	allMigrations, appliedMap, err := m.prepare(fsys)
+	if err != nil {
+		return err
 	}
 
-	// Get applied migrations
-	appliedMigrations, err := m.GetAppliedMigrations()
+	// Apply pending migrations
+	for _, migration := range allMigrations {
+		if !appliedMap[migration.Filename] {
+			if err := m.apply(migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Rollback reverts the most recently applied migrations, newest first
+func (m *MigrationManager) Rollback(fsys fs.FS, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("rollback steps must be positive")
+	}
+
+	return m.withLock(func() error {
+		return m.rollback(fsys, steps)
+	})
+}
+
+// rollback reverts applied migrations; the caller holds the lock
+func (m *MigrationManager) rollback(fsys fs.FS, steps int) error {
+	allMigrations, appliedMap, err := m.prepare(fsys)
 	if err != nil {
-		return fmt.Errorf("failed to get applied migrations: %v", err)
+		return err
+	}
+
+	for i := len(allMigrations) - 1; i >= 0 && steps > 0; i-- {
+		migration := allMigrations[i]
+		if !appliedMap[migration.Filename] {
+			continue
+		}
+		if err := m.revert(migration); err != nil {
+			return err
+		}
+		steps--
+	}
+
+	return nil
+}
+
+// MigrateTo applies or reverts migrations until the schema is at the given
+// version. Version 0 reverts every migration.
+func (m *MigrationManager) MigrateTo(fsys fs.FS, version int) error {
+	if version < 0 {
+		return fmt.Errorf("invalid target version: %d", version)
+	}
+
+	return m.withLock(func() error {
+		return m.migrateTo(fsys, version)
+	})
+}
+
+// migrateTo moves the schema to the target version; the caller holds the lock
+func (m *MigrationManager) migrateTo(fsys fs.FS, version int) error {
+	allMigrations, appliedMap, err := m.prepare(fsys)
+	if err != nil {
+		return err
+	}
+
+	if version > 0 {
+		found := false
+		for _, migration := range allMigrations {
+			if migration.ID == version {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("migration version %d not found", version)
+		}
+	}
+
+	// Revert newer migrations, newest first
+	for i := len(allMigrations) - 1; i >= 0; i-- {
+		migration := allMigrations[i]
+		if migration.ID > version && appliedMap[migration.Filename] {
+			if err := m.revert(migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Apply pending migrations up to the target
+	for _, migration := range allMigrations {
+		if migration.ID <= version && !appliedMap[migration.Filename] {
+			if err := m.apply(migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// prepare creates the migrations table, loads the available and applied
+// migrations and enforces the drift policy before anything is changed. A dry
+// run only reads the migrations table, leaving it as it is.
+func (m *MigrationManager) prepare(fsys fs.FS) ([]Migration, map[string]bool, error) {
+	var appliedMigrations []appliedRecord
+	var err error
+	if m.dryRun != nil {
+		appliedMigrations, err = m.peekAppliedRecords()
+	} else {
+		// Create migrations table if it doesn't exist
+		if err := m.CreateMigrationsTable(); err != nil {
+			return nil, nil, fmt.Errorf("failed to create migrations table: %v", err)
+		}
+
+		appliedMigrations, err = m.getAppliedRecords()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get applied migrations: %v", err)
 	}
 
 	// Load all migrations
-	allMigrations, err := m.LoadMigrations(migrationsDir)
+	allMigrations, err := m.LoadMigrations(fsys)
 	if err != nil {
-		return fmt.Errorf("failed to load migrations: %v", err)
+		return nil, nil, fmt.Errorf("failed to load migrations: %v", err)
+	}
+
+	// Record checksums for migrations applied before they were tracked. A
+	// dry run skips this; records without a checksum never count as drift.
+	if m.dryRun == nil {
+		if err := m.backfillChecksums(allMigrations, appliedMigrations); err != nil {
+			return nil, nil, fmt.Errorf("failed to record migration checksums: %v", err)
+		}
+	}
+
+	if err := m.checkDrift(buildStatus(allMigrations, appliedMigrations)); err != nil {
+		return nil, nil, err
 	}
 
 	// Create a map of applied migrations for quick lookup
 	appliedMap := make(map[string]bool)
-	for _, filename := range appliedMigrations {
-		appliedMap[filename] = true
+	for _, record := range appliedMigrations {
+		appliedMap[record.Filename] = true
 	}
 
-	// Apply pending migrations
-	for _, migration := range allMigrations {
-		if !appliedMap[migration.Filename] {
-			fmt.Printf("Applying migration: %s\n", migration.Filename)
-			if err := m.ApplyMigration(migration); err != nil {
-				return fmt.Errorf("failed to apply migration %s: %v", migration.Filename, err)
-			}
-			fmt.Printf("Successfully applied migration: %s\n", migration.Filename)
+	return allMigrations, appliedMap, nil
+}
+
+// apply applies a migration and logs progress
+func (m *MigrationManager) apply(migration Migration) error {
+	if m.dryRun != nil {
+		fmt.Fprintf(m.dryRun, "-- Would apply migration: %s\n%s\n\n", migration.Filename, strings.TrimSpace(migration.Content))
+		return nil
+	}
+
+	fmt.Printf("Applying migration: %s\n", migration.Filename)
+	if err := m.ApplyMigration(migration); err != nil {
+		return fmt.Errorf("failed to apply migration %s: %v", migration.Filename, err)
+	}
+	fmt.Printf("Successfully applied migration: %s\n", migration.Filename)
+	return nil
+}
+
+// revert reverts a migration and logs progress
+func (m *MigrationManager) revert(migration Migration) error {
+	if m.dryRun != nil {
+		if migration.Down == "" {
+			return fmt.Errorf("migration %s has no down section", migration.Filename)
 		}
+		fmt.Fprintf(m.dryRun, "-- Would revert migration: %s\n%s\n\n", migration.Filename, strings.TrimSpace(migration.Down))
+		return nil
 	}
 
+	fmt.Printf("Reverting migration: %s\n", migration.Filename)
+	if err := m.RevertMigration(migration); err != nil {
+		return fmt.Errorf("failed to roll back migration %s: %v", migration.Filename, err)
+	}
+	fmt.Printf("Successfully reverted migration: %s\n", migration.Filename)
 	return nil
 }
 
-// readFile reads a file and returns its content
-func readFile(filename string) ([]byte, error) {
-	return os.ReadFile(filename)
+// checksum returns the hex encoded SHA-256 of a migration file
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// splitMigration splits migration content into its up and down sections
+func splitMigration(content string) (up, down string) {
+	var upBuf, downBuf strings.Builder
+	current := &upBuf
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case upMarker:
+			current = &upBuf
+			continue
+		case downMarker:
+			current = &downBuf
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+
+	return upBuf.String(), downBuf.String()
+}
+
+// nonIdentifierChars matches runs of characters not allowed in migration names
+var nonIdentifierChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateMigrationFile writes an empty up/down migration named after name into
+// dir, numbered after the highest existing migration, and returns its path
+func CreateMigrationFile(dir, name string) (string, error) {
+	name = strings.Trim(nonIdentifierChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", fmt.Errorf("migration name is required")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read migrations directory: %v", err)
+	}
+
+	nextID := 1
+	for _, entry := range entries {
+		var id int
+		if !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		if _, err := fmt.Sscanf(entry.Name(), "%d_", &id); err == nil && id >= nextID {
+			nextID = id + 1
+		}
+	}
+
+	filename := fmt.Sprintf("%03d_%s.sql", nextID, name)
+	content := fmt.Sprintf("-- %s\n-- Migration: %s\n\n%s\n\n%s\n", strings.ReplaceAll(name, "_", " "), filename, upMarker, downMarker)
+
+	filePath := filepath.Join(dir, filename)
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create migration file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return "", fmt.Errorf("failed to write migration file: %v", err)
+	}
+
+	return filePath, nil
 }
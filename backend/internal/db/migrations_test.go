@@ -0,0 +1,425 @@
+package db
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/migrations"
+)
+
+// newTestMigrationManager opens a temporary SQLite database
+func newTestMigrationManager(t *testing.T) *MigrationManager {
+	t.Helper()
+
+	database, err := Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	return database.migrationManager
+}
+
+// migrationFS builds an in-memory file system of migration files
+func migrationFS(t *testing.T, files map[string]string) fs.FS {
+	t.Helper()
+
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	return fsys
+}
+
+// tableExists reports whether a table exists in the SQLite schema
+func tableExists(t *testing.T, m *MigrationManager, table string) bool {
+	t.Helper()
+
+	var count int
+	err := m.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to inspect schema: %v", err)
+	}
+	return count > 0
+}
+
+var reversibleMigrations = map[string]string{
+	"001_create_widgets.sql": "-- migrate:up\nCREATE TABLE widgets (id INTEGER PRIMARY KEY);\n\n-- migrate:down\nDROP TABLE widgets;\n",
+	"002_create_gadgets.sql": "-- migrate:up\nCREATE TABLE gadgets (id INTEGER PRIMARY KEY);\n\n-- migrate:down\nDROP TABLE gadgets;\n",
+	"003_create_gizmos.sql":  "-- migrate:up\nCREATE TABLE gizmos (id INTEGER PRIMARY KEY);\n\n-- migrate:down\nDROP TABLE gizmos;\n",
+}
+
+func TestSplitMigration(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		expectedUp   string
+		expectedDown string
+	}{
+		{
+			name:         "no markers is up only",
+			content:      "CREATE TABLE a (id INTEGER);\n",
+			expectedUp:   "CREATE TABLE a (id INTEGER);\n",
+			expectedDown: "",
+		},
+		{
+			name:         "up and down sections",
+			content:      "-- header\n-- migrate:up\nCREATE TABLE a (id INTEGER);\n-- migrate:down\nDROP TABLE a;\n",
+			expectedUp:   "-- header\nCREATE TABLE a (id INTEGER);\n",
+			expectedDown: "DROP TABLE a;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up, down := splitMigration(tt.content)
+			if up != tt.expectedUp || down != tt.expectedDown {
+				t.Errorf("splitMigration() = %q, %q; want %q, %q", up, down, tt.expectedUp, tt.expectedDown)
+			}
+		})
+	}
+}
+
+func TestRollback(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrationFS(t, reversibleMigrations)
+
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	if err := m.Rollback(fsys, 2); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	applied, err := m.GetAppliedMigrations()
+	if err != nil {
+		t.Fatalf("GetAppliedMigrations() error = %v", err)
+	}
+	if !reflect.DeepEqual(applied, []string{"001_create_widgets.sql"}) {
+		t.Errorf("applied after rollback = %v, want [001_create_widgets.sql]", applied)
+	}
+	if tableExists(t, m, "gadgets") || tableExists(t, m, "gizmos") {
+		t.Error("Expected rolled back tables to be dropped")
+	}
+	if !tableExists(t, m, "widgets") {
+		t.Error("Expected widgets table to remain")
+	}
+}
+
+func TestRollbackWithoutDownSection(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrationFS(t, map[string]string{
+		"001_create_widgets.sql": "CREATE TABLE widgets (id INTEGER PRIMARY KEY);\n",
+	})
+
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	if err := m.Rollback(fsys, 1); err == nil {
+		t.Fatal("Expected rollback of an up-only migration to fail")
+	}
+	if !tableExists(t, m, "widgets") {
+		t.Error("Expected widgets table to remain after failed rollback")
+	}
+}
+
+func TestRollbackIsTransactional(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrationFS(t, map[string]string{
+		"001_create_widgets.sql": "-- migrate:up\nCREATE TABLE widgets (id INTEGER PRIMARY KEY);\n-- migrate:down\nDROP TABLE widgets;\nDROP TABLE does_not_exist;\n",
+	})
+
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	if err := m.Rollback(fsys, 1); err == nil {
+		t.Fatal("Expected failing down section to return an error")
+	}
+
+	applied, _ := m.GetAppliedMigrations()
+	if len(applied) != 1 || !tableExists(t, m, "widgets") {
+		t.Errorf("Expected failed rollback to leave schema and record intact, applied = %v", applied)
+	}
+}
+
+func TestMigrateTo(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrationFS(t, reversibleMigrations)
+
+	if err := m.MigrateTo(fsys, 2); err != nil {
+		t.Fatalf("MigrateTo(2) error = %v", err)
+	}
+	applied, _ := m.GetAppliedMigrations()
+	if !reflect.DeepEqual(applied, []string{"001_create_widgets.sql", "002_create_gadgets.sql"}) {
+		t.Errorf("applied after MigrateTo(2) = %v", applied)
+	}
+
+	if err := m.MigrateTo(fsys, 3); err != nil {
+		t.Fatalf("MigrateTo(3) error = %v", err)
+	}
+	if !tableExists(t, m, "gizmos") {
+		t.Error("Expected gizmos table after MigrateTo(3)")
+	}
+
+	if err := m.MigrateTo(fsys, 0); err != nil {
+		t.Fatalf("MigrateTo(0) error = %v", err)
+	}
+	applied, _ = m.GetAppliedMigrations()
+	if len(applied) != 0 {
+		t.Errorf("applied after MigrateTo(0) = %v, want none", applied)
+	}
+
+	if err := m.MigrateTo(fsys, 7); err == nil {
+		t.Error("Expected MigrateTo with unknown version to fail")
+	}
+}
+
+func TestProjectMigrationsRoundTrip(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrations.FS
+
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	if err := m.MigrateTo(fsys, 0); err != nil {
+		t.Fatalf("MigrateTo(0) error = %v", err)
+	}
+	for _, table := range []string{"users", "articles", "tags", "article_tags", "follows", "favorites", "comments"} {
+		if tableExists(t, m, table) {
+			t.Errorf("Expected table %s to be dropped", table)
+		}
+	}
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() after full rollback error = %v", err)
+	}
+}
+
+func TestLoadMigrationsSelectsDialectVariant(t *testing.T) {
+	fsys := migrationFS(t, map[string]string{
+		"001_create_widgets.sql":          "CREATE TABLE widgets (id INTEGER PRIMARY KEY);\n",
+		"001_create_widgets.postgres.sql": "CREATE TABLE widgets (id SERIAL PRIMARY KEY);\n",
+		"002_create_gadgets.sqlite.sql":   "CREATE TABLE gadgets (id INTEGER PRIMARY KEY);\n",
+		"002_create_gadgets.postgres.sql": "CREATE TABLE gadgets (id SERIAL PRIMARY KEY);\n",
+		"003_create_gizmos.sql":           "CREATE TABLE gizmos (id INTEGER PRIMARY KEY);\n",
+	})
+
+	tests := []struct {
+		dialect  Dialect
+		expected []string
+	}{
+		{SQLite, []string{"INTEGER", "INTEGER", "INTEGER"}},
+		{Postgres, []string{"SERIAL", "SERIAL", "INTEGER"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			m := NewMigrationManager(nil, tt.dialect)
+			loaded, err := m.LoadMigrations(fsys)
+			if err != nil {
+				t.Fatalf("LoadMigrations() error = %v", err)
+			}
+			if len(loaded) != len(tt.expected) {
+				t.Fatalf("loaded %d migrations, want %d", len(loaded), len(tt.expected))
+			}
+
+			filenames := []string{"001_create_widgets.sql", "002_create_gadgets.sql", "003_create_gizmos.sql"}
+			for i, migration := range loaded {
+				if migration.Filename != filenames[i] {
+					t.Errorf("migration %d filename = %s, want %s", i, migration.Filename, filenames[i])
+				}
+				if !strings.Contains(migration.Content, tt.expected[i]) {
+					t.Errorf("migration %s content = %q, want %s variant", migration.Filename, migration.Content, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProjectMigrationsHaveVariantsForEveryDialect(t *testing.T) {
+	sqlite, err := NewMigrationManager(nil, SQLite).LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("LoadMigrations(sqlite) error = %v", err)
+	}
+	postgres, err := NewMigrationManager(nil, Postgres).LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("LoadMigrations(postgres) error = %v", err)
+	}
+
+	if len(sqlite) != len(postgres) {
+		t.Fatalf("sqlite has %d migrations, postgres has %d", len(sqlite), len(postgres))
+	}
+	for i := range sqlite {
+		if sqlite[i].Filename != postgres[i].Filename {
+			t.Errorf("migration %d is %s on sqlite but %s on postgres", i, sqlite[i].Filename, postgres[i].Filename)
+		}
+	}
+	for _, migration := range postgres {
+		if strings.Contains(migration.Content, "DATETIME") {
+			t.Errorf("postgres migration %s uses SQLite-only DATETIME", migration.Filename)
+		}
+	}
+}
+
+func TestSetMigrationsDir(t *testing.T) {
+	database, err := Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	overrideDir := t.TempDir()
+	hotfix := []byte("CREATE TABLE hotfixes (id INTEGER PRIMARY KEY);\n")
+	if err := os.WriteFile(filepath.Join(overrideDir, "001_create_hotfixes.sql"), hotfix, 0o644); err != nil {
+		t.Fatalf("Failed to write migration: %v", err)
+	}
+
+	database.SetMigrationsDir(overrideDir)
+	if err := database.Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	m := database.migrationManager
+	if !tableExists(t, m, "hotfixes") {
+		t.Error("Expected migrations to be loaded from the override directory")
+	}
+	if tableExists(t, m, "users") {
+		t.Error("Expected embedded migrations to be ignored when overridden")
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	m := newTestMigrationManager(t)
+	fsys := migrationFS(t, reversibleMigrations)
+
+	var out strings.Builder
+	m.SetDryRun(&out)
+	if err := m.RunMigrations(fsys); err != nil {
+		t.Fatalf("RunMigrations() dry run error = %v", err)
+	}
+
+	if !strings.Contains(out.String(), "CREATE TABLE widgets") {
+		t.Errorf("dry run output = %q, want migration SQL", out.String())
+	}
+	if tableExists(t, m, "widgets") || tableExists(t, m, "migrations") {
+		t.Error("Expected dry run not to create tables")
+	}
+}
+
+// schemaSnapshot describes the SQLite schema and the applied migrations
+func schemaSnapshot(t *testing.T, m *MigrationManager) string {
+	t.Helper()
+
+	var snapshot strings.Builder
+	rows, err := m.db.Query("SELECT type, name, COALESCE(sql, '') FROM sqlite_master ORDER BY type, name")
+	if err != nil {
+		t.Fatalf("Failed to inspect schema: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var kind, name, sql string
+		if err := rows.Scan(&kind, &name, &sql); err != nil {
+			t.Fatalf("Failed to scan schema: %v", err)
+		}
+		snapshot.WriteString(kind + " " + name + ": " + sql + "\n")
+	}
+
+	if tableExists(t, m, "migrations") {
+		applied, err := m.GetAppliedMigrations()
+		if err != nil {
+			t.Fatalf("Failed to read migrations: %v", err)
+		}
+		snapshot.WriteString(strings.Join(applied, "\n"))
+	}
+	return snapshot.String()
+}
+
+func TestDryRunIsReadOnly(t *testing.T) {
+	fsys := migrationFS(t, reversibleMigrations)
+
+	tests := []struct {
+		name  string
+		setup string
+		run   func(m *MigrationManager) error
+		want  string
+	}{
+		{
+			name: "up on an empty database",
+			run:  func(m *MigrationManager) error { return m.RunMigrations(fsys) },
+			want: "CREATE TABLE gizmos",
+		},
+		{
+			name: "to a version",
+			run:  func(m *MigrationManager) error { return m.MigrateTo(fsys, 2) },
+			want: "CREATE TABLE gadgets",
+		},
+		{
+			// A migrations table from before checksums were tracked
+			name: "down on a legacy migrations table",
+			setup: `CREATE TABLE migrations (id INTEGER PRIMARY KEY, filename TEXT NOT NULL, applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+				CREATE TABLE widgets (id INTEGER PRIMARY KEY);
+				INSERT INTO migrations (id, filename) VALUES (1, '001_create_widgets.sql');`,
+			run:  func(m *MigrationManager) error { return m.Rollback(fsys, 1) },
+			want: "DROP TABLE widgets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMigrationManager(t)
+			if tt.setup != "" {
+				if _, err := m.db.Exec(tt.setup); err != nil {
+					t.Fatalf("Failed to set up database: %v", err)
+				}
+			}
+			before := schemaSnapshot(t, m)
+
+			var out strings.Builder
+			m.SetDryRun(&out)
+			if err := tt.run(m); err != nil {
+				t.Fatalf("dry run error = %v", err)
+			}
+
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("dry run output = %q, want %q", out.String(), tt.want)
+			}
+			if after := schemaSnapshot(t, m); after != before {
+				t.Errorf("dry run changed the database:\nbefore:\n%s\nafter:\n%s", before, after)
+			}
+		})
+	}
+}
+
+func TestCreateMigrationFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"001_create_widgets.sql", "002_create_gadgets.sqlite.sql", "002_create_gadgets.postgres.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("-- migrate:up\n"), 0o644); err != nil {
+			t.Fatalf("Failed to write migration: %v", err)
+		}
+	}
+
+	path, err := CreateMigrationFile(dir, "Add Gizmo-Index")
+	if err != nil {
+		t.Fatalf("CreateMigrationFile() error = %v", err)
+	}
+	if filepath.Base(path) != "003_add_gizmo_index.sql" {
+		t.Errorf("CreateMigrationFile() = %s, want 003_add_gizmo_index.sql", filepath.Base(path))
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read migration: %v", err)
+	}
+	if !strings.Contains(string(content), upMarker) || !strings.Contains(string(content), downMarker) {
+		t.Errorf("migration template = %q, want up and down markers", content)
+	}
+
+	if _, err := CreateMigrationFile(dir, "!!"); err == nil {
+		t.Error("Expected an error for an empty migration name")
+	}
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Options configures the connection pool and SQLite connection settings
+type Options struct {
+	MaxOpenConns    int           // 0 means unlimited
+	MaxIdleConns    int           // 0 means no idle connections are kept
+	ConnMaxLifetime time.Duration // 0 means connections are reused forever
+	ConnMaxIdleTime time.Duration // 0 means idle connections are not closed
+
+	// SQLite only; applied to every connection the pool opens
+	JournalMode string        // e.g. "WAL" or "DELETE", empty keeps the file's mode
+	BusyTimeout time.Duration // how long a write waits on a locked database
+	Synchronous string        // e.g. "NORMAL" or "FULL", empty keeps the default
+}
+
+// DefaultOptions returns settings suited to a single server instance
+func DefaultOptions() Options {
+	return Options{
+		MaxOpenConns:    25,
+		MaxIdleConns:    25,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+		JournalMode:     "WAL",
+		BusyTimeout:     5 * time.Second,
+		Synchronous:     "NORMAL",
+	}
+}
+
+// configurePool applies the pool limits to db
+func configurePool(db *sql.DB, options Options) {
+	db.SetMaxOpenConns(options.MaxOpenConns)
+	db.SetMaxIdleConns(options.MaxIdleConns)
+	db.SetConnMaxLifetime(options.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(options.ConnMaxIdleTime)
+}
+
+// sqliteDSN adds the connection settings to a SQLite data source name. The
+// go-sqlite3 driver runs these PRAGMAs on every new connection, unlike a
+// PRAGMA executed once through the pool, which only reaches one connection.
+// Transactions begin with BEGIN IMMEDIATE: a deferred transaction that reads
+// and then writes cannot take the write lock once another connection has
+// committed, and fails with "database is locked" instead of waiting.
+// Parameters already present in the DSN take precedence.
+func sqliteDSN(dataSourceName string, options Options) string {
+	busyTimeout := ""
+	if options.BusyTimeout > 0 {
+		busyTimeout = fmt.Sprint(options.BusyTimeout.Milliseconds())
+	}
+
+	params := [][2]string{
+		{"_foreign_keys", "on"},
+		{"_journal_mode", options.JournalMode},
+		{"_busy_timeout", busyTimeout},
+		{"_synchronous", options.Synchronous},
+		{"_txlock", "immediate"},
+	}
+
+	var query []string
+	for _, param := range params {
+		key, value := param[0], param[1]
+		if value == "" || strings.Contains(dataSourceName, key+"=") {
+			continue
+		}
+		query = append(query, key+"="+url.QueryEscape(value))
+	}
+	if len(query) == 0 {
+		return dataSourceName
+	}
+
+	separator := "?"
+	if strings.Contains(dataSourceName, "?") {
+		separator = "&"
+	}
+	return dataSourceName + separator + strings.Join(query, "&")
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLiteDSN(t *testing.T) {
+	options := Options{JournalMode: "WAL", BusyTimeout: 2 * time.Second, Synchronous: "NORMAL"}
+
+	tests := []struct {
+		dsn      string
+		options  Options
+		expected string
+	}{
+		{"test.db", options, "test.db?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=2000&_synchronous=NORMAL&_txlock=immediate"},
+		{"file:test.db?cache=shared", options, "file:test.db?cache=shared&_foreign_keys=on&_journal_mode=WAL&_busy_timeout=2000&_synchronous=NORMAL&_txlock=immediate"},
+		{"test.db?_journal_mode=DELETE", options, "test.db?_journal_mode=DELETE&_foreign_keys=on&_busy_timeout=2000&_synchronous=NORMAL&_txlock=immediate"},
+		{"test.db", Options{}, "test.db?_foreign_keys=on&_txlock=immediate"},
+	}
+
+	for _, tt := range tests {
+		if got := sqliteDSN(tt.dsn, tt.options); got != tt.expected {
+			t.Errorf("sqliteDSN(%q) = %q, want %q", tt.dsn, got, tt.expected)
+		}
+	}
+}
+
+func TestSQLiteSettingsApplyToEveryConnection(t *testing.T) {
+	database, err := Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	// Hold several connections at once so the pool must open new ones
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		conn, err := database.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get connection: %v", err)
+		}
+		defer conn.Close()
+
+		var foreignKeys, busyTimeout int
+		var journalMode string
+		if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+			t.Fatalf("Failed to read foreign_keys: %v", err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+			t.Fatalf("Failed to read busy_timeout: %v", err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode); err != nil {
+			t.Fatalf("Failed to read journal_mode: %v", err)
+		}
+
+		if foreignKeys != 1 || busyTimeout != 5000 || journalMode != "wal" {
+			t.Errorf("connection %d: foreign_keys=%d busy_timeout=%d journal_mode=%s; want 1, 5000, wal",
+				i, foreignKeys, busyTimeout, journalMode)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Package dump exports and imports the application data as NDJSON, one
+// JSON object per row. The format does not depend on the SQL dialect, so a
+// dump taken from SQLite can be loaded into PostgreSQL and vice versa.
+package dump
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+// Format identifies dump files in their header line
+const Format = "realworld-ndjson"
+
+// Version is the dump format version written by Export
+const Version = 1
+
+// table lists the columns of an exported table
+type table struct {
+	name    string
+	columns []string
+}
+
+// tables are exported and imported in this order so that rows are always
+// inserted after the rows they reference. article_renders is a cache that
+// is rebuilt on demand, so it is left out.
+var tables = []table{
+	{"users", []string{"id", "email", "username", "password_hash", "bio", "image", "created_at", "updated_at"}},
+	{"articles", []string{"id", "slug", "title", "description", "body", "author_id", "created_at", "updated_at", "favorites_count", "status", "publish_at", "revision"}},
+	{"article_revisions", []string{"id", "article_id", "revision", "editor_id", "title", "description", "body", "tag_list", "created_at"}},
+	{"tags", []string{"id", "name", "created_at"}},
+	{"article_tags", []string{"id", "article_id", "tag_id", "created_at"}},
+	{"follows", []string{"id", "follower_id", "followed_id", "created_at"}},
+	{"favorites", []string{"id", "user_id", "article_id", "created_at"}},
+	{"comments", []string{"id", "body", "author_id", "article_id", "created_at", "updated_at"}},
+}
+
+// Header is the first line of a dump
+type Header struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	Migrations []string  `json:"migrations"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// Record is a single row of a table
+type Record struct {
+	Table string                 `json:"table"`
+	Row   map[string]interface{} `json:"row"`
+}
+
+// Counts holds the number of rows per table
+type Counts map[string]int
+
+// Export writes every row of the application tables to w
+func Export(database *db.Database, w io.Writer) (Counts, error) {
+	status, err := database.MigrationStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration status: %w", err)
+	}
+
+	// Read all tables in one transaction for a consistent dump
+	tx, err := database.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin export: %w", err)
+	}
+	defer tx.Rollback()
+
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+
+	header := Header{
+		Format:     Format,
+		Version:    Version,
+		Migrations: status.Filenames(db.MigrationApplied),
+		ExportedAt: time.Now().UTC(),
+	}
+	if err := encoder.Encode(header); err != nil {
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	counts := Counts{}
+	for _, t := range tables {
+		query := fmt.Sprintf("SELECT %s FROM %s ORDER BY id", strings.Join(t.columns, ", "), t.name)
+		rows, err := tx.Query(query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", t.name, err)
+		}
+
+		for rows.Next() {
+			values := make([]interface{}, len(t.columns))
+			pointers := make([]interface{}, len(t.columns))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+			if err := rows.Scan(pointers...); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s: %w", t.name, err)
+			}
+
+			row := make(map[string]interface{}, len(t.columns))
+			for i, column := range t.columns {
+				if b, ok := values[i].([]byte); ok {
+					values[i] = string(b)
+				}
+				row[column] = values[i]
+			}
+
+			if err := encoder.Encode(Record{Table: t.name, Row: row}); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to write %s: %w", t.name, err)
+			}
+			counts[t.name]++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to iterate %s: %w", t.name, err)
+		}
+	}
+
+	if err := out.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write dump: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Import loads a dump written by Export into an empty database. Migrations
+// are run first, which also enforces the drift policy, and the dump is
+// rejected if it was taken from a schema with migrations this binary does
+// not know. All rows are inserted in one transaction.
+func Import(database *db.Database, r io.Reader) (Counts, error) {
+	if err := database.Migrate(); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	decoder.UseNumber()
+
+	var header Header
+	if err := decoder.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if header.Format != Format || header.Version != Version {
+		return nil, fmt.Errorf("unsupported dump format: %s version %d", header.Format, header.Version)
+	}
+	if err := checkMigrations(database, header.Migrations); err != nil {
+		return nil, err
+	}
+
+	columnsByTable := make(map[string]map[string]bool, len(tables))
+	for _, t := range tables {
+		columnsByTable[t.name] = make(map[string]bool, len(t.columns))
+		for _, column := range t.columns {
+			columnsByTable[t.name][column] = true
+		}
+	}
+
+	tx, err := database.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin import: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, t := range tables {
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM " + t.name).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", t.name, err)
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("target database is not empty: %s has %d rows", t.name, count)
+		}
+	}
+
+	counts := Counts{}
+	for {
+		var record Record
+		if err := decoder.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read record: %w", err)
+		}
+
+		known, ok := columnsByTable[record.Table]
+		if !ok {
+			return nil, fmt.Errorf("unknown table in dump: %s", record.Table)
+		}
+
+		for column := range record.Row {
+			if !known[column] {
+				return nil, fmt.Errorf("unknown column in dump: %s.%s", record.Table, column)
+			}
+		}
+
+		columns := make([]string, 0, len(record.Row))
+		placeholders := make([]string, 0, len(record.Row))
+		args := make([]interface{}, 0, len(record.Row))
+		for _, column := range orderedColumns(record.Table) {
+			value, present := record.Row[column]
+			if !present {
+				continue
+			}
+			converted, err := convertValue(column, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s.%s: %w", record.Table, column, err)
+			}
+			columns = append(columns, column)
+			placeholders = append(placeholders, "?")
+			args = append(args, converted)
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			record.Table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+		if _, err := tx.Exec(database.Dialect().Rebind(query), args...); err != nil {
+			return nil, fmt.Errorf("failed to insert into %s: %w", record.Table, err)
+		}
+		counts[record.Table]++
+	}
+
+	// Explicit IDs bypass PostgreSQL sequences, so move them past the data
+	if database.Dialect() == db.Postgres {
+		for _, t := range tables {
+			query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s", t.name, t.name)
+			if _, err := tx.Exec(query); err != nil {
+				return nil, fmt.Errorf("failed to reset %s id sequence: %w", t.name, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+
+	return counts, nil
+}
+
+// checkMigrations rejects dumps taken from a schema newer than this binary's
+func checkMigrations(database *db.Database, exported []string) error {
+	status, err := database.MigrationStatus()
+	if err != nil {
+		return fmt.Errorf("failed to read migration status: %w", err)
+	}
+
+	known := make(map[string]bool, len(status.Migrations))
+	for _, filename := range status.Filenames(db.MigrationApplied) {
+		known[filename] = true
+	}
+
+	var unknown []string
+	for _, filename := range exported {
+		if !known[filename] {
+			unknown = append(unknown, filename)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("dump requires migrations not applied here: %v", unknown)
+	}
+
+	return nil
+}
+
+// orderedColumns returns the columns of a table in export order
+func orderedColumns(name string) []string {
+	for _, t := range tables {
+		if t.name == name {
+			return t.columns
+		}
+	}
+	return nil
+}
+
+// convertValue turns a decoded JSON value back into a database value
+func convertValue(column string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.Int64()
+	case string:
+		// Timestamps are exported as RFC 3339 strings
+		if strings.HasSuffix(column, "_at") {
+			return time.Parse(time.RFC3339Nano, v)
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
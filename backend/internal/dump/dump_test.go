@@ -0,0 +1,157 @@
+package dump
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+// newTestDatabase opens and migrates a temporary SQLite database
+func newTestDatabase(t *testing.T) *db.Database {
+	t.Helper()
+
+	database, err := db.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), db.SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := database.Migrate(); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	return database
+}
+
+// populate inserts one row into every exported table
+func populate(t *testing.T, database *db.Database) {
+	t.Helper()
+
+	statements := []string{
+		"INSERT INTO users (email, username, password_hash, bio) VALUES ('jake@example.com', 'jake', 'hash', 'I work at statefarm')",
+		"INSERT INTO users (email, username, password_hash) VALUES ('jane@example.com', 'jane', 'hash')",
+		"INSERT INTO articles (slug, title, description, body, author_id, favorites_count) VALUES ('how-to-train-your-dragon', 'How to train your dragon', 'Ever wonder how?', 'It takes a Jacobian', 1, 1)",
+		"INSERT INTO article_revisions (article_id, revision, editor_id, title, description, body, tag_list) VALUES (1, 1, 1, 'How to train your dragon', 'Ever wonder how?', 'It takes a Jacobian', '[\"dragons\"]')",
+		"INSERT INTO tags (name) VALUES ('dragons')",
+		"INSERT INTO article_tags (article_id, tag_id) VALUES (1, 1)",
+		"INSERT INTO follows (follower_id, followed_id) VALUES (2, 1)",
+		"INSERT INTO favorites (user_id, article_id) VALUES (2, 1)",
+		"INSERT INTO comments (body, author_id, article_id) VALUES ('Thank you so much!', 2, 1)",
+	}
+	for _, statement := range statements {
+		if _, err := database.Exec(statement); err != nil {
+			t.Fatalf("Failed to populate database: %v", err)
+		}
+	}
+}
+
+// records returns the lines of a dump after the header
+func records(t *testing.T, dump string) []string {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(dump), "\n")
+	if len(lines) == 0 {
+		t.Fatal("Expected a header line")
+	}
+	return lines[1:]
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	source := newTestDatabase(t)
+	populate(t, source)
+
+	var exported bytes.Buffer
+	counts, err := Export(source, &exported)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	for _, table := range tables {
+		if counts[table.name] == 0 {
+			t.Errorf("Export() exported no %s", table.name)
+		}
+	}
+
+	var header Header
+	if err := json.Unmarshal([]byte(strings.SplitN(exported.String(), "\n", 2)[0]), &header); err != nil {
+		t.Fatalf("Failed to decode header: %v", err)
+	}
+	if header.Format != Format || len(header.Migrations) == 0 {
+		t.Errorf("header = %+v, want format %s with migrations", header, Format)
+	}
+
+	target := newTestDatabase(t)
+	imported, err := Import(target, strings.NewReader(exported.String()))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if len(imported) != len(counts) {
+		t.Errorf("Import() counts = %v, want %v", imported, counts)
+	}
+
+	var reexported bytes.Buffer
+	if _, err := Export(target, &reexported); err != nil {
+		t.Fatalf("Export() of imported database error = %v", err)
+	}
+
+	want, got := records(t, exported.String()), records(t, reexported.String())
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("re-exported rows differ:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestImportRequiresEmptyDatabase(t *testing.T) {
+	source := newTestDatabase(t)
+	populate(t, source)
+
+	var exported bytes.Buffer
+	if _, err := Export(source, &exported); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	_, err := Import(source, &exported)
+	if err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("Import() error = %v, want not empty error", err)
+	}
+}
+
+func TestImportRejectsInvalidDumps(t *testing.T) {
+	tests := []struct {
+		name    string
+		dump    string
+		wantErr string
+	}{
+		{
+			name:    "unknown format",
+			dump:    `{"format":"csv","version":1}`,
+			wantErr: "unsupported dump format",
+		},
+		{
+			name:    "newer schema",
+			dump:    `{"format":"realworld-ndjson","version":1,"migrations":["999_from_the_future.sql"]}`,
+			wantErr: "999_from_the_future.sql",
+		},
+		{
+			name:    "unknown table",
+			dump:    "{\"format\":\"realworld-ndjson\",\"version\":1}\n{\"table\":\"secrets\",\"row\":{\"id\":1}}",
+			wantErr: "unknown table",
+		},
+		{
+			name:    "unknown column",
+			dump:    "{\"format\":\"realworld-ndjson\",\"version\":1}\n{\"table\":\"tags\",\"row\":{\"id\":1,\"colour\":\"red\"}}",
+			wantErr: "unknown column",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Import(newTestDatabase(t), strings.NewReader(tt.dump))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Import() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
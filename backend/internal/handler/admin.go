@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/dump"
+)
+
+// AdminHandler handles administrative endpoints
+type AdminHandler struct {
+	database *db.Database
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(database *db.Database) *AdminHandler {
+	return &AdminHandler{
+		database: database,
+	}
+}
+
+// Backup streams an online SQLite snapshot of the database
+func (h *AdminHandler) Backup(w http.ResponseWriter, r *http.Request) {
+	dir, err := os.MkdirTemp("", "realworld-backup-")
+	if err != nil {
+		writeJSONError(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "backup.db")
+	if err := h.database.Backup(r.Context(), path); err != nil {
+		if err == db.ErrBackupUnsupported {
+			writeJSONError(w, "Online backup is only supported for SQLite databases", http.StatusNotImplemented)
+			return
+		}
+		writeJSONError(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("realworld-%s.db", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	http.ServeFile(w, r, path)
+}
+
+// Export streams a logical NDJSON dump of the database
+func (h *AdminHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filename := fmt.Sprintf("realworld-%s.ndjson", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	// The status line is sent with the first row, so a failure part way
+	// through can only be logged
+	if _, err := dump.Export(h.database, w); err != nil {
+		log.Printf("Failed to export database: %v", err)
+	}
+}
+
+// queryStatsResponse is one statement in the query stats response
+type queryStatsResponse struct {
+	Query   string  `json:"query"`
+	Calls   int64   `json:"calls"`
+	Errors  int64   `json:"errors"`
+	TotalMs float64 `json:"totalMs"`
+	MaxMs   float64 `json:"maxMs"`
+	P50Ms   float64 `json:"p50Ms"`
+	P95Ms   float64 `json:"p95Ms"`
+}
+
+// QueryStats reports the calls and latency percentiles of every statement
+// the repositories have run, most total time first
+func (h *AdminHandler) QueryStats(w http.ResponseWriter, r *http.Request) {
+	stats := h.database.QueryStats()
+	if stats == nil {
+		writeJSONError(w, "Query stats are not enabled", http.StatusNotFound)
+		return
+	}
+
+	queries := []queryStatsResponse{}
+	for _, statement := range stats.Snapshot() {
+		queries = append(queries, queryStatsResponse{
+			Query:   statement.Query,
+			Calls:   statement.Calls,
+			Errors:  statement.Errors,
+			TotalMs: milliseconds(statement.Total),
+			MaxMs:   milliseconds(statement.Max),
+			P50Ms:   milliseconds(statement.P50),
+			P95Ms:   milliseconds(statement.P95),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"queries": queries})
+}
+
+// ResetQueryStats discards the recorded statement timings
+func (h *AdminHandler) ResetQueryStats(w http.ResponseWriter, r *http.Request) {
+	stats := h.database.QueryStats()
+	if stats == nil {
+		writeJSONError(w, "Query stats are not enabled", http.StatusNotFound)
+		return
+	}
+
+	stats.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+func TestQueryStatsEndpoints(t *testing.T) {
+	database, err := db.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), db.SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+	h := NewAdminHandler(database)
+
+	if rec := serve(h.QueryStats, http.MethodGet, "", nil, 0); rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("QueryStats() without stats = %d (%s), want a 404 JSON error", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	stats := db.NewQueryStats(0)
+	database.SetQueryStats(stats)
+	stats.Record("SELECT 1", 0, 3*time.Millisecond, nil)
+
+	rec := serve(h.QueryStats, http.MethodGet, "", nil, 0)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("QueryStats() status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var response struct {
+		Queries []queryStatsResponse `json:"queries"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode stats: %v", err)
+	}
+	if len(response.Queries) != 1 || response.Queries[0].Calls != 1 || response.Queries[0].P95Ms != 3 {
+		t.Errorf("QueryStats() = %+v, want one call of SELECT 1 at 3ms", response.Queries)
+	}
+
+	if rec := serve(h.ResetQueryStats, http.MethodDelete, "", nil, 0); rec.Code != http.StatusNoContent {
+		t.Errorf("ResetQueryStats() status = %d, want 204", rec.Code)
+	}
+	if len(stats.Snapshot()) != 0 {
+		t.Error("Expected ResetQueryStats() to discard the stats")
+	}
+}
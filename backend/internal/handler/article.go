@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/middleware"
@@ -45,12 +47,17 @@ func (h *ArticleHandler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create article
-	article, err := h.articleService.CreateArticle(req, claims.UserID)
+	article, err := h.articleService.CreateArticle(r.Context(), req, claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "title is required" || err.Error() == "description is required" || err.Error() == "body is required":
 			statusCode = http.StatusBadRequest
+		case isPublicationError(err):
+			statusCode = http.StatusBadRequest
 		default:
 			statusCode = http.StatusInternalServerError
 		}
@@ -95,9 +102,16 @@ func (h *ArticleHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
 		currentUserID = claims.UserID
 	}
 
-	// Get article
-	article, err := h.articleService.GetArticleBySlug(slug, currentUserID)
+	// Get article, with the rendered body when asked for
+	getArticle := h.articleService.GetArticleBySlug
+	if wantsBodyHTML(r) {
+		getArticle = h.articleService.GetRenderedArticleBySlug
+	}
+	article, err := getArticle(r.Context(), slug, currentUserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		if err.Error() == "article not found" {
 			statusCode = http.StatusNotFound
@@ -154,8 +168,11 @@ func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update article
-	article, err := h.articleService.UpdateArticle(slug, req, claims.UserID)
+	article, err := h.articleService.UpdateArticle(r.Context(), slug, req, claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "article not found":
@@ -164,6 +181,8 @@ func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 			statusCode = http.StatusForbidden
 		case err.Error() == "title cannot be empty" || err.Error() == "description cannot be empty" || err.Error() == "body cannot be empty":
 			statusCode = http.StatusBadRequest
+		case isPublicationError(err) || err.Error() == "published articles cannot be unpublished":
+			statusCode = http.StatusBadRequest
 		default:
 			statusCode = http.StatusInternalServerError
 		}
@@ -210,8 +229,11 @@ func (h *ArticleHandler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete article
-	err := h.articleService.DeleteArticle(slug, claims.UserID)
+	err := h.articleService.DeleteArticle(r.Context(), slug, claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "article not found":
@@ -236,6 +258,39 @@ func (h *ArticleHandler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"message":"Article deleted successfully"}`))
 }
 
+// wantsBodyHTML reports whether a request asks for the rendered body of
+// articles with ?bodyHtml=true
+func wantsBodyHTML(r *http.Request) bool {
+	bodyHTML, _ := strconv.ParseBool(r.URL.Query().Get("bodyHtml"))
+	return bodyHTML
+}
+
+// isPublicationError reports whether err rejects the status or publish
+// time of an article
+func isPublicationError(err error) bool {
+	switch err.Error() {
+	case "status must be draft, scheduled or published",
+		"publishAt is required for scheduled articles",
+		"publishAt must be in the future",
+		"publishAt is only allowed for scheduled articles":
+		return true
+	}
+	return false
+}
+
+// parseListTime parses an RFC 3339 time or a date from a list filter. An
+// empty value is the zero time.
+func parseListTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	t, err := time.Parse("2006-01-02", value)
+	return t, err == nil
+}
+
 // GetArticles handles global article list retrieval with filtering and pagination
 func (h *ArticleHandler) GetArticles(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -244,7 +299,7 @@ func (h *ArticleHandler) GetArticles(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse query parameters
-	params := service.ArticleListParams{}
+	params := service.ArticleListParams{BodyHTML: wantsBodyHTML(r)}
 
 	// Parse limit
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
@@ -259,12 +314,26 @@ func (h *ArticleHandler) GetArticles(w http.ResponseWriter, r *http.Request) {
 			params.Offset = offset
 		}
 	}
+	params.Cursor = r.URL.Query().Get("cursor")
+	params.Sort = r.URL.Query().Get("sort")
 
-	// Parse filters
-	params.Tag = r.URL.Query().Get("tag")
-	params.Author = r.URL.Query().Get("author")
+	// Parse filters; tag, excludeTag and author may repeat
+	params.Tags = r.URL.Query()["tag"]
+	params.TagMode = r.URL.Query().Get("tagMode")
+	params.ExcludeTags = r.URL.Query()["excludeTag"]
+	params.Authors = r.URL.Query()["author"]
 	params.Favorited = r.URL.Query().Get("favorited")
 
+	var ok bool
+	if params.Since, ok = parseListTime(r.URL.Query().Get("since")); !ok {
+		http.Error(w, `{"error":"Invalid since"}`, http.StatusBadRequest)
+		return
+	}
+	if params.Until, ok = parseListTime(r.URL.Query().Get("until")); !ok {
+		http.Error(w, `{"error":"Invalid until"}`, http.StatusBadRequest)
+		return
+	}
+
 	// Get current user ID (optional for this endpoint)
 	var currentUserID int
 	if claims, ok := middleware.GetUserFromContext(r); ok {
@@ -272,13 +341,28 @@ func (h *ArticleHandler) GetArticles(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get articles
-	response, err := h.articleService.GetArticles(params, currentUserID)
+	response, err := h.articleService.GetArticles(r.Context(), params, currentUserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
+		if err.Error() == "invalid cursor" {
+			http.Error(w, `{"error":"Invalid cursor"}`, http.StatusBadRequest)
+			return
+		}
+		if err.Error() == "invalid sort" {
+			http.Error(w, `{"error":"Invalid sort"}`, http.StatusBadRequest)
+			return
+		}
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "tag mode must be any or all" || err.Error() == "since must be before until" {
+			statusCode = http.StatusBadRequest
+		}
 		errorResponse := map[string]interface{}{
 			"error": err.Error(),
 		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(statusCode)
 		json.NewEncoder(w).Encode(errorResponse)
 		return
 	}
@@ -303,7 +387,7 @@ func (h *ArticleHandler) GetArticlesFeed(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Parse query parameters
-	params := service.ArticleListParams{}
+	params := service.ArticleListParams{BodyHTML: wantsBodyHTML(r)}
 
 	// Parse limit
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
@@ -318,10 +402,139 @@ func (h *ArticleHandler) GetArticlesFeed(w http.ResponseWriter, r *http.Request)
 			params.Offset = offset
 		}
 	}
+	params.Cursor = r.URL.Query().Get("cursor")
+	params.Sort = r.URL.Query().Get("sort")
 
 	// Get feed articles
-	response, err := h.articleService.GetArticlesFeed(params, claims.UserID)
+	response, err := h.articleService.GetArticlesFeed(r.Context(), params, claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
+		if err.Error() == "invalid cursor" {
+			http.Error(w, `{"error":"Invalid cursor"}`, http.StatusBadRequest)
+			return
+		}
+		if err.Error() == "invalid sort" {
+			http.Error(w, `{"error":"Invalid sort"}`, http.StatusBadRequest)
+			return
+		}
+		errorResponse := map[string]interface{}{
+			"error": err.Error(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetDrafts handles listing the current user's drafts and scheduled articles
+func (h *ArticleHandler) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Authentication required for drafts
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		http.Error(w, `{"error":"Authentication required"}`, http.StatusUnauthorized)
+		return
+	}
+
+	// Parse query parameters
+	params := service.ArticleListParams{BodyHTML: wantsBodyHTML(r)}
+
+	// Parse limit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			params.Limit = limit
+		}
+	}
+
+	// Parse offset
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			params.Offset = offset
+		}
+	}
+	params.Cursor = r.URL.Query().Get("cursor")
+	params.Sort = r.URL.Query().Get("sort")
+
+	// Get drafts
+	response, err := h.articleService.GetDrafts(r.Context(), params, claims.UserID)
+	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
+		if err.Error() == "invalid cursor" {
+			http.Error(w, `{"error":"Invalid cursor"}`, http.StatusBadRequest)
+			return
+		}
+		if err.Error() == "invalid sort" {
+			http.Error(w, `{"error":"Invalid sort"}`, http.StatusBadRequest)
+			return
+		}
+		errorResponse := map[string]interface{}{
+			"error": err.Error(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// SearchArticles handles full-text article search
+func (h *ArticleHandler) SearchArticles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, `{"error":"Search query is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	// Parse query parameters
+	params := service.ArticleListParams{BodyHTML: wantsBodyHTML(r)}
+
+	// Parse limit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			params.Limit = limit
+		}
+	}
+
+	// Parse offset
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			params.Offset = offset
+		}
+	}
+
+	// Get current user ID (optional for this endpoint)
+	var currentUserID int
+	if claims, ok := middleware.GetUserFromContext(r); ok {
+		currentUserID = claims.UserID
+	}
+
+	response, err := h.articleService.SearchArticles(r.Context(), query, params, currentUserID)
+	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		errorResponse := map[string]interface{}{
 			"error": err.Error(),
 		}
@@ -354,8 +567,11 @@ func (h *ArticleHandler) FavoriteArticle(w http.ResponseWriter, r *http.Request)
 	slug := vars["slug"]
 
 	// Favorite the article
-	articleResponse, err := h.articleService.FavoriteArticle(slug, claims.UserID)
+	articleResponse, err := h.articleService.FavoriteArticle(r.Context(), slug, claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "failed to get article: article not found":
@@ -395,8 +611,11 @@ func (h *ArticleHandler) UnfavoriteArticle(w http.ResponseWriter, r *http.Reques
 	slug := vars["slug"]
 
 	// Unfavorite the article
-	articleResponse, err := h.articleService.UnfavoriteArticle(slug, claims.UserID)
+	articleResponse, err := h.articleService.UnfavoriteArticle(r.Context(), slug, claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "failed to get article: article not found":
@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/middleware"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/utils"
+)
+
+const dragonArticle = `{"article":{"title":"How to train your dragon","description":"Ever wonder how?","body":"You have to believe","tagList":["dragons"]}}`
+
+// createArticle creates the dragon article through the handler and returns
+// its slug
+func createArticle(t *testing.T, h *testHandlers, authorID int) string {
+	t.Helper()
+
+	rec := serve(h.articles.CreateArticle, http.MethodPost, dragonArticle, nil, authorID)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreateArticle() status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var response model.ArticleResponseWrapper
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode article: %v", err)
+	}
+	return response.Article.Slug
+}
+
+func TestCreateArticleStatus(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+
+	tests := []struct {
+		name   string
+		body   string
+		userID int
+		want   int
+	}{
+		{"created", dragonArticle, jake, http.StatusCreated},
+		{"anonymous", dragonArticle, 0, http.StatusUnauthorized},
+		{"invalid JSON", `{"article":`, jake, http.StatusBadRequest},
+		{"missing title", `{"article":{"description":"d","body":"b"}}`, jake, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(h.articles.CreateArticle, http.MethodPost, tt.body, nil, tt.userID)
+			if rec.Code != tt.want {
+				t.Errorf("CreateArticle() status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestArticleHandlerStatus(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+	celeb := h.register(t, "celeb")
+	slug := createArticle(t, h, jake)
+
+	update := `{"article":{"body":"With love"}}`
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		body    string
+		slug    string
+		userID  int
+		want    int
+	}{
+		{"get", h.articles.GetArticle, http.MethodGet, "", slug, 0, http.StatusOK},
+		{"get missing", h.articles.GetArticle, http.MethodGet, "", "missing", 0, http.StatusNotFound},
+		{"update by another user", h.articles.UpdateArticle, http.MethodPut, update, slug, celeb, http.StatusForbidden},
+		{"update missing", h.articles.UpdateArticle, http.MethodPut, update, "missing", jake, http.StatusNotFound},
+		{"update empty body", h.articles.UpdateArticle, http.MethodPut, `{"article":{"body":""}}`, slug, jake, http.StatusBadRequest},
+		{"update", h.articles.UpdateArticle, http.MethodPut, update, slug, jake, http.StatusOK},
+		{"favorite anonymously", h.articles.FavoriteArticle, http.MethodPost, "", slug, 0, http.StatusUnauthorized},
+		{"favorite", h.articles.FavoriteArticle, http.MethodPost, "", slug, celeb, http.StatusOK},
+		{"delete by another user", h.articles.DeleteArticle, http.MethodDelete, "", slug, celeb, http.StatusForbidden},
+		{"delete", h.articles.DeleteArticle, http.MethodDelete, "", slug, jake, http.StatusOK},
+		{"delete again", h.articles.DeleteArticle, http.MethodDelete, "", slug, jake, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.handler, tt.method, tt.body, map[string]string{"slug": tt.slug}, tt.userID)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestArticleHandlerContextErrors(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+	slug := createArticle(t, h, jake)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		{"canceled", canceled, StatusClientClosedRequest},
+		{"deadline exceeded", expired, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveContext(tt.ctx, h.articles.GetArticle, http.MethodGet, "", map[string]string{"slug": slug}, 0)
+			if rec.Code != tt.want {
+				t.Errorf("GetArticle() status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+			if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("GetArticle() Content-Type = %q, want application/json", contentType)
+			}
+
+			rec = serveContext(tt.ctx, h.articles.GetArticles, http.MethodGet, "", nil, 0)
+			if rec.Code != tt.want {
+				t.Errorf("GetArticles() status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestSearchArticles(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+	createArticle(t, h, jake)
+
+	tests := []struct {
+		name      string
+		query     string
+		want      int
+		wantCount int
+	}{
+		{"match", "?q=dragon", http.StatusOK, 1},
+		{"match on tag", "?q=dragons&limit=5", http.StatusOK, 1},
+		{"no match", "?q=wyvern", http.StatusOK, 0},
+		{"missing query", "", http.StatusBadRequest, 0},
+		{"blank query", "?q=%20%20", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/articles/search"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.articles.SearchArticles(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("SearchArticles() status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var response model.ArticlesResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode articles: %v", err)
+			}
+			if response.ArticlesCount != tt.wantCount || len(response.Articles) != tt.wantCount {
+				t.Fatalf("SearchArticles() = %d of %d articles, want %d", len(response.Articles), response.ArticlesCount, tt.wantCount)
+			}
+			if tt.wantCount > 0 && (response.Articles[0].Author.Username != "jake" || !strings.Contains(response.Articles[0].Snippet, "<mark>")) {
+				t.Errorf("SearchArticles() article = %+v, want jake's article with a highlighted snippet", response.Articles[0])
+			}
+		})
+	}
+}
+
+func TestArticleListParameters(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+	createArticle(t, h, jake)
+	createArticle(t, h, jake)
+
+	list := func(handler http.HandlerFunc, query string, userID int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/articles"+query, nil)
+		if userID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, &utils.Claims{UserID: userID}))
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := list(h.articles.GetArticles, "?limit=1", 0)
+	var first model.ArticlesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&first); err != nil || first.NextCursor == "" {
+		t.Fatalf("GetArticles(limit=1) = %+v, %v; want a next cursor", first, err)
+	}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		query   string
+		userID  int
+		want    int
+	}{
+		{"next page", h.articles.GetArticles, "?limit=1&cursor=" + first.NextCursor, 0, http.StatusOK},
+		{"invalid cursor", h.articles.GetArticles, "?cursor=garbage", 0, http.StatusBadRequest},
+		{"feed invalid cursor", h.articles.GetArticlesFeed, "?cursor=garbage", jake, http.StatusBadRequest},
+		{"cursor of another sort", h.articles.GetArticles, "?sort=oldest&cursor=" + first.NextCursor, 0, http.StatusBadRequest},
+		{"sorted", h.articles.GetArticles, "?sort=most_favorited", 0, http.StatusOK},
+		{"invalid sort", h.articles.GetArticles, "?sort=random", 0, http.StatusBadRequest},
+		{"feed invalid sort", h.articles.GetArticlesFeed, "?sort=random", jake, http.StatusBadRequest},
+		{"filtered", h.articles.GetArticles, "?tag=go&tag=sql&tagMode=all&excludeTag=rust&author=jake&author=celeb&since=2024-01-01&until=2030-01-01T00:00:00Z", 0, http.StatusOK},
+		{"invalid tag mode", h.articles.GetArticles, "?tag=go&tagMode=some", 0, http.StatusBadRequest},
+		{"invalid since", h.articles.GetArticles, "?since=yesterday", 0, http.StatusBadRequest},
+		{"invalid until", h.articles.GetArticles, "?until=2024-13-01", 0, http.StatusBadRequest},
+		{"since after until", h.articles.GetArticles, "?since=2024-02-01&until=2024-01-01", 0, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := list(tt.handler, tt.query, tt.userID); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestArticleDraftHandlers(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+	celeb := h.register(t, "celeb")
+
+	rec := serve(h.articles.CreateArticle, http.MethodPost, `{"article":{"title":"Secret","description":"d","body":"b","status":"draft"}}`, nil, jake)
+	var draft model.ArticleResponseWrapper
+	if err := json.NewDecoder(rec.Body).Decode(&draft); err != nil || rec.Code != http.StatusCreated || draft.Article.Status != model.ArticleDraft {
+		t.Fatalf("CreateArticle(draft) = %d, %+v, %v; want a created draft", rec.Code, draft.Article, err)
+	}
+	slug := draft.Article.Slug
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		body    string
+		userID  int
+		want    int
+	}{
+		{"create unknown status", h.articles.CreateArticle, http.MethodPost, `{"article":{"title":"t","description":"d","body":"b","status":"archived"}}`, jake, http.StatusBadRequest},
+		{"create scheduled without time", h.articles.CreateArticle, http.MethodPost, `{"article":{"title":"t","description":"d","body":"b","status":"scheduled"}}`, jake, http.StatusBadRequest},
+		{"create scheduled in the past", h.articles.CreateArticle, http.MethodPost, `{"article":{"title":"t","description":"d","body":"b","status":"scheduled","publishAt":"2020-01-01T00:00:00Z"}}`, jake, http.StatusBadRequest},
+		{"get by another user", h.articles.GetArticle, http.MethodGet, "", celeb, http.StatusNotFound},
+		{"get anonymously", h.articles.GetArticle, http.MethodGet, "", 0, http.StatusNotFound},
+		{"get by author", h.articles.GetArticle, http.MethodGet, "", jake, http.StatusOK},
+		{"update by another user", h.articles.UpdateArticle, http.MethodPut, `{"article":{"status":"published"}}`, celeb, http.StatusNotFound},
+		{"schedule", h.articles.UpdateArticle, http.MethodPut, `{"article":{"status":"scheduled","publishAt":"` + future + `"}}`, jake, http.StatusOK},
+		{"publish", h.articles.UpdateArticle, http.MethodPut, `{"article":{"status":"published"}}`, jake, http.StatusOK},
+		{"unpublish", h.articles.UpdateArticle, http.MethodPut, `{"article":{"status":"draft"}}`, jake, http.StatusBadRequest},
+		{"get published", h.articles.GetArticle, http.MethodGet, "", 0, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.handler, tt.method, tt.body, map[string]string{"slug": slug}, tt.userID)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	serve(h.articles.CreateArticle, http.MethodPost, `{"article":{"title":"Later","description":"d","body":"b","status":"scheduled","publishAt":"`+future+`"}}`, nil, jake)
+	rec = serve(h.articles.GetDrafts, http.MethodGet, "", nil, jake)
+	var drafts model.ArticlesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&drafts); err != nil || rec.Code != http.StatusOK || drafts.ArticlesCount != 1 || drafts.Articles[0].Status != model.ArticleScheduled {
+		t.Errorf("GetDrafts() = %d, %+v, %v; want the scheduled article", rec.Code, drafts, err)
+	}
+	if rec := serve(h.articles.GetDrafts, http.MethodGet, "", nil, 0); rec.Code != http.StatusUnauthorized {
+		t.Errorf("GetDrafts() anonymously status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRenderedBodyHandlers(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+	slug := createArticle(t, h, jake)
+	body := `{"article":{"body":"You have to **believe**<script>alert(1)</script>"}}`
+	if rec := serve(h.articles.UpdateArticle, http.MethodPut, body, map[string]string{"slug": slug}, jake); rec.Code != http.StatusOK {
+		t.Fatalf("UpdateArticle() status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	request := func(handler http.HandlerFunc, query string) map[string]interface{} {
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/"+query, nil), map[string]string{"slug": slug})
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		var response struct {
+			Article  map[string]interface{}   `json:"article"`
+			Articles []map[string]interface{} `json:"articles"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, error = %v", rec.Code, err)
+		}
+		if response.Article != nil {
+			return response.Article
+		}
+		if len(response.Articles) != 1 {
+			t.Fatalf("articles = %v, want one", response.Articles)
+		}
+		return response.Articles[0]
+	}
+
+	want := "<p>You have to <strong>believe</strong>alert(1)</p>\n"
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		query    string
+		bodyHTML interface{}
+	}{
+		{"get", h.articles.GetArticle, "", nil},
+		{"get rendered", h.articles.GetArticle, "?bodyHtml=true", want},
+		{"get not rendered", h.articles.GetArticle, "?bodyHtml=false", nil},
+		{"list", h.articles.GetArticles, "", nil},
+		{"list rendered", h.articles.GetArticles, "?bodyHtml=1", want},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := request(tt.handler, tt.query)
+			if article["bodyHtml"] != tt.bodyHTML {
+				t.Errorf("bodyHtml = %v, want %v", article["bodyHtml"], tt.bodyHTML)
+			}
+			if _, ok := article["excerpt"]; ok != (tt.bodyHTML != nil) {
+				t.Errorf("excerpt = %v, want one only with bodyHtml", article["excerpt"])
+			}
+		})
+	}
+}
@@ -42,8 +42,11 @@ func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
 		currentUserID = claims.UserID
 	}
 
-	comments, err := h.commentService.GetCommentsByArticleSlug(slug, currentUserID)
+	comments, err := h.commentService.GetCommentsByArticleSlug(r.Context(), slug, currentUserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch err.Error() {
 		case "article not found":
@@ -93,8 +96,11 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create comment
-	comment, err := h.commentService.CreateComment(slug, req.Comment.Body, claims.UserID)
+	comment, err := h.commentService.CreateComment(r.Context(), slug, req.Comment.Body, claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "failed to find article: article not found":
@@ -140,8 +146,11 @@ func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete comment
-	err = h.commentService.DeleteComment(commentID, claims.UserID)
+	err = h.commentService.DeleteComment(r.Context(), commentID, claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "failed to get comment: comment not found":
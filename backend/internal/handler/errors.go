@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// StatusClientClosedRequest is the non-standard status (popularized by nginx)
+// for requests the client abandoned before a response was written
+const StatusClientClosedRequest = 499
+
+// writeContextError writes the response for errors caused by the request's
+// context ending: 499 when the client went away and 503 when the request ran
+// past its database deadline. It reports whether err was such an error.
+func writeContextError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled):
+		writeJSONError(w, "Request canceled", StatusClientClosedRequest)
+	case errors.Is(err, context.DeadlineExceeded):
+		writeJSONError(w, "Database request timed out", http.StatusServiceUnavailable)
+	default:
+		return false
+	}
+	return true
+}
+
+// writeJSONError writes an {"error": message} response with the given status
+func writeJSONError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": message,
+	})
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/middleware"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository/memory"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/service"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/utils"
+)
+
+const testJWTSecret = "test-secret"
+
+// testHandlers wires the handlers to services backed by one in-memory store
+type testHandlers struct {
+	users    *UserHandler
+	articles *ArticleHandler
+	comments *CommentHandler
+	profiles *ProfileHandler
+
+	userService *service.UserService
+}
+
+// newTestHandlers creates handlers over an empty in-memory store
+func newTestHandlers() *testHandlers {
+	store := memory.NewStore()
+	userService := service.NewUserService(store.Users())
+	tagService := service.NewTagService(store.Tags())
+
+	return &testHandlers{
+		users:       NewUserHandler(userService, testJWTSecret),
+		articles:    NewArticleHandler(service.NewArticleService(store, store.Articles(), store.Users(), tagService)),
+		comments:    NewCommentHandler(service.NewCommentService(store.Comments(), store.Articles(), store.Users())),
+		profiles:    NewProfileHandler(service.NewProfileService(store.Users())),
+		userService: userService,
+	}
+}
+
+// register creates a user and returns its ID
+func (h *testHandlers) register(t *testing.T, username string) int {
+	t.Helper()
+
+	var req model.CreateUserRequest
+	req.User.Email = username + "@example.com"
+	req.User.Username = username
+	req.User.Password = "password1"
+
+	user, err := h.userService.CreateUser(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to register %s: %v", username, err)
+	}
+	return user.ID
+}
+
+// serve calls handler with a request carrying the path variables and, when
+// userID is not zero, the claims the JWT middleware would have added
+func serve(handler http.HandlerFunc, method, body string, vars map[string]string, userID int) *httptest.ResponseRecorder {
+	return serveContext(context.Background(), handler, method, body, vars, userID)
+}
+
+// serveContext is serve with the request bound to ctx
+func serveContext(ctx context.Context, handler http.HandlerFunc, method, body string, vars map[string]string, userID int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body)).WithContext(ctx)
+	if userID != 0 {
+		claims := &utils.Claims{UserID: userID}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+	}
+	req = mux.SetURLVars(req, vars)
+
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
@@ -4,32 +4,72 @@ import (
 	"encoding/json"
 	"net/http"
 	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
 )
 
+// MigrationStatusProvider reports the state of database migrations
+type MigrationStatusProvider interface {
+	MigrationStatus() (*db.MigrationStatus, error)
+}
+
 // HealthHandler handles health check endpoints
-type HealthHandler struct{}
+type HealthHandler struct {
+	migrations MigrationStatusProvider
+}
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(migrations MigrationStatusProvider) *HealthHandler {
+	return &HealthHandler{
+		migrations: migrations,
+	}
 }
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
-	Service   string    `json:"service"`
+	Status     string                  `json:"status"`
+	Timestamp  time.Time               `json:"timestamp"`
+	Service    string                  `json:"service"`
+	Migrations *MigrationHealthSummary `json:"migrations,omitempty"`
 }
 
-// Check handles the health check endpoint
+// MigrationHealthSummary summarizes migration state for health checks
+type MigrationHealthSummary struct {
+	Applied  int      `json:"applied"`
+	Pending  int      `json:"pending"`
+	Modified []string `json:"modified"`
+	Missing  []string `json:"missing"`
+}
+
+// Check handles the health check endpoint. Migration drift reports a
+// "degraded" status; failing to read migration state reports 503.
 func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 	response := HealthResponse{
 		Status:    "ok",
 		Timestamp: time.Now(),
 		Service:   "realworld-backend",
 	}
+	statusCode := http.StatusOK
+
+	if h.migrations != nil {
+		status, err := h.migrations.MigrationStatus()
+		if err != nil {
+			response.Status = "unavailable"
+			statusCode = http.StatusServiceUnavailable
+		} else {
+			response.Migrations = &MigrationHealthSummary{
+				Applied:  status.Count(db.MigrationApplied),
+				Pending:  status.Count(db.MigrationPending),
+				Modified: status.Filenames(db.MigrationModified),
+				Missing:  status.Filenames(db.MigrationMissing),
+			}
+			if status.Drifted() {
+				response.Status = "degraded"
+			}
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(response)
 }
@@ -38,8 +38,11 @@ func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		currentUserID = &claims.UserID
 	}
 
-	profile, err := h.profileService.GetProfile(username, currentUserID)
+	profile, err := h.profileService.GetProfile(r.Context(), username, currentUserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch err.Error() {
 		case "failed to get profile: user not found":
@@ -75,8 +78,11 @@ func (h *ProfileHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	username := vars["username"]
 
-	profile, err := h.profileService.FollowUser(claims.UserID, username)
+	profile, err := h.profileService.FollowUser(r.Context(), claims.UserID, username)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "user not found: user not found":
@@ -116,8 +122,11 @@ func (h *ProfileHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	username := vars["username"]
 
-	profile, err := h.profileService.UnfollowUser(claims.UserID, username)
+	profile, err := h.profileService.UnfollowUser(r.Context(), claims.UserID, username)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		var statusCode int
 		switch {
 		case err.Error() == "user not found: user not found":
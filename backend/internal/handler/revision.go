@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/middleware"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// GetRevisions handles listing the revisions of an article
+func (h *ArticleHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Only the author may see the revisions
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		http.Error(w, `{"error":"Authentication required"}`, http.StatusUnauthorized)
+		return
+	}
+
+	revisions, err := h.articleService.GetRevisions(r.Context(), mux.Vars(r)["slug"], claims.UserID)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// GetRevision handles retrieving one revision of an article
+func (h *ArticleHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Only the author may see the revisions
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		http.Error(w, `{"error":"Authentication required"}`, http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	number, err := strconv.Atoi(vars["revision"])
+	if err != nil || number < 1 {
+		http.Error(w, `{"error":"Invalid revision"}`, http.StatusBadRequest)
+		return
+	}
+
+	revision, err := h.articleService.GetRevision(r.Context(), vars["slug"], number, claims.UserID)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.RevisionResponseWrapper{Revision: *revision})
+}
+
+// DiffRevisions handles diffing two revisions of an article, given as the
+// from and to query parameters. Without to, the diff is to the current
+// article.
+func (h *ArticleHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Only the author may see the revisions
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		http.Error(w, `{"error":"Authentication required"}`, http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := strconv.Atoi(query.Get("from"))
+	if err != nil || from < 1 {
+		http.Error(w, `{"error":"Invalid from revision"}`, http.StatusBadRequest)
+		return
+	}
+	var to int
+	if value := query.Get("to"); value != "" {
+		to, err = strconv.Atoi(value)
+		if err != nil || to < 1 {
+			http.Error(w, `{"error":"Invalid to revision"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	diff, err := h.articleService.DiffRevisions(r.Context(), mux.Vars(r)["slug"], from, to, claims.UserID)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.RevisionDiffResponse{Diff: *diff})
+}
+
+// RestoreRevision handles restoring an earlier revision of an article
+func (h *ArticleHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Only the author may restore a revision
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		http.Error(w, `{"error":"Authentication required"}`, http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	number, err := strconv.Atoi(vars["revision"])
+	if err != nil || number < 1 {
+		http.Error(w, `{"error":"Invalid revision"}`, http.StatusBadRequest)
+		return
+	}
+
+	article, err := h.articleService.RestoreRevision(r.Context(), vars["slug"], number, claims.UserID)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.ArticleResponseWrapper{Article: *article})
+}
+
+// writeRevisionError writes the error response of a revision request
+func writeRevisionError(w http.ResponseWriter, err error) {
+	if writeContextError(w, err) {
+		return
+	}
+
+	var statusCode int
+	switch {
+	case err.Error() == "article not found" || err.Error() == "revision not found":
+		statusCode = http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		statusCode = http.StatusForbidden
+	default:
+		statusCode = http.StatusInternalServerError
+	}
+
+	writeJSONError(w, err.Error(), statusCode)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/middleware"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/utils"
+)
+
+func TestRevisionHandlers(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+	celeb := h.register(t, "celeb")
+	slug := createArticle(t, h, jake)
+
+	if rec := serve(h.articles.UpdateArticle, http.MethodPut, `{"article":{"body":"With love"}}`, map[string]string{"slug": slug}, jake); rec.Code != http.StatusOK {
+		t.Fatalf("UpdateArticle() status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	request := func(handler http.HandlerFunc, method, query, revision string, userID int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/"+query, nil)
+		if userID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, &utils.Claims{UserID: userID}))
+		}
+		req = mux.SetURLVars(req, map[string]string{"slug": slug, "revision": revision})
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := request(h.articles.GetRevisions, http.MethodGet, "", "", jake)
+	var revisions model.RevisionsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&revisions); err != nil || rec.Code != http.StatusOK || revisions.RevisionsCount != 1 {
+		t.Fatalf("GetRevisions() = %d, %+v, %v; want one revision", rec.Code, revisions, err)
+	}
+
+	rec = request(h.articles.DiffRevisions, http.MethodGet, "?from=1", "", jake)
+	var diff model.RevisionDiffResponse
+	if err := json.NewDecoder(rec.Body).Decode(&diff); err != nil || rec.Code != http.StatusOK || diff.Diff.Diff == "" {
+		t.Errorf("DiffRevisions(from=1) = %d, %+v, %v; want a diff to the current article", rec.Code, diff, err)
+	}
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		method   string
+		query    string
+		revision string
+		userID   int
+		want     int
+	}{
+		{"list anonymously", h.articles.GetRevisions, http.MethodGet, "", "", 0, http.StatusUnauthorized},
+		{"list by another user", h.articles.GetRevisions, http.MethodGet, "", "", celeb, http.StatusForbidden},
+		{"get", h.articles.GetRevision, http.MethodGet, "", "1", jake, http.StatusOK},
+		{"get missing", h.articles.GetRevision, http.MethodGet, "", "2", jake, http.StatusNotFound},
+		{"get invalid", h.articles.GetRevision, http.MethodGet, "", "0", jake, http.StatusBadRequest},
+		{"diff without from", h.articles.DiffRevisions, http.MethodGet, "", "", jake, http.StatusBadRequest},
+		{"diff invalid to", h.articles.DiffRevisions, http.MethodGet, "?from=1&to=latest", "", jake, http.StatusBadRequest},
+		{"diff missing revision", h.articles.DiffRevisions, http.MethodGet, "?from=1&to=7", "", jake, http.StatusNotFound},
+		{"diff by another user", h.articles.DiffRevisions, http.MethodGet, "?from=1", "", celeb, http.StatusForbidden},
+		{"restore by another user", h.articles.RestoreRevision, http.MethodPost, "", "1", celeb, http.StatusForbidden},
+		{"restore missing", h.articles.RestoreRevision, http.MethodPost, "", "9", jake, http.StatusNotFound},
+		{"restore", h.articles.RestoreRevision, http.MethodPost, "", "1", jake, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := request(tt.handler, tt.method, tt.query, tt.revision, tt.userID); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
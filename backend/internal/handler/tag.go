@@ -41,8 +41,11 @@ func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get popular tags
-	tags, err := h.tagService.GetPopularTags(limit)
+	tags, err := h.tagService.GetPopularTags(r.Context(), limit)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		errorResponse := map[string]interface{}{
 			"error": err.Error(),
 		}
@@ -74,8 +77,11 @@ func (h *TagHandler) GetAllTags(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get all tags
-	tags, err := h.tagService.GetAllTags()
+	tags, err := h.tagService.GetAllTags(r.Context())
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		errorResponse := map[string]interface{}{
 			"error": err.Error(),
 		}
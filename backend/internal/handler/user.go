@@ -39,8 +39,11 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create user
-	user, err := h.userService.CreateUser(req)
+	user, err := h.userService.CreateUser(r.Context(), req)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		// Handle specific validation errors
 		var statusCode int
 		switch {
@@ -98,8 +101,11 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Authenticate user
-	user, err := h.userService.AuthenticateUser(req.User.Email, req.User.Password)
+	user, err := h.userService.AuthenticateUser(r.Context(), req.User.Email, req.User.Password)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		http.Error(w, `{"error":"Invalid email or password"}`, http.StatusUnauthorized)
 		return
 	}
@@ -137,8 +143,11 @@ func (h *UserHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get user from database
-	user, err := h.userService.GetUserByID(claims.UserID)
+	user, err := h.userService.GetUserByID(r.Context(), claims.UserID)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		http.Error(w, `{"error":"User not found"}`, http.StatusNotFound)
 		return
 	}
@@ -183,8 +192,11 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update user
-	user, err := h.userService.UpdateUser(claims.UserID, req)
+	user, err := h.userService.UpdateUser(r.Context(), claims.UserID, req)
 	if err != nil {
+		if writeContextError(w, err) {
+			return
+		}
 		// Handle specific validation errors
 		var statusCode int
 		switch {
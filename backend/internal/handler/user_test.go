@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/utils"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	h := newTestHandlers()
+
+	register := `{"user":{"email":"jake@example.com","username":"jake","password":"password1"}}`
+	rec := serve(h.users.Register, http.MethodPost, register, nil, 0)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Register() status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var response struct {
+		User struct {
+			Username string `json:"username"`
+			Token    string `json:"token"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode user: %v", err)
+	}
+	if _, err := utils.ValidateToken(response.User.Token, testJWTSecret); err != nil || response.User.Username != "jake" {
+		t.Errorf("Register() = %+v, token error %v; want jake with a valid token", response.User, err)
+	}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+		want    int
+	}{
+		{"register duplicate", h.users.Register, register, http.StatusConflict},
+		{"register invalid", h.users.Register, `{"user":{"email":"x","username":"x","password":"x"}}`, http.StatusBadRequest},
+		{"login", h.users.Login, `{"user":{"email":"jake@example.com","password":"password1"}}`, http.StatusOK},
+		{"login wrong password", h.users.Login, `{"user":{"email":"jake@example.com","password":"wrong1"}}`, http.StatusUnauthorized},
+		{"login missing fields", h.users.Login, `{"user":{}}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.handler, http.MethodPost, tt.body, nil, 0)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestCommentAndProfileHandlerStatus(t *testing.T) {
+	h := newTestHandlers()
+	jake := h.register(t, "jake")
+	celeb := h.register(t, "celeb")
+	slug := createArticle(t, h, jake)
+
+	rec := serve(h.comments.CreateComment, http.MethodPost, `{"comment":{"body":"Nice"}}`, map[string]string{"slug": slug}, celeb)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreateComment() status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var created struct {
+		Comment struct {
+			ID int `json:"id"`
+		} `json:"comment"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode comment: %v", err)
+	}
+	commentVars := map[string]string{"slug": slug, "id": strconv.Itoa(created.Comment.ID)}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		vars    map[string]string
+		userID  int
+		want    int
+	}{
+		{"list comments", h.comments.GetComments, http.MethodGet, map[string]string{"slug": slug}, 0, http.StatusOK},
+		{"delete comment by another user", h.comments.DeleteComment, http.MethodDelete, commentVars, jake, http.StatusForbidden},
+		{"delete comment", h.comments.DeleteComment, http.MethodDelete, commentVars, celeb, http.StatusOK},
+		{"delete comment again", h.comments.DeleteComment, http.MethodDelete, commentVars, celeb, http.StatusNotFound},
+		{"get profile", h.profiles.GetProfile, http.MethodGet, map[string]string{"username": "celeb"}, 0, http.StatusOK},
+		{"get missing profile", h.profiles.GetProfile, http.MethodGet, map[string]string{"username": "nobody"}, 0, http.StatusNotFound},
+		{"follow", h.profiles.FollowUser, http.MethodPost, map[string]string{"username": "celeb"}, jake, http.StatusOK},
+		{"follow anonymously", h.profiles.FollowUser, http.MethodPost, map[string]string{"username": "celeb"}, 0, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.handler, tt.method, "", tt.vars, tt.userID)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Package markdown renders article bodies from Markdown to sanitized HTML
+// and plaintext excerpts.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"golang.org/x/net/html"
+)
+
+// Version identifies the output of Render and Excerpt. Bump it whenever
+// the converter, the policy or the excerpts change so that cached renders
+// are redone.
+const Version = 1
+
+var (
+	// converter renders CommonMark with the GitHub extensions. It leaves
+	// out raw HTML and dangerous link destinations.
+	converter = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy allow-lists the tags and attributes of user content: links
+	// and images only with http, https or mailto URLs, rel="nofollow" on
+	// links, and no scripts, styles or event handlers
+	policy = bluemonday.UGCPolicy()
+)
+
+// blockElements separate the words of an excerpt
+var blockElements = map[string]bool{
+	"address": true, "blockquote": true, "br": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "hr": true, "li": true, "ol": true, "p": true,
+	"pre": true, "table": true, "td": true, "th": true, "tr": true, "ul": true,
+}
+
+// Render converts Markdown to HTML that is safe to embed in a page. Raw
+// HTML in the source is dropped by the converter, and the output is then
+// sanitized as well, so the policy holds whatever the converter emits.
+func Render(source string) (string, error) {
+	var out bytes.Buffer
+	if err := converter.Convert([]byte(source), &out); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	return policy.Sanitize(out.String()), nil
+}
+
+// Excerpt returns the text of rendered HTML on one line, cut at a word
+// boundary to at most limit characters, with an ellipsis when cut
+func Excerpt(rendered string, limit int) string {
+	var text strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(rendered))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// The end of the input; reading a string cannot fail otherwise
+			return truncate(strings.Join(strings.Fields(text.String()), " "), limit)
+		case html.TextToken:
+			text.Write(tokenizer.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			if name, _ := tokenizer.TagName(); blockElements[string(name)] {
+				text.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// truncate cuts text to at most limit characters, preferring the last word
+// boundary, and marks the cut with an ellipsis
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	// Leave room for the ellipsis, and drop a word cut in half unless it is
+	// the only one
+	runes := []rune(text)
+	cut := string(runes[:limit-1])
+	if runes[limit-1] != ' ' {
+		if space := strings.LastIndexByte(cut, ' '); space > len(cut)/2 {
+			cut = cut[:space]
+		}
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
@@ -0,0 +1,136 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "paragraph with emphasis",
+			source:   "Ever *wonder* **how**?",
+			expected: "<p>Ever <em>wonder</em> <strong>how</strong>?</p>\n",
+		},
+		{
+			name:     "heading and list",
+			source:   "# Dragons\n\n- fire\n- wings",
+			expected: "<h1>Dragons</h1>\n<ul>\n<li>fire</li>\n<li>wings</li>\n</ul>\n",
+		},
+		{
+			name:     "code is escaped",
+			source:   "`<b>` and\n\n```\n<script>\n```",
+			expected: "<p><code>&lt;b&gt;</code> and</p>\n<pre><code>&lt;script&gt;\n</code></pre>\n",
+		},
+		{
+			name:     "links get nofollow",
+			source:   "[dragons](https://example.com/dragons)",
+			expected: "<p><a href=\"https://example.com/dragons\" rel=\"nofollow\">dragons</a></p>\n",
+		},
+		{
+			name:     "raw script tags are dropped",
+			source:   "Hello<script>alert(1)</script>",
+			expected: "<p>Helloalert(1)</p>\n",
+		},
+		{
+			name:     "raw event handler is dropped",
+			source:   "<img src=\"x\" onerror=\"alert(1)\">",
+			expected: "\n",
+		},
+		{
+			name:     "javascript link is dropped",
+			source:   "[click](javascript:alert(1))",
+			expected: "<p>click</p>\n",
+		},
+		{
+			name:     "data image source is dropped",
+			source:   "![x](data:text/html;base64,PHNjcmlwdD4=)",
+			expected: "<p><img alt=\"x\"></p>\n",
+		},
+		{
+			name:     "table",
+			source:   "| a |\n|---|\n| b |",
+			expected: "<table>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>b</td>\n</tr>\n</tbody>\n</table>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		limit    int
+		expected string
+	}{
+		{
+			name:     "blocks are separated",
+			html:     "<h1>Dragons</h1>\n<p>Ever <em>wonder</em> how?</p><ul><li>fire</li><li>wings</li></ul>",
+			limit:    100,
+			expected: "Dragons Ever wonder how? fire wings",
+		},
+		{
+			name:     "entities are decoded",
+			html:     "<p>Fish &amp; chips &lt;3</p>",
+			limit:    100,
+			expected: "Fish & chips <3",
+		},
+		{
+			name:     "cut at a word boundary",
+			html:     "<p>How to train your dragon, the definitive guide</p>",
+			limit:    26,
+			expected: "How to train your dragon…",
+		},
+		{
+			name:     "long word is cut",
+			html:     "<p>Supercalifragilisticexpialidocious</p>",
+			limit:    10,
+			expected: "Supercali…",
+		},
+		{
+			name:     "multibyte characters",
+			html:     "<p>ドラゴンの育て方</p>",
+			limit:    5,
+			expected: "ドラゴン…",
+		},
+		{
+			name:     "empty",
+			html:     "",
+			limit:    10,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Excerpt(tt.html, tt.limit); result != tt.expected {
+				t.Errorf("Excerpt() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderedExcerpt(t *testing.T) {
+	rendered, err := Render("# Dragons\n\n<script>\nalert(1)\n</script>\n\nThey **breathe** fire.")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if excerpt := Excerpt(rendered, 100); excerpt != "Dragons They breathe fire." || strings.Contains(rendered, "script") {
+		t.Errorf("Render() = %q with excerpt %q, want the script left out", rendered, excerpt)
+	}
+}
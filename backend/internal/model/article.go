@@ -4,17 +4,28 @@ import (
 	"time"
 )
 
+// Article statuses. Only published articles are visible to other users.
+const (
+	ArticleDraft     = "draft"
+	ArticleScheduled = "scheduled" // published by the publisher at PublishAt
+	ArticlePublished = "published"
+)
+
 // Article represents an article in the database
 type Article struct {
-	ID             int       `json:"id" db:"id"`
-	Slug           string    `json:"slug" db:"slug"`
-	Title          string    `json:"title" db:"title"`
-	Description    string    `json:"description" db:"description"`
-	Body           string    `json:"body" db:"body"`
-	AuthorID       int       `json:"author_id" db:"author_id"`
-	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt      time.Time `json:"updatedAt" db:"updated_at"`
-	FavoritesCount int       `json:"favoritesCount" db:"favorites_count"`
+	ID             int        `json:"id" db:"id"`
+	Slug           string     `json:"slug" db:"slug"`
+	Title          string     `json:"title" db:"title"`
+	Description    string     `json:"description" db:"description"`
+	Body           string     `json:"body" db:"body"`
+	AuthorID       int        `json:"author_id" db:"author_id"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updatedAt" db:"updated_at"`
+	FavoritesCount int        `json:"favoritesCount" db:"favorites_count"`
+	CommentsCount  int        `json:"-" db:"-"` // only read by lists sorted by comments
+	Status         string     `json:"status" db:"status"`
+	PublishAt      *time.Time `json:"publishAt" db:"publish_at"` // due time when scheduled, publication time when published
+	Revision       int        `json:"revision" db:"revision"`    // version number, one more than the stored revisions
 }
 
 // ArticleResponse represents an article response for API
@@ -29,6 +40,11 @@ type ArticleResponse struct {
 	Favorited      bool          `json:"favorited"`
 	FavoritesCount int           `json:"favoritesCount"`
 	Author         AuthorProfile `json:"author"`
+	Status         string        `json:"status"`
+	PublishAt      *time.Time    `json:"publishAt,omitempty"`
+	Snippet        string        `json:"snippet,omitempty"`  // search results only
+	BodyHTML       string        `json:"bodyHtml,omitempty"` // sanitized HTML of Body, when asked for
+	Excerpt        string        `json:"excerpt,omitempty"`  // plaintext start of Body, with BodyHTML
 }
 
 // AuthorProfile represents an author in article responses
@@ -42,20 +58,24 @@ type AuthorProfile struct {
 // CreateArticleRequest represents a request to create an article
 type CreateArticleRequest struct {
 	Article struct {
-		Title       string   `json:"title" validate:"required,min=1"`
-		Description string   `json:"description" validate:"required,min=1"`
-		Body        string   `json:"body" validate:"required,min=1"`
-		TagList     []string `json:"tagList"`
+		Title       string     `json:"title" validate:"required,min=1"`
+		Description string     `json:"description" validate:"required,min=1"`
+		Body        string     `json:"body" validate:"required,min=1"`
+		TagList     []string   `json:"tagList"`
+		Status      string     `json:"status,omitempty"`    // published when empty
+		PublishAt   *time.Time `json:"publishAt,omitempty"` // required when scheduled
 	} `json:"article"`
 }
 
 // UpdateArticleRequest represents a request to update an article
 type UpdateArticleRequest struct {
 	Article struct {
-		Title       *string  `json:"title,omitempty"`
-		Description *string  `json:"description,omitempty"`
-		Body        *string  `json:"body,omitempty"`
-		TagList     []string `json:"tagList,omitempty"`
+		Title       *string    `json:"title,omitempty"`
+		Description *string    `json:"description,omitempty"`
+		Body        *string    `json:"body,omitempty"`
+		TagList     []string   `json:"tagList,omitempty"`
+		Status      *string    `json:"status,omitempty"`
+		PublishAt   *time.Time `json:"publishAt,omitempty"`
 	} `json:"article"`
 }
 
@@ -68,4 +88,22 @@ type ArticleResponseWrapper struct {
 type ArticlesResponse struct {
 	Articles      []ArticleResponse `json:"articles"`
 	ArticlesCount int               `json:"articlesCount"`
+	NextCursor    string            `json:"nextCursor,omitempty"` // pass as ?cursor= for the older articles
+	PrevCursor    string            `json:"prevCursor,omitempty"` // pass as ?cursor= for the newer articles
+}
+
+// SearchResult is an article matched by a full-text search
+type SearchResult struct {
+	Article Article
+	Snippet string // HTML-escaped excerpt with the matched words in <mark>
+}
+
+// ArticleRender is the rendered body of one revision of an article
+type ArticleRender struct {
+	ArticleID int       `db:"article_id"`
+	Revision  int       `db:"revision"`
+	Renderer  int       `db:"renderer"` // markdown.Version that rendered it
+	BodyHTML  string    `db:"body_html"`
+	Excerpt   string    `db:"excerpt"`
+	CreatedAt time.Time `db:"created_at"`
 }
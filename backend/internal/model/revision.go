@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// ArticleRevision is an earlier version of an article. Revision n holds the
+// fields and tags that the n-th update of the article replaced.
+type ArticleRevision struct {
+	ID          int       `json:"id" db:"id"`
+	ArticleID   int       `json:"-" db:"article_id"`
+	Revision    int       `json:"revision" db:"revision"`
+	EditorID    int       `json:"-" db:"editor_id"` // who made the update
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	Body        string    `json:"body" db:"body"`
+	TagList     []string  `json:"tagList" db:"tag_list"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"` // when the update was made
+}
+
+// RevisionResponse represents an article revision for API
+type RevisionResponse struct {
+	Revision    int       `json:"revision"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Body        string    `json:"body"`
+	TagList     []string  `json:"tagList"`
+	Editor      string    `json:"editor"` // username of who replaced this revision
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// RevisionResponseWrapper wraps a revision response
+type RevisionResponseWrapper struct {
+	Revision RevisionResponse `json:"revision"`
+}
+
+// RevisionsResponse represents the revisions of an article, newest first
+type RevisionsResponse struct {
+	Revisions      []RevisionResponse `json:"revisions"`
+	RevisionsCount int                `json:"revisionsCount"`
+}
+
+// RevisionDiff is a unified diff between two versions of an article
+type RevisionDiff struct {
+	From int    `json:"from"`
+	To   int    `json:"to"` // 0 is the current article
+	Diff string `json:"diff"`
+}
+
+// RevisionDiffResponse wraps a revision diff
+type RevisionDiffResponse struct {
+	Diff RevisionDiff `json:"diff"`
+}
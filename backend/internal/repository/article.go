@@ -1,46 +1,69 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
 )
 
+// articleColumns are the columns of articles a read into a model.Article,
+// in the order of articleDest
+const articleColumns = "a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at, a.favorites_count, a.status, a.publish_at, a.revision"
+
+// articleDest returns the scan destinations for articleColumns
+func articleDest(article *model.Article) []interface{} {
+	return []interface{}{
+		&article.ID, &article.Slug, &article.Title, &article.Description,
+		&article.Body, &article.AuthorID, &article.CreatedAt, &article.UpdatedAt,
+		&article.FavoritesCount, &article.Status, &article.PublishAt, &article.Revision,
+	}
+}
+
 // ArticleRepository handles article database operations
 type ArticleRepository struct {
-	db *sql.DB
+	db      db.Executor
+	reader  db.Executor // read replica, or the primary when none is configured
+	dialect db.Dialect
 }
 
 // NewArticleRepository creates a new article repository
-func NewArticleRepository(db *sql.DB) *ArticleRepository {
-	return &ArticleRepository{db: db}
+func NewArticleRepository(database *db.Database) *ArticleRepository {
+	return &ArticleRepository{db: database.Primary(), reader: database.ReadOnly(), dialect: database.Dialect()}
 }
 
 // Create creates a new article
-func (r *ArticleRepository) Create(article *model.Article) error {
+func (r *ArticleRepository) Create(ctx context.Context, article *model.Article) error {
 	query := `
-		INSERT INTO articles (slug, title, description, body, author_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO articles (slug, title, description, body, author_id, created_at, updated_at, status, publish_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	now := time.Now()
 	article.CreatedAt = now
 	article.UpdatedAt = now
 	article.FavoritesCount = 0
+	article.Revision = 1
 
-	result, err := r.db.Exec(query,
-		article.Slug, article.Title, article.Description, article.Body,
-		article.AuthorID, article.CreatedAt, article.UpdatedAt)
-	if err != nil {
-		return fmt.Errorf("failed to create article: %w", err)
+	// Articles are published on creation unless the caller says otherwise,
+	// and published articles are stamped with their creation time
+	if article.Status == "" {
+		article.Status = model.ArticlePublished
+	}
+	if article.Status == model.ArticlePublished {
+		article.PublishAt = &now
 	}
 
-	id, err := result.LastInsertId()
+	id, err := r.dialect.InsertReturningID(ctx, r.db, query,
+		article.Slug, article.Title, article.Description, article.Body,
+		article.AuthorID, article.CreatedAt, article.UpdatedAt,
+		article.Status, article.PublishAt)
 	if err != nil {
-		return fmt.Errorf("failed to get article ID: %w", err)
+		return fmt.Errorf("failed to create article: %w", err)
 	}
 
 	article.ID = int(id)
@@ -48,18 +71,11 @@ func (r *ArticleRepository) Create(article *model.Article) error {
 }
 
 // GetBySlug retrieves an article by slug
-func (r *ArticleRepository) GetBySlug(slug string) (*model.Article, error) {
-	query := `
-		SELECT id, slug, title, description, body, author_id, created_at, updated_at
-		FROM articles 
-		WHERE slug = ?
-	`
+func (r *ArticleRepository) GetBySlug(ctx context.Context, slug string) (*model.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles a WHERE a.slug = ?`
 
 	article := &model.Article{}
-	err := r.db.QueryRow(query, slug).Scan(
-		&article.ID, &article.Slug, &article.Title, &article.Description,
-		&article.Body, &article.AuthorID, &article.CreatedAt, &article.UpdatedAt,
-	)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), slug).Scan(articleDest(article)...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -68,18 +84,11 @@ func (r *ArticleRepository) GetBySlug(slug string) (*model.Article, error) {
 		return nil, fmt.Errorf("failed to get article: %w", err)
 	}
 
-	// Set default favorites count to 0 for now
-	article.FavoritesCount = 0
-
 	return article, nil
 }
 
 // Update updates an existing article
-func (r *ArticleRepository) Update(slug string, updates map[string]interface{}) (*model.Article, error) {
-	if len(updates) == 0 {
-		return r.GetBySlug(slug)
-	}
-
+func (r *ArticleRepository) Update(ctx context.Context, slug string, updates map[string]interface{}) (*model.Article, error) {
 	// Build dynamic update query
 	setParts := make([]string, 0, len(updates))
 	args := make([]interface{}, 0, len(updates)+2)
@@ -93,6 +102,9 @@ func (r *ArticleRepository) Update(slug string, updates map[string]interface{})
 	setParts = append(setParts, "updated_at = ?")
 	args = append(args, time.Now())
 
+	// Every update makes a new version of the article
+	setParts = append(setParts, "revision = revision + 1")
+
 	// Add slug as the last parameter for WHERE clause
 	args = append(args, slug)
 
@@ -102,19 +114,33 @@ func (r *ArticleRepository) Update(slug string, updates map[string]interface{})
 		WHERE slug = ?
 	`, strings.Join(setParts, ", "))
 
-	_, err := r.db.Exec(query, args...)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update article: %w", err)
 	}
 
-	return r.GetBySlug(slug)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("article not found")
+	}
+
+	// The article is found under its new slug when the update renamed it
+	if newSlug, ok := updates["slug"].(string); ok {
+		slug = newSlug
+	}
+
+	return r.GetBySlug(ctx, slug)
 }
 
 // Delete deletes an article by slug
-func (r *ArticleRepository) Delete(slug string) error {
+func (r *ArticleRepository) Delete(ctx context.Context, slug string) error {
 	query := `DELETE FROM articles WHERE slug = ?`
 
-	result, err := r.db.Exec(query, slug)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), slug)
 	if err != nil {
 		return fmt.Errorf("failed to delete article: %w", err)
 	}
@@ -132,7 +158,7 @@ func (r *ArticleRepository) Delete(slug string) error {
 }
 
 // GetArticleTags retrieves tags for an article
-func (r *ArticleRepository) GetArticleTags(articleID int) ([]string, error) {
+func (r *ArticleRepository) GetArticleTags(ctx context.Context, articleID int) ([]string, error) {
 	query := `
 		SELECT t.name 
 		FROM tags t
@@ -141,7 +167,7 @@ func (r *ArticleRepository) GetArticleTags(articleID int) ([]string, error) {
 		ORDER BY t.name
 	`
 
-	rows, err := r.db.Query(query, articleID)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), articleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get article tags: %w", err)
 	}
@@ -164,55 +190,46 @@ func (r *ArticleRepository) GetArticleTags(articleID int) ([]string, error) {
 }
 
 // SetArticleTags sets tags for an article
-func (r *ArticleRepository) SetArticleTags(articleID int, tagNames []string) error {
-	// Start transaction
-	tx, err := r.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Delete existing article tags
-	_, err = tx.Exec("DELETE FROM article_tags WHERE article_id = ?", articleID)
-	if err != nil {
-		return fmt.Errorf("failed to delete existing tags: %w", err)
-	}
+func (r *ArticleRepository) SetArticleTags(ctx context.Context, articleID int, tagNames []string) error {
+	return withinTx(ctx, r.db, func(tx db.Executor) error {
+		// Delete existing article tags
+		_, err := tx.ExecContext(ctx, r.dialect.Rebind("DELETE FROM article_tags WHERE article_id = ?"), articleID)
+		if err != nil {
+			return fmt.Errorf("failed to delete existing tags: %w", err)
+		}
 
-	// Add new tags
-	for _, tagName := range tagNames {
-		// Get or create tag
-		var tagID int
-		err = tx.QueryRow("SELECT id FROM tags WHERE name = ?", tagName).Scan(&tagID)
-		if err == sql.ErrNoRows {
-			// Create new tag
-			result, err := tx.Exec("INSERT INTO tags (name) VALUES (?)", tagName)
-			if err != nil {
-				return fmt.Errorf("failed to create tag %s: %w", tagName, err)
+		// Add new tags
+		for _, tagName := range tagNames {
+			// Get or create tag
+			var tagID int
+			err = tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT id FROM tags WHERE name = ?"), tagName).Scan(&tagID)
+			if err == sql.ErrNoRows {
+				// Create new tag
+				id, err := r.dialect.InsertReturningID(ctx, tx, "INSERT INTO tags (name) VALUES (?)", tagName)
+				if err != nil {
+					return fmt.Errorf("failed to create tag %s: %w", tagName, err)
+				}
+				tagID = int(id)
+			} else if err != nil {
+				return fmt.Errorf("failed to get tag %s: %w", tagName, err)
 			}
-			id, err := result.LastInsertId()
+
+			// Link article to tag
+			_, err = tx.ExecContext(ctx, r.dialect.Rebind("INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?)"), articleID, tagID)
 			if err != nil {
-				return fmt.Errorf("failed to get tag ID: %w", err)
+				return fmt.Errorf("failed to link article to tag %s: %w", tagName, err)
 			}
-			tagID = int(id)
-		} else if err != nil {
-			return fmt.Errorf("failed to get tag %s: %w", tagName, err)
 		}
 
-		// Link article to tag
-		_, err = tx.Exec("INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?)", articleID, tagID)
-		if err != nil {
-			return fmt.Errorf("failed to link article to tag %s: %w", tagName, err)
-		}
-	}
-
-	return tx.Commit()
+		return nil
+	})
 }
 
 // CheckArticleExists checks if an article exists by slug
-func (r *ArticleRepository) CheckArticleExists(slug string) (bool, error) {
+func (r *ArticleRepository) CheckArticleExists(ctx context.Context, slug string) (bool, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM articles WHERE slug = ?`
-	err := r.db.QueryRow(query, slug).Scan(&count)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), slug).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check article existence: %w", err)
 	}
@@ -220,180 +237,188 @@ func (r *ArticleRepository) CheckArticleExists(slug string) (bool, error) {
 }
 
 // GetArticles retrieves articles with filtering and pagination
-func (r *ArticleRepository) GetArticles(limit, offset int, tag, author, favorited string) ([]model.Article, int, error) {
-	// Build the base query
-	baseQuery := `
-		FROM articles a
-		LEFT JOIN article_tags at ON a.id = at.article_id
-		LEFT JOIN tags t ON at.tag_id = t.id
-		LEFT JOIN users u ON a.author_id = u.id
-		LEFT JOIN favorites f ON a.id = f.article_id
-	`
-
-	// Build WHERE conditions
-	conditions := []string{}
-	args := []interface{}{}
-
-	if tag != "" {
-		conditions = append(conditions, "t.name = ?")
-		args = append(args, tag)
-	}
-
-	if author != "" {
-		conditions = append(conditions, "u.username = ?")
-		args = append(args, author)
-	}
-
-	if favorited != "" {
-		conditions = append(conditions, "f.user_id = (SELECT id FROM users WHERE username = ?)")
-		args = append(args, favorited)
-	}
-
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+func (r *ArticleRepository) GetArticles(ctx context.Context, page Page, filter ArticleFilter) ([]model.Article, int, error) {
+	baseQuery := "FROM articles a"
+	conditions, args := filter.conditions()
 
 	// Get total count
-	countQuery := "SELECT COUNT(DISTINCT a.id) " + baseQuery + " " + whereClause
+	countQuery := "SELECT COUNT(*) " + baseQuery + " WHERE " + strings.Join(conditions, " AND ")
 	var totalCount int
-	err := r.db.QueryRow(countQuery, args...).Scan(&totalCount)
+	err := r.reader.QueryRowContext(ctx, r.dialect.Rebind(countQuery), args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get articles count: %w", err)
 	}
 
 	// Get articles
-	articlesQuery := `
-		SELECT DISTINCT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at, 
-		       COALESCE((SELECT COUNT(*) FROM favorites f WHERE f.article_id = a.id), 0) as favorites_count
-	` + baseQuery + " " + whereClause + `
-		ORDER BY a.created_at DESC
-		LIMIT ? OFFSET ?
-	`
-
-	args = append(args, limit, offset)
-	rows, err := r.db.Query(articlesQuery, args...)
+	articles, err := r.queryPage(ctx, baseQuery, conditions, args, page, "articles")
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get articles: %w", err)
-	}
-	defer rows.Close()
-
-	var articles []model.Article
-	for rows.Next() {
-		var article model.Article
-		err := rows.Scan(
-			&article.ID, &article.Slug, &article.Title, &article.Description,
-			&article.Body, &article.AuthorID, &article.CreatedAt, &article.UpdatedAt,
-			&article.FavoritesCount,
-		)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan article: %w", err)
-		}
-		articles = append(articles, article)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("failed to iterate articles: %w", err)
+		return nil, 0, err
 	}
 
 	return articles, totalCount, nil
 }
 
 // GetFeedArticles retrieves articles from followed users for personalized feed
-func (r *ArticleRepository) GetFeedArticles(limit, offset, userID int) ([]model.Article, int, error) {
+func (r *ArticleRepository) GetFeedArticles(ctx context.Context, page Page, userID int) ([]model.Article, int, error) {
 	// Build the base query for feed (articles from followed users)
 	baseQuery := `
 		FROM articles a
 		INNER JOIN follows f ON a.author_id = f.followed_id
-		WHERE f.follower_id = ?
 	`
-
+	conditions := []string{"f.follower_id = ?", publishedCondition}
 	args := []interface{}{userID}
 
 	// Get total count
-	countQuery := "SELECT COUNT(a.id) " + baseQuery
+	countQuery := "SELECT COUNT(a.id) " + baseQuery + " WHERE " + strings.Join(conditions, " AND ")
 	var totalCount int
-	err := r.db.QueryRow(countQuery, args...).Scan(&totalCount)
+	err := r.reader.QueryRowContext(ctx, r.dialect.Rebind(countQuery), args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get feed count: %w", err)
 	}
 
 	// Get articles
-	articlesQuery := `
-		SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at, 
-		       COALESCE((SELECT COUNT(*) FROM favorites f WHERE f.article_id = a.id), 0) as favorites_count
-	` + baseQuery + `
-		ORDER BY a.created_at DESC
-		LIMIT ? OFFSET ?
-	`
+	articles, err := r.queryPage(ctx, baseQuery, conditions, args, page, "feed articles")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return articles, totalCount, nil
+}
+
+// queryPage reads one page of the articles a selected by from (a FROM
+// clause with its joins) and conditions. The cursor narrows the page but
+// not the count, which callers read with the same from, conditions and
+// arguments.
+func (r *ArticleRepository) queryPage(ctx context.Context, from string, conditions []string, args []interface{}, page Page, what string) ([]model.Article, error) {
+	pageCondition, orderBy, pageArgs, limitArgs := pageClauses(page)
+	if pageCondition != "" {
+		conditions = append(conditions, pageCondition)
+		args = append(args, pageArgs...)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
 
-	args = append(args, limit, offset)
-	rows, err := r.db.Query(articlesQuery, args...)
+	columns := articleColumns
+	if page.selectsCommentsCount() {
+		columns += ", " + commentsCountExpr
+	}
+
+	query := "SELECT " + columns + " " + from + where + " " + orderBy + " LIMIT ? OFFSET ?"
+	args = append(args, limitArgs...)
+	rows, err := r.reader.QueryContext(ctx, r.dialect.Rebind(query), args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get feed articles: %w", err)
+		return nil, fmt.Errorf("failed to get %s: %w", what, err)
 	}
 	defer rows.Close()
 
 	var articles []model.Article
 	for rows.Next() {
 		var article model.Article
-		err := rows.Scan(
-			&article.ID, &article.Slug, &article.Title, &article.Description,
-			&article.Body, &article.AuthorID, &article.CreatedAt, &article.UpdatedAt,
-			&article.FavoritesCount,
-		)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan feed article: %w", err)
+		dest := articleDest(&article)
+		if page.selectsCommentsCount() {
+			dest = append(dest, &article.CommentsCount)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", what, err)
 		}
 		articles = append(articles, article)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("failed to iterate feed articles: %w", err)
+		return nil, fmt.Errorf("failed to iterate %s: %w", what, err)
 	}
 
-	return articles, totalCount, nil
+	reverseArticles(page, articles)
+	return articles, nil
 }
 
-// FavoriteArticle adds an article to user's favorites
-func (r *ArticleRepository) FavoriteArticle(userID, articleID int) error {
-	query := `INSERT INTO favorites (user_id, article_id) VALUES (?, ?)`
+// PublishScheduled publishes the scheduled articles due at or before now
+// and returns how many it published
+func (r *ArticleRepository) PublishScheduled(ctx context.Context, now time.Time) (int, error) {
+	query := `UPDATE articles SET status = ? WHERE status = ? AND publish_at <= ?`
 
-	_, err := r.db.Exec(query, userID, articleID)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), model.ArticlePublished, model.ArticleScheduled, now.Local())
 	if err != nil {
-		return fmt.Errorf("failed to favorite article: %w", err)
+		return 0, fmt.Errorf("failed to publish scheduled articles: %w", err)
 	}
 
-	return nil
+	published, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(published), nil
 }
 
-// UnfavoriteArticle removes an article from user's favorites
-func (r *ArticleRepository) UnfavoriteArticle(userID, articleID int) error {
-	query := `DELETE FROM favorites WHERE user_id = ? AND article_id = ?`
+// NextScheduledAt returns when the next scheduled article is due, or the
+// zero time when none is scheduled
+func (r *ArticleRepository) NextScheduledAt(ctx context.Context) (time.Time, error) {
+	query := `SELECT publish_at FROM articles WHERE status = ? ORDER BY publish_at LIMIT 1`
 
-	result, err := r.db.Exec(query, userID, articleID)
-	if err != nil {
-		return fmt.Errorf("failed to unfavorite article: %w", err)
+	var next time.Time
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), model.ArticleScheduled).Scan(&next)
+	if err != nil && err != sql.ErrNoRows {
+		return time.Time{}, fmt.Errorf("failed to get next scheduled article: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+	return next, nil
+}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("favorite not found")
+// FavoriteArticle adds an article to user's favorites and increments its
+// favorites count in the same transaction
+func (r *ArticleRepository) FavoriteArticle(ctx context.Context, userID, articleID int) error {
+	return withinTx(ctx, r.db, func(tx db.Executor) error {
+		query := `INSERT INTO favorites (user_id, article_id) VALUES (?, ?)`
+		if _, err := tx.ExecContext(ctx, r.dialect.Rebind(query), userID, articleID); err != nil {
+			return fmt.Errorf("failed to favorite article: %w", err)
+		}
+
+		return r.adjustFavoritesCount(ctx, tx, articleID, 1)
+	})
+}
+
+// UnfavoriteArticle removes an article from user's favorites and decrements
+// its favorites count in the same transaction
+func (r *ArticleRepository) UnfavoriteArticle(ctx context.Context, userID, articleID int) error {
+	return withinTx(ctx, r.db, func(tx db.Executor) error {
+		query := `DELETE FROM favorites WHERE user_id = ? AND article_id = ?`
+		result, err := tx.ExecContext(ctx, r.dialect.Rebind(query), userID, articleID)
+		if err != nil {
+			return fmt.Errorf("failed to unfavorite article: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("favorite not found")
+		}
+
+		return r.adjustFavoritesCount(ctx, tx, articleID, -1)
+	})
+}
+
+// adjustFavoritesCount adds delta to an article's favorites count
+func (r *ArticleRepository) adjustFavoritesCount(ctx context.Context, tx db.Executor, articleID, delta int) error {
+	query := `UPDATE articles SET favorites_count = favorites_count + ? WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, r.dialect.Rebind(query), delta, articleID); err != nil {
+		return fmt.Errorf("failed to update favorites count: %w", err)
 	}
 
 	return nil
 }
 
 // IsFavorited checks if an article is favorited by a user
-func (r *ArticleRepository) IsFavorited(userID, articleID int) (bool, error) {
+func (r *ArticleRepository) IsFavorited(ctx context.Context, userID, articleID int) (bool, error) {
 	query := `SELECT COUNT(*) FROM favorites WHERE user_id = ? AND article_id = ?`
 
 	var count int
-	err := r.db.QueryRow(query, userID, articleID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), userID, articleID).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check favorite status: %w", err)
 	}
@@ -401,15 +426,57 @@ func (r *ArticleRepository) IsFavorited(userID, articleID int) (bool, error) {
 	return count > 0, nil
 }
 
+// GetFavoritedIDs reports which of the given articles a user has favorited
+func (r *ArticleRepository) GetFavoritedIDs(ctx context.Context, userID int, articleIDs []int) (map[int]bool, error) {
+	if len(articleIDs) == 0 {
+		return map[int]bool{}, nil
+	}
+
+	query := `SELECT article_id FROM favorites WHERE user_id = ? AND article_id IN (` + placeholders(len(articleIDs)) + `)`
+
+	args := append([]interface{}{userID}, intArgs(articleIDs)...)
+	favorited, err := queryIDSet(ctx, r.db, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check favorite statuses: %w", err)
+	}
+
+	return favorited, nil
+}
+
 // GetFavoritesCount returns the number of favorites for an article
-func (r *ArticleRepository) GetFavoritesCount(articleID int) (int, error) {
-	query := `SELECT COUNT(*) FROM favorites WHERE article_id = ?`
+func (r *ArticleRepository) GetFavoritesCount(ctx context.Context, articleID int) (int, error) {
+	query := `SELECT favorites_count FROM articles WHERE id = ?`
 
 	var count int
-	err := r.db.QueryRow(query, articleID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), articleID).Scan(&count)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("article not found")
+		}
 		return 0, fmt.Errorf("failed to get favorites count: %w", err)
 	}
 
 	return count, nil
 }
+
+// ReconcileFavoritesCounts recomputes every article's favorites count from
+// the favorites table and returns the number of articles that had drifted
+func (r *ArticleRepository) ReconcileFavoritesCounts(ctx context.Context) (int, error) {
+	query := `
+		UPDATE articles
+		SET favorites_count = (SELECT COUNT(*) FROM favorites f WHERE f.article_id = articles.id)
+		WHERE favorites_count <> (SELECT COUNT(*) FROM favorites f WHERE f.article_id = articles.id)
+	`
+
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query))
+	if err != nil {
+		return 0, fmt.Errorf("failed to reconcile favorites counts: %w", err)
+	}
+
+	repaired, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(repaired), nil
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestArticleRepositoryCRUD(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		author := createTestUser(t, userRepo, "author")
+		article := createTestArticle(t, articleRepo, tagRepo, "how-to-train-your-dragon", author.ID, "dragons", "training")
+		if article.ID == 0 {
+			t.Fatal("Expected article ID to be set after create")
+		}
+
+		fetched, err := articleRepo.GetBySlug(ctx, "how-to-train-your-dragon")
+		if err != nil {
+			t.Fatalf("GetBySlug() error = %v", err)
+		}
+		if fetched.ID != article.ID || fetched.AuthorID != author.ID {
+			t.Errorf("GetBySlug() = %+v, want article %d by %d", fetched, article.ID, author.ID)
+		}
+
+		exists, err := articleRepo.CheckArticleExists(ctx, "how-to-train-your-dragon")
+		if err != nil || !exists {
+			t.Errorf("CheckArticleExists() = %v, %v; want true", exists, err)
+		}
+
+		updated, err := articleRepo.Update(ctx, "how-to-train-your-dragon", map[string]interface{}{
+			"description": "Ever wonder how?",
+		})
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if updated.Description != "Ever wonder how?" || updated.Title != article.Title {
+			t.Errorf("Update() = %+v, want only description changed", updated)
+		}
+
+		renamed, err := articleRepo.Update(ctx, "how-to-train-your-dragon", map[string]interface{}{
+			"title": "How to tame your dragon",
+			"slug":  "how-to-tame-your-dragon",
+		})
+		if err != nil {
+			t.Fatalf("Update() renaming slug error = %v", err)
+		}
+		if renamed.Slug != "how-to-tame-your-dragon" || renamed.ID != article.ID {
+			t.Errorf("Update() = %+v, want article %d under its new slug", renamed, article.ID)
+		}
+		if _, err := articleRepo.Update(ctx, "how-to-train-your-dragon", map[string]interface{}{"body": "x"}); err == nil || err.Error() != "article not found" {
+			t.Errorf("Update() of old slug error = %v, want article not found", err)
+		}
+		if _, err := articleRepo.Update(ctx, "how-to-tame-your-dragon", map[string]interface{}{
+			"title": "How to train your dragon",
+			"slug":  "how-to-train-your-dragon",
+		}); err != nil {
+			t.Fatalf("Update() restoring slug error = %v", err)
+		}
+
+		if err := articleRepo.SetArticleTags(ctx, article.ID, []string{"dragons", "fantasy"}); err != nil {
+			t.Fatalf("SetArticleTags() error = %v", err)
+		}
+		tags, err := articleRepo.GetArticleTags(ctx, article.ID)
+		if err != nil {
+			t.Fatalf("GetArticleTags() error = %v", err)
+		}
+		if !reflect.DeepEqual(tags, []string{"dragons", "fantasy"}) {
+			t.Errorf("GetArticleTags() = %v, want [dragons fantasy]", tags)
+		}
+
+		if err := articleRepo.Delete(ctx, "how-to-train-your-dragon"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := articleRepo.GetBySlug(ctx, "how-to-train-your-dragon"); err == nil || err.Error() != "article not found" {
+			t.Errorf("GetBySlug() after delete error = %v, want article not found", err)
+		}
+		if err := articleRepo.Delete(ctx, "how-to-train-your-dragon"); err == nil {
+			t.Error("Expected deleting a missing article to fail")
+		}
+	})
+}
+
+func TestArticleRepositoryListing(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		jake := createTestUser(t, userRepo, "jake")
+		celeb := createTestUser(t, userRepo, "celeb")
+
+		first := createTestArticle(t, articleRepo, tagRepo, "first", jake.ID, "go", "sql")
+		createTestArticle(t, articleRepo, tagRepo, "second", celeb.ID, "go")
+		createTestArticle(t, articleRepo, tagRepo, "third", celeb.ID)
+
+		articles, count, err := articleRepo.GetArticles(ctx, Page{Limit: 10}, ArticleFilter{})
+		if err != nil {
+			t.Fatalf("GetArticles() error = %v", err)
+		}
+		if count != 3 || len(articles) != 3 {
+			t.Errorf("GetArticles() = %d articles, count %d; want 3", len(articles), count)
+		}
+
+		_, count, err = articleRepo.GetArticles(ctx, Page{Limit: 10}, ArticleFilter{Tags: []string{"go"}})
+		if err != nil || count != 2 {
+			t.Errorf("GetArticles(tag=go) count = %d, %v; want 2", count, err)
+		}
+
+		articles, count, err = articleRepo.GetArticles(ctx, Page{Limit: 10}, ArticleFilter{Authors: []string{"celeb"}})
+		if err != nil || count != 2 || len(articles) != 2 {
+			t.Errorf("GetArticles(author=celeb) = %d, %d, %v; want 2", len(articles), count, err)
+		}
+
+		filter := ArticleFilter{Tags: []string{"go", "sql"}, MatchAllTags: true, ExcludeTags: []string{"rust", "c"}, Authors: []string{"jake", "celeb"}}
+		articles, count, err = articleRepo.GetArticles(ctx, Page{Limit: 10}, filter)
+		if err != nil || count != 1 || len(articles) != 1 || articles[0].Slug != "first" {
+			t.Errorf("GetArticles(%+v) = %v, %d, %v; want [first]", filter, articles, count, err)
+		}
+
+		articles, count, err = articleRepo.GetArticles(ctx, Page{Limit: 1, Offset: 1}, ArticleFilter{})
+		if err != nil || count != 3 || len(articles) != 1 {
+			t.Errorf("GetArticles(limit=1, offset=1) = %d, %d, %v; want 1 of 3", len(articles), count, err)
+		}
+
+		if err := articleRepo.FavoriteArticle(ctx, celeb.ID, first.ID); err != nil {
+			t.Fatalf("FavoriteArticle() error = %v", err)
+		}
+		favorited, err := articleRepo.IsFavorited(ctx, celeb.ID, first.ID)
+		if err != nil || !favorited {
+			t.Errorf("IsFavorited() = %v, %v; want true", favorited, err)
+		}
+		favoritesCount, err := articleRepo.GetFavoritesCount(ctx, first.ID)
+		if err != nil || favoritesCount != 1 {
+			t.Errorf("GetFavoritesCount() = %d, %v; want 1", favoritesCount, err)
+		}
+
+		articles, count, err = articleRepo.GetArticles(ctx, Page{Limit: 10}, ArticleFilter{Favorited: "celeb"})
+		if err != nil || count != 1 || len(articles) != 1 || articles[0].Slug != "first" {
+			t.Fatalf("GetArticles(favorited=celeb) = %v, %d, %v; want [first]", articles, count, err)
+		}
+		if articles[0].FavoritesCount != 1 {
+			t.Errorf("GetArticles() favorites count = %d, want 1", articles[0].FavoritesCount)
+		}
+
+		if err := articleRepo.UnfavoriteArticle(ctx, celeb.ID, first.ID); err != nil {
+			t.Fatalf("UnfavoriteArticle() error = %v", err)
+		}
+		if err := articleRepo.UnfavoriteArticle(ctx, celeb.ID, first.ID); err == nil || err.Error() != "favorite not found" {
+			t.Errorf("UnfavoriteArticle() twice error = %v, want favorite not found", err)
+		}
+
+		if err := userRepo.FollowUser(ctx, jake.ID, celeb.ID); err != nil {
+			t.Fatalf("FollowUser() error = %v", err)
+		}
+		feed, count, err := articleRepo.GetFeedArticles(ctx, Page{Limit: 10}, jake.ID)
+		if err != nil {
+			t.Fatalf("GetFeedArticles() error = %v", err)
+		}
+		if count != 2 || len(feed) != 2 {
+			t.Errorf("GetFeedArticles() = %d articles, count %d; want 2", len(feed), count)
+		}
+		for _, article := range feed {
+			if article.AuthorID != celeb.ID {
+				t.Errorf("GetFeedArticles() returned article by %d, want %d", article.AuthorID, celeb.ID)
+			}
+		}
+	})
+}
+
+func TestFavoritesCountMaintained(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		jake := createTestUser(t, userRepo, "jake")
+		celeb := createTestUser(t, userRepo, "celeb")
+		article := createTestArticle(t, articleRepo, tagRepo, "dragons", jake.ID)
+
+		assertCount := func(want int) {
+			t.Helper()
+			fetched, err := articleRepo.GetBySlug(ctx, "dragons")
+			if err != nil || fetched.FavoritesCount != want {
+				t.Errorf("GetBySlug() favorites count = %v, %v; want %d", fetched, err, want)
+			}
+			if count, err := articleRepo.GetFavoritesCount(ctx, article.ID); err != nil || count != want {
+				t.Errorf("GetFavoritesCount() = %d, %v; want %d", count, err, want)
+			}
+		}
+
+		for _, user := range []int{jake.ID, celeb.ID} {
+			if err := articleRepo.FavoriteArticle(ctx, user, article.ID); err != nil {
+				t.Fatalf("FavoriteArticle() error = %v", err)
+			}
+		}
+		assertCount(2)
+
+		// A rejected favorite leaves the counter alone
+		if err := articleRepo.FavoriteArticle(ctx, celeb.ID, article.ID); err == nil {
+			t.Fatal("Expected favoriting twice to fail")
+		}
+		assertCount(2)
+
+		if err := articleRepo.UnfavoriteArticle(ctx, celeb.ID, article.ID); err != nil {
+			t.Fatalf("UnfavoriteArticle() error = %v", err)
+		}
+		if err := articleRepo.UnfavoriteArticle(ctx, celeb.ID, article.ID); err == nil {
+			t.Fatal("Expected unfavoriting twice to fail")
+		}
+		assertCount(1)
+
+		if _, err := database.Exec("UPDATE articles SET favorites_count = 7"); err != nil {
+			t.Fatalf("Failed to corrupt counter: %v", err)
+		}
+		repaired, err := articleRepo.ReconcileFavoritesCounts(ctx)
+		if err != nil || repaired != 1 {
+			t.Errorf("ReconcileFavoritesCounts() = %d, %v; want 1 repaired", repaired, err)
+		}
+		assertCount(1)
+
+		repaired, err = articleRepo.ReconcileFavoritesCounts(ctx)
+		if err != nil || repaired != 0 {
+			t.Errorf("ReconcileFavoritesCounts() again = %d, %v; want 0 repaired", repaired, err)
+		}
+	})
+}
+
+func TestFavoritingKeepsUpdatedAt(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		author := createTestUser(t, userRepo, "author")
+		article := createTestArticle(t, articleRepo, tagRepo, "article", author.ID)
+		before, err := articleRepo.GetBySlug(ctx, "article")
+		if err != nil {
+			t.Fatalf("GetBySlug() error = %v", err)
+		}
+
+		// The recently updated sort must not move articles that were only
+		// favorited
+		if err := articleRepo.FavoriteArticle(ctx, author.ID, article.ID); err != nil {
+			t.Fatalf("FavoriteArticle() error = %v", err)
+		}
+		after, err := articleRepo.GetBySlug(ctx, "article")
+		if err != nil || !after.UpdatedAt.Equal(before.UpdatedAt) {
+			t.Errorf("updated_at after favoriting = %v, %v; want unchanged %v", after.UpdatedAt, err, before.UpdatedAt)
+		}
+
+		edited, err := articleRepo.Update(ctx, "article", map[string]interface{}{"body": "Edited"})
+		if err != nil || !edited.UpdatedAt.After(before.UpdatedAt) {
+			t.Errorf("updated_at after editing = %v, %v; want later than %v", edited.UpdatedAt, err, before.UpdatedAt)
+		}
+	})
+}
+
+func TestScheduledPublishing(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+
+		jake := createTestUser(t, userRepo, "jake")
+		publishAt := time.Now().Add(time.Hour)
+		article := &model.Article{Slug: "soon", Title: "Soon", Description: "d", Body: "b", AuthorID: jake.ID, Status: model.ArticleScheduled, PublishAt: &publishAt}
+		if err := articleRepo.Create(ctx, article); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		if _, count, err := articleRepo.GetArticles(ctx, Page{Limit: 10}, ArticleFilter{}); err != nil || count != 0 {
+			t.Errorf("GetArticles() count = %d, %v; want the scheduled article hidden", count, err)
+		}
+		if _, count, err := articleRepo.GetArticles(ctx, Page{Limit: 10}, ArticleFilter{AuthorID: jake.ID, Unpublished: true}); err != nil || count != 1 {
+			t.Errorf("GetArticles(unpublished) count = %d, %v; want 1", count, err)
+		}
+
+		next, err := articleRepo.NextScheduledAt(ctx)
+		if err != nil || !next.Equal(publishAt) {
+			t.Errorf("NextScheduledAt() = %v, %v; want %v", next, err, publishAt)
+		}
+
+		// Due times are compared in UTC as well as local time
+		if published, err := articleRepo.PublishScheduled(ctx, publishAt.Add(-time.Second).UTC()); err != nil || published != 0 {
+			t.Errorf("PublishScheduled(before) = %d, %v; want 0", published, err)
+		}
+		if published, err := articleRepo.PublishScheduled(ctx, publishAt.UTC()); err != nil || published != 1 {
+			t.Errorf("PublishScheduled(at) = %d, %v; want 1", published, err)
+		}
+
+		fetched, err := articleRepo.GetBySlug(ctx, "soon")
+		if err != nil || fetched.Status != model.ArticlePublished || !fetched.PublishAt.Equal(publishAt) {
+			t.Errorf("GetBySlug() = %+v, %v; want published at %v", fetched, err, publishAt)
+		}
+	})
+}
@@ -1,22 +1,25 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
 
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
 )
 
 type CommentRepository struct {
-	db *sql.DB
+	db      db.Executor
+	dialect db.Dialect
 }
 
-func NewCommentRepository(db *sql.DB) *CommentRepository {
-	return &CommentRepository{db: db}
+func NewCommentRepository(database *db.Database) *CommentRepository {
+	return &CommentRepository{db: database.Primary(), dialect: database.Dialect()}
 }
 
-func (r *CommentRepository) Create(comment *model.Comment) error {
+func (r *CommentRepository) Create(ctx context.Context, comment *model.Comment) error {
 	query := `
 		INSERT INTO comments (body, author_id, article_id, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?)
@@ -26,23 +29,18 @@ func (r *CommentRepository) Create(comment *model.Comment) error {
 	comment.CreatedAt = now
 	comment.UpdatedAt = now
 
-	result, err := r.db.Exec(query,
+	id, err := r.dialect.InsertReturningID(ctx, r.db, query,
 		comment.Body, comment.AuthorID, comment.ArticleID,
 		comment.CreatedAt, comment.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create comment: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get comment ID: %w", err)
-	}
-
 	comment.ID = int(id)
 	return nil
 }
 
-func (r *CommentRepository) GetByArticleSlug(slug string) ([]*model.Comment, error) {
+func (r *CommentRepository) GetByArticleSlug(ctx context.Context, slug string) ([]*model.Comment, error) {
 	query := `
 		SELECT c.id, c.body, c.author_id, c.article_id, c.created_at, c.updated_at,
 			   u.username, u.email, u.bio, u.image
@@ -53,7 +51,7 @@ func (r *CommentRepository) GetByArticleSlug(slug string) ([]*model.Comment, err
 		ORDER BY c.created_at DESC
 	`
 
-	rows, err := r.db.Query(query, slug)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), slug)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query comments: %w", err)
 	}
@@ -85,7 +83,7 @@ func (r *CommentRepository) GetByArticleSlug(slug string) ([]*model.Comment, err
 	return comments, nil
 }
 
-func (r *CommentRepository) GetByID(id int) (*model.Comment, error) {
+func (r *CommentRepository) GetByID(ctx context.Context, id int) (*model.Comment, error) {
 	query := `
 		SELECT c.id, c.body, c.author_id, c.article_id, c.created_at, c.updated_at,
 			   u.username, u.email, u.bio, u.image
@@ -99,7 +97,7 @@ func (r *CommentRepository) GetByID(id int) (*model.Comment, error) {
 	}
 
 	var email string // Temporary variable for email
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), id).Scan(
 		&comment.ID, &comment.Body, &comment.AuthorID, &comment.ArticleID,
 		&comment.CreatedAt, &comment.UpdatedAt,
 		&comment.Author.Username, &email, &comment.Author.Bio, &comment.Author.Image,
@@ -114,10 +112,10 @@ func (r *CommentRepository) GetByID(id int) (*model.Comment, error) {
 	return comment, nil
 }
 
-func (r *CommentRepository) Delete(id int) error {
+func (r *CommentRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM comments WHERE id = ?`
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), id)
 	if err != nil {
 		return fmt.Errorf("failed to delete comment: %w", err)
 	}
@@ -134,11 +132,11 @@ func (r *CommentRepository) Delete(id int) error {
 	return nil
 }
 
-func (r *CommentRepository) GetArticleIDBySlug(slug string) (int, error) {
+func (r *CommentRepository) GetArticleIDBySlug(ctx context.Context, slug string) (int, error) {
 	query := `SELECT id FROM articles WHERE slug = ?`
 
 	var articleID int
-	err := r.db.QueryRow(query, slug).Scan(&articleID)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), slug).Scan(&articleID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return 0, fmt.Errorf("article not found")
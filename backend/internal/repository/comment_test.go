@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestCommentRepository(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+		commentRepo := NewCommentRepository(database)
+
+		author := createTestUser(t, userRepo, "author")
+		article := createTestArticle(t, articleRepo, tagRepo, "commented", author.ID)
+
+		articleID, err := commentRepo.GetArticleIDBySlug(ctx, "commented")
+		if err != nil || articleID != article.ID {
+			t.Fatalf("GetArticleIDBySlug() = %d, %v; want %d", articleID, err, article.ID)
+		}
+		if _, err := commentRepo.GetArticleIDBySlug(ctx, "missing"); err == nil || err.Error() != "article not found" {
+			t.Errorf("GetArticleIDBySlug(missing) error = %v, want article not found", err)
+		}
+
+		comment := &model.Comment{Body: "Thank you so much!", AuthorID: author.ID, ArticleID: article.ID}
+		if err := commentRepo.Create(ctx, comment); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if comment.ID == 0 {
+			t.Fatal("Expected comment ID to be set after create")
+		}
+
+		fetched, err := commentRepo.GetByID(ctx, comment.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if fetched.Body != comment.Body || fetched.Author.Username != "author" {
+			t.Errorf("GetByID() = %+v, want body and author populated", fetched)
+		}
+
+		comments, err := commentRepo.GetByArticleSlug(ctx, "commented")
+		if err != nil || len(comments) != 1 {
+			t.Fatalf("GetByArticleSlug() = %d comments, %v; want 1", len(comments), err)
+		}
+
+		if err := commentRepo.Delete(ctx, comment.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := commentRepo.Delete(ctx, comment.ID); err == nil || err.Error() != "comment not found" {
+			t.Errorf("Delete() twice error = %v, want comment not found", err)
+		}
+	})
+}
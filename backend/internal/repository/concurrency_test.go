@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// TestConcurrentWrites hammers the database from many goroutines at once,
+// which fails with "database is locked" unless every pooled SQLite
+// connection waits on busy_timeout
+func TestConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+		commentRepo := NewCommentRepository(database)
+
+		const writers = 20
+		const commentsPerWriter = 10
+
+		author := createTestUser(t, userRepo, "author")
+		article := createTestArticle(t, articleRepo, tagRepo, "busy-article", author.ID)
+
+		users := make([]*model.User, writers)
+		for i := range users {
+			users[i] = createTestUser(t, userRepo, fmt.Sprintf("reader%d", i))
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, writers*(commentsPerWriter+1))
+		for _, user := range users {
+			wg.Add(1)
+			go func(user *model.User) {
+				defer wg.Done()
+
+				if err := articleRepo.FavoriteArticle(ctx, user.ID, article.ID); err != nil {
+					errs <- err
+				}
+				for i := 0; i < commentsPerWriter; i++ {
+					comment := &model.Comment{Body: fmt.Sprintf("comment %d", i), AuthorID: user.ID, ArticleID: article.ID}
+					if err := commentRepo.Create(ctx, comment); err != nil {
+						errs <- err
+					}
+				}
+			}(user)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Errorf("concurrent write error = %v", err)
+		}
+
+		count, err := articleRepo.GetFavoritesCount(ctx, article.ID)
+		if err != nil || count != writers {
+			t.Errorf("GetFavoritesCount() = %d, %v; want %d", count, err, writers)
+		}
+		comments, err := commentRepo.GetByArticleSlug(ctx, "busy-article")
+		if err != nil || len(comments) != writers*commentsPerWriter {
+			t.Errorf("GetByArticleSlug() = %d comments, %v; want %d", len(comments), err, writers*commentsPerWriter)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+func TestQueriesHonorContext(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		author := createTestUser(t, userRepo, "author")
+		createTestArticle(t, articleRepo, tagRepo, "article", author.ID, "go")
+
+		canceled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancelExpired()
+
+		tests := []struct {
+			name string
+			ctx  context.Context
+			want error
+		}{
+			{"canceled", canceled, context.Canceled},
+			{"deadline exceeded", expired, context.DeadlineExceeded},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, _, err := articleRepo.GetArticles(tt.ctx, Page{Limit: 10}, ArticleFilter{}); !errors.Is(err, tt.want) {
+					t.Errorf("GetArticles() error = %v, want %v", err, tt.want)
+				}
+				if _, err := articleRepo.GetBySlug(tt.ctx, "article"); !errors.Is(err, tt.want) {
+					t.Errorf("GetBySlug() error = %v, want %v", err, tt.want)
+				}
+				if err := articleRepo.Delete(tt.ctx, "article"); !errors.Is(err, tt.want) {
+					t.Errorf("Delete() error = %v, want %v", err, tt.want)
+				}
+				if err := tagRepo.UpdateTagsForArticle(tt.ctx, 1, []string{"rust"}); !errors.Is(err, tt.want) {
+					t.Errorf("UpdateTagsForArticle() error = %v, want %v", err, tt.want)
+				}
+			})
+		}
+
+		// Nothing was changed by the aborted calls
+		if exists, err := articleRepo.CheckArticleExists(context.Background(), "article"); err != nil || !exists {
+			t.Errorf("CheckArticleExists() = %v, %v; want true", exists, err)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// publishedCondition keeps drafts and scheduled articles out of the lists
+// of articles a
+const publishedCondition = "a.status = '" + model.ArticlePublished + "'"
+
+// ArticleFilter narrows an article list. Every set field must match;
+// empty fields match every article. Lists hold published articles only,
+// unless Unpublished asks for the others.
+type ArticleFilter struct {
+	Tags         []string  // articles with any of these tags
+	MatchAllTags bool      // articles with every one of Tags instead
+	ExcludeTags  []string  // articles with none of these tags
+	Authors      []string  // articles by any of these usernames
+	Favorited    string    // articles favorited by this username
+	Since        time.Time // articles created at or after
+	Until        time.Time // articles created before
+	AuthorID     int       // articles by this user
+	Unpublished  bool      // drafts and scheduled articles instead
+}
+
+// conditions returns the WHERE conditions of the filter on articles a and
+// their arguments. Each is an EXISTS subquery or a column comparison, so
+// the list needs no joins and one article is never counted twice.
+func (f ArticleFilter) conditions() ([]string, []interface{}) {
+	conditions := []string{publishedCondition}
+	var args []interface{}
+	if f.Unpublished {
+		conditions[0] = "a.status <> '" + model.ArticlePublished + "'"
+	}
+
+	hasTag := func(names []string) string {
+		for _, name := range names {
+			args = append(args, name)
+		}
+		return `EXISTS (SELECT 1 FROM article_tags at JOIN tags t ON t.id = at.tag_id
+			WHERE at.article_id = a.id AND t.name IN (` + placeholders(len(names)) + `))`
+	}
+
+	if len(f.Tags) > 0 {
+		if f.MatchAllTags {
+			for _, tag := range f.Tags {
+				conditions = append(conditions, hasTag([]string{tag}))
+			}
+		} else {
+			conditions = append(conditions, hasTag(f.Tags))
+		}
+	}
+
+	if len(f.ExcludeTags) > 0 {
+		conditions = append(conditions, "NOT "+hasTag(f.ExcludeTags))
+	}
+
+	if len(f.Authors) > 0 {
+		for _, author := range f.Authors {
+			args = append(args, author)
+		}
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM users u
+			WHERE u.id = a.author_id AND u.username IN (`+placeholders(len(f.Authors))+`))`)
+	}
+
+	if f.Favorited != "" {
+		args = append(args, f.Favorited)
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM favorites f JOIN users fu ON fu.id = f.user_id
+			WHERE f.article_id = a.id AND fu.username = ?)`)
+	}
+
+	if f.AuthorID != 0 {
+		args = append(args, f.AuthorID)
+		conditions = append(conditions, "a.author_id = ?")
+	}
+
+	// Articles are stored with local times, which SQLite compares as text
+	if !f.Since.IsZero() {
+		args = append(args, f.Since.Local())
+		conditions = append(conditions, "a.created_at >= ?")
+	}
+
+	if !f.Until.IsZero() {
+		args = append(args, f.Until.Local())
+		conditions = append(conditions, "a.created_at < ?")
+	}
+
+	return conditions, args
+}
+
+// placeholders returns n comma-separated "?" placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
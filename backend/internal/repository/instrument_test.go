@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+func TestRepositoriesRecordQueryStats(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		stats := db.NewQueryStats(0)
+		database.SetQueryStats(stats)
+
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		jake := createTestUser(t, userRepo, "jake")
+		article := createTestArticle(t, articleRepo, tagRepo, "dragons", jake.ID, "fantasy")
+		if _, _, err := articleRepo.GetArticles(ctx, Page{Limit: 10}, ArticleFilter{}); err != nil {
+			t.Fatalf("GetArticles() error = %v", err)
+		}
+		err := NewUnitOfWork(database).Do(ctx, func(repos *Repositories) error {
+			return repos.Articles.FavoriteArticle(ctx, jake.ID, article.ID)
+		})
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+
+		calls := map[string]int64{}
+		for _, statement := range stats.Snapshot() {
+			for _, prefix := range []string{"INSERT INTO users", "INSERT INTO article_tags", "SELECT COUNT(*) FROM articles a", "INSERT INTO favorites", "UPDATE articles SET favorites_count"} {
+				if strings.HasPrefix(statement.Query, prefix) {
+					calls[prefix] += statement.Calls
+				}
+			}
+		}
+		for prefix, count := range calls {
+			if count != 1 {
+				t.Errorf("calls of %q = %d, want 1", prefix, count)
+			}
+		}
+		if len(calls) != 5 {
+			t.Errorf("recorded statements = %v, want users, tags, list, favorite and counter statements", calls)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// UserStore stores users and follow relationships
+type UserStore interface {
+	GetByID(ctx context.Context, id int) (*model.User, error)
+	GetByIDs(ctx context.Context, ids []int) (map[int]*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	Create(ctx context.Context, user *model.User) error
+	Update(ctx context.Context, user *model.User) error
+	EmailExists(ctx context.Context, email string) (bool, error)
+	UsernameExists(ctx context.Context, username string) (bool, error)
+	FollowUser(ctx context.Context, followerID, followedID int) error
+	UnfollowUser(ctx context.Context, followerID, followedID int) error
+	IsFollowing(ctx context.Context, followerID, followedID int) (bool, error)
+	GetFollowedIDs(ctx context.Context, followerID int, userIDs []int) (map[int]bool, error)
+	GetProfileByUsername(ctx context.Context, username string, currentUserID *int) (*model.ProfileResponse, error)
+}
+
+// ArticleStore stores articles and favorites
+type ArticleStore interface {
+	Create(ctx context.Context, article *model.Article) error
+	GetBySlug(ctx context.Context, slug string) (*model.Article, error)
+	Update(ctx context.Context, slug string, updates map[string]interface{}) (*model.Article, error)
+	Delete(ctx context.Context, slug string) error
+	GetArticleTags(ctx context.Context, articleID int) ([]string, error)
+	SetArticleTags(ctx context.Context, articleID int, tagNames []string) error
+	CheckArticleExists(ctx context.Context, slug string) (bool, error)
+	GetArticles(ctx context.Context, page Page, filter ArticleFilter) ([]model.Article, int, error)
+	GetFeedArticles(ctx context.Context, page Page, userID int) ([]model.Article, int, error)
+	SearchArticles(ctx context.Context, query string, limit, offset int) ([]model.SearchResult, int, error)
+	FavoriteArticle(ctx context.Context, userID, articleID int) error
+	UnfavoriteArticle(ctx context.Context, userID, articleID int) error
+	IsFavorited(ctx context.Context, userID, articleID int) (bool, error)
+	GetFavoritedIDs(ctx context.Context, userID int, articleIDs []int) (map[int]bool, error)
+	GetFavoritesCount(ctx context.Context, articleID int) (int, error)
+	PublishScheduled(ctx context.Context, now time.Time) (int, error)
+	NextScheduledAt(ctx context.Context) (time.Time, error)
+	CreateRevision(ctx context.Context, revision *model.ArticleRevision) error
+	GetRevisions(ctx context.Context, articleID int) ([]model.ArticleRevision, error)
+	GetRevision(ctx context.Context, articleID, number int) (*model.ArticleRevision, error)
+	GetRenders(ctx context.Context, renderer int, articleIDs []int) (map[int]model.ArticleRender, error)
+	SaveRender(ctx context.Context, render *model.ArticleRender) error
+	ReconcileFavoritesCounts(ctx context.Context) (int, error)
+}
+
+// TagStore stores tags and their links to articles
+type TagStore interface {
+	GetPopularTags(ctx context.Context, limit int) ([]string, error)
+	GetAllTags(ctx context.Context) ([]string, error)
+	CreateTagsForArticle(ctx context.Context, articleID int, tagNames []string) error
+	UpdateTagsForArticle(ctx context.Context, articleID int, tagNames []string) error
+	GetTagsForArticle(ctx context.Context, articleID int) ([]string, error)
+	GetTagsForArticles(ctx context.Context, articleIDs []int) (map[int][]string, error)
+	GetArticleCountByTag(ctx context.Context, tagName string) (int, error)
+	DeleteUnusedTags(ctx context.Context) error
+	TagExists(ctx context.Context, tagName string) (bool, error)
+}
+
+// CommentStore stores comments on articles
+type CommentStore interface {
+	Create(ctx context.Context, comment *model.Comment) error
+	GetByArticleSlug(ctx context.Context, slug string) ([]*model.Comment, error)
+	GetByID(ctx context.Context, id int) (*model.Comment, error)
+	Delete(ctx context.Context, id int) error
+	GetArticleIDBySlug(ctx context.Context, slug string) (int, error)
+}
+
+// Transactor runs several repository operations atomically
+type Transactor interface {
+	Do(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+// The SQL repositories implement the store interfaces
+var (
+	_ UserStore    = (*UserRepository)(nil)
+	_ ArticleStore = (*ArticleRepository)(nil)
+	_ TagStore     = (*TagRepository)(nil)
+	_ CommentStore = (*CommentRepository)(nil)
+	_ Transactor   = (*UnitOfWork)(nil)
+)
@@ -0,0 +1,506 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+)
+
+// ArticleRepository stores articles and favorites in memory
+type ArticleRepository struct {
+	conn
+}
+
+// Create creates a new article
+func (r *ArticleRepository) Create(ctx context.Context, article *model.Article) error {
+	return r.write(ctx, func(s *state) error {
+		if _, exists := s.articleBySlug(article.Slug); exists {
+			return fmt.Errorf("failed to create article: %w", uniqueViolation("articles.slug"))
+		}
+		if _, ok := s.users[article.AuthorID]; !ok {
+			return fmt.Errorf("failed to create article: %w", errForeignKey)
+		}
+
+		now := time.Now()
+		article.ID = s.nextID("articles")
+		article.CreatedAt = now
+		article.UpdatedAt = now
+		article.FavoritesCount = 0
+		article.Revision = 1
+		// Published unless the caller says otherwise, as of their creation
+		if article.Status == "" {
+			article.Status = model.ArticlePublished
+		}
+		if article.Status == model.ArticlePublished {
+			article.PublishAt = &now
+		}
+		s.articles[article.ID] = *article
+		return nil
+	})
+}
+
+// GetBySlug retrieves an article by slug
+func (r *ArticleRepository) GetBySlug(ctx context.Context, slug string) (*model.Article, error) {
+	var article *model.Article
+	err := r.read(ctx, func(s *state) error {
+		found, ok := s.articleBySlug(slug)
+		if !ok {
+			return fmt.Errorf("article not found")
+		}
+		article = &found
+		return nil
+	})
+	return article, err
+}
+
+// Update updates an existing article
+func (r *ArticleRepository) Update(ctx context.Context, slug string, updates map[string]interface{}) (*model.Article, error) {
+	err := r.write(ctx, func(s *state) error {
+		article, ok := s.articleBySlug(slug)
+		if !ok {
+			return fmt.Errorf("article not found")
+		}
+
+		for field, value := range updates {
+			text, _ := value.(string)
+			switch field {
+			case "slug":
+				if other, exists := s.articleBySlug(text); exists && other.ID != article.ID {
+					return fmt.Errorf("failed to update article: %w", uniqueViolation("articles.slug"))
+				}
+				article.Slug = text
+			case "title":
+				article.Title = text
+			case "description":
+				article.Description = text
+			case "body":
+				article.Body = text
+			case "status":
+				if text != model.ArticleDraft && text != model.ArticleScheduled && text != model.ArticlePublished {
+					return fmt.Errorf("failed to update article: CHECK constraint failed: status")
+				}
+				article.Status = text
+			case "publish_at":
+				article.PublishAt, _ = value.(*time.Time)
+			default:
+				return fmt.Errorf("failed to update article: no such column: %s", field)
+			}
+		}
+
+		// Every update makes a new version of the article
+		article.UpdatedAt = time.Now()
+		article.Revision++
+		s.articles[article.ID] = article
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The article is found under its new slug when the update renamed it
+	if newSlug, ok := updates["slug"].(string); ok {
+		slug = newSlug
+	}
+
+	return r.GetBySlug(ctx, slug)
+}
+
+// Delete deletes an article by slug, along with its tags links, favorites,
+// comments, revisions and renders
+func (r *ArticleRepository) Delete(ctx context.Context, slug string) error {
+	return r.write(ctx, func(s *state) error {
+		article, ok := s.articleBySlug(slug)
+		if !ok {
+			return fmt.Errorf("article not found")
+		}
+
+		delete(s.articles, article.ID)
+		for key := range s.tagLinks {
+			if key[0] == article.ID {
+				delete(s.tagLinks, key)
+			}
+		}
+		for key := range s.favorites {
+			if key[1] == article.ID {
+				delete(s.favorites, key)
+			}
+		}
+		for id, comment := range s.comments {
+			if comment.ArticleID == article.ID {
+				delete(s.comments, id)
+			}
+		}
+		for id, revision := range s.revisions {
+			if revision.ArticleID == article.ID {
+				delete(s.revisions, id)
+			}
+		}
+		for key := range s.renders {
+			if key[0] == article.ID {
+				delete(s.renders, key)
+			}
+		}
+		return nil
+	})
+}
+
+// GetArticleTags retrieves tags for an article
+func (r *ArticleRepository) GetArticleTags(ctx context.Context, articleID int) ([]string, error) {
+	var tags []string
+	err := r.read(ctx, func(s *state) error {
+		tags = s.articleTags(articleID)
+		return nil
+	})
+	return tags, err
+}
+
+// SetArticleTags sets tags for an article
+func (r *ArticleRepository) SetArticleTags(ctx context.Context, articleID int, tagNames []string) error {
+	return r.write(ctx, func(s *state) error {
+		return s.replaceArticleTags(articleID, tagNames)
+	})
+}
+
+// CheckArticleExists checks if an article exists by slug
+func (r *ArticleRepository) CheckArticleExists(ctx context.Context, slug string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func(s *state) error {
+		_, exists = s.articleBySlug(slug)
+		return nil
+	})
+	return exists, err
+}
+
+// GetArticles retrieves articles with filtering and pagination
+func (r *ArticleRepository) GetArticles(ctx context.Context, page repository.Page, filter repository.ArticleFilter) ([]model.Article, int, error) {
+	var articles []model.Article
+	var totalCount int
+	err := r.read(ctx, func(s *state) error {
+		var matches []model.Article
+		for _, article := range s.articles {
+			if s.matchesFilter(article, filter) {
+				matches = append(matches, article)
+			}
+		}
+
+		totalCount = len(matches)
+		articles = s.page(matches, page)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return articles, totalCount, nil
+}
+
+// matchesFilter reports whether article passes every condition of filter
+func (s *state) matchesFilter(article model.Article, filter repository.ArticleFilter) bool {
+	if (article.Status == model.ArticlePublished) == filter.Unpublished {
+		return false
+	}
+	if filter.AuthorID != 0 && article.AuthorID != filter.AuthorID {
+		return false
+	}
+
+	tags := map[string]bool{}
+	for _, tag := range s.articleTags(article.ID) {
+		tags[tag] = true
+	}
+
+	if len(filter.Tags) > 0 {
+		found := 0
+		for _, tag := range filter.Tags {
+			if tags[tag] {
+				found++
+			}
+		}
+		if found == 0 || (filter.MatchAllTags && found < len(filter.Tags)) {
+			return false
+		}
+	}
+
+	for _, tag := range filter.ExcludeTags {
+		if tags[tag] {
+			return false
+		}
+	}
+
+	if len(filter.Authors) > 0 {
+		byAuthor := false
+		for _, username := range filter.Authors {
+			if user, ok := s.userByUsername(username); ok && user.ID == article.AuthorID {
+				byAuthor = true
+			}
+		}
+		if !byAuthor {
+			return false
+		}
+	}
+
+	if filter.Favorited != "" {
+		user, ok := s.userByUsername(filter.Favorited)
+		if !ok {
+			return false
+		}
+		if _, liked := s.favorites[pair{user.ID, article.ID}]; !liked {
+			return false
+		}
+	}
+
+	if !filter.Since.IsZero() && article.CreatedAt.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && !article.CreatedAt.Before(filter.Until) {
+		return false
+	}
+	return true
+}
+
+// GetFeedArticles retrieves articles from followed users for personalized feed
+func (r *ArticleRepository) GetFeedArticles(ctx context.Context, page repository.Page, userID int) ([]model.Article, int, error) {
+	var articles []model.Article
+	var totalCount int
+	err := r.read(ctx, func(s *state) error {
+		var matches []model.Article
+		for _, article := range s.articles {
+			if _, following := s.follows[pair{userID, article.AuthorID}]; following && article.Status == model.ArticlePublished {
+				matches = append(matches, article)
+			}
+		}
+
+		totalCount = len(matches)
+		articles = s.page(matches, page)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return articles, totalCount, nil
+}
+
+// page sorts articles in the page's order and applies the limit and either
+// the offset or the cursor
+func (s *state) page(articles []model.Article, page repository.Page) []model.Article {
+	sortBy := page.Sort
+	if !sortBy.Valid() {
+		sortBy = repository.SortNewest
+	}
+	if sortBy == repository.SortMostCommented {
+		for i := range articles {
+			articles[i].CommentsCount = s.commentsCount(articles[i].ID)
+		}
+	}
+
+	sort.Slice(articles, func(i, j int) bool {
+		return precedes(articles[i], repository.CursorFor(articles[j], sortBy, false))
+	})
+
+	if cursor := page.Cursor; cursor != nil {
+		var side []model.Article
+		for _, article := range articles {
+			if article.ID == cursor.ID {
+				continue
+			}
+			if precedes(article, cursor) == cursor.Before {
+				side = append(side, article)
+			}
+		}
+
+		// Before the cursor, the page is the articles closest to it
+		if cursor.Before && page.Limit < len(side) {
+			return side[len(side)-page.Limit:]
+		}
+		if page.Limit < len(side) {
+			side = side[:page.Limit]
+		}
+		return side
+	}
+
+	if page.Offset >= len(articles) {
+		return nil
+	}
+	articles = articles[page.Offset:]
+	if page.Limit >= 0 && page.Limit < len(articles) {
+		articles = articles[:page.Limit]
+	}
+	return articles
+}
+
+// precedes reports whether article comes before the position of cursor in
+// the cursor's sort, ignoring its direction
+func precedes(article model.Article, cursor *repository.Cursor) bool {
+	key := repository.CursorFor(article, cursor.Sort, false)
+
+	var cmp int
+	switch {
+	case cursor.Sort == repository.SortMostFavorited || cursor.Sort == repository.SortMostCommented:
+		cmp = key.Count - cursor.Count
+	case key.Time.Before(cursor.Time):
+		cmp = -1
+	case key.Time.After(cursor.Time):
+		cmp = 1
+	}
+	if cmp == 0 {
+		cmp = article.ID - cursor.ID
+	}
+
+	if cursor.Sort == repository.SortOldest {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+// commentsCount counts the comments on an article
+func (s *state) commentsCount(articleID int) int {
+	count := 0
+	for _, comment := range s.comments {
+		if comment.ArticleID == articleID {
+			count++
+		}
+	}
+	return count
+}
+
+// favoritesCount counts the favorites of an article
+func (s *state) favoritesCount(articleID int) int {
+	count := 0
+	for key := range s.favorites {
+		if key[1] == articleID {
+			count++
+		}
+	}
+	return count
+}
+
+// FavoriteArticle adds an article to user's favorites
+func (r *ArticleRepository) FavoriteArticle(ctx context.Context, userID, articleID int) error {
+	return r.write(ctx, func(s *state) error {
+		key := pair{userID, articleID}
+		_, userExists := s.users[userID]
+		_, articleExists := s.articles[articleID]
+		_, alreadyFavorited := s.favorites[key]
+
+		switch {
+		case !userExists || !articleExists:
+			return fmt.Errorf("failed to favorite article: %w", errForeignKey)
+		case alreadyFavorited:
+			return fmt.Errorf("failed to favorite article: %w", uniqueViolation("favorites.user_id, favorites.article_id"))
+		}
+
+		s.favorites[key] = time.Now()
+		s.adjustFavoritesCount(articleID, 1)
+		return nil
+	})
+}
+
+// UnfavoriteArticle removes an article from user's favorites
+func (r *ArticleRepository) UnfavoriteArticle(ctx context.Context, userID, articleID int) error {
+	return r.write(ctx, func(s *state) error {
+		key := pair{userID, articleID}
+		if _, ok := s.favorites[key]; !ok {
+			return fmt.Errorf("favorite not found")
+		}
+
+		delete(s.favorites, key)
+		s.adjustFavoritesCount(articleID, -1)
+		return nil
+	})
+}
+
+// IsFavorited checks if an article is favorited by a user
+func (r *ArticleRepository) IsFavorited(ctx context.Context, userID, articleID int) (bool, error) {
+	var favorited bool
+	err := r.read(ctx, func(s *state) error {
+		_, favorited = s.favorites[pair{userID, articleID}]
+		return nil
+	})
+	return favorited, err
+}
+
+// PublishScheduled publishes the scheduled articles due at or before now
+// and returns how many it published
+func (r *ArticleRepository) PublishScheduled(ctx context.Context, now time.Time) (int, error) {
+	var published int
+	err := r.write(ctx, func(s *state) error {
+		for id, article := range s.articles {
+			if article.Status == model.ArticleScheduled && article.PublishAt != nil && !article.PublishAt.After(now) {
+				article.Status = model.ArticlePublished
+				s.articles[id] = article
+				published++
+			}
+		}
+		return nil
+	})
+	return published, err
+}
+
+// NextScheduledAt returns when the next scheduled article is due, or the
+// zero time when none is scheduled
+func (r *ArticleRepository) NextScheduledAt(ctx context.Context) (time.Time, error) {
+	var next time.Time
+	err := r.read(ctx, func(s *state) error {
+		for _, article := range s.articles {
+			if article.Status == model.ArticleScheduled && article.PublishAt != nil && (next.IsZero() || article.PublishAt.Before(next)) {
+				next = *article.PublishAt
+			}
+		}
+		return nil
+	})
+	return next, err
+}
+
+// GetFavoritedIDs reports which of the given articles a user has favorited
+func (r *ArticleRepository) GetFavoritedIDs(ctx context.Context, userID int, articleIDs []int) (map[int]bool, error) {
+	favorited := map[int]bool{}
+	err := r.read(ctx, func(s *state) error {
+		for _, id := range articleIDs {
+			if _, ok := s.favorites[pair{userID, id}]; ok {
+				favorited[id] = true
+			}
+		}
+		return nil
+	})
+	return favorited, err
+}
+
+// GetFavoritesCount returns the number of favorites for an article
+func (r *ArticleRepository) GetFavoritesCount(ctx context.Context, articleID int) (int, error) {
+	var count int
+	err := r.read(ctx, func(s *state) error {
+		article, ok := s.articles[articleID]
+		if !ok {
+			return fmt.Errorf("article not found")
+		}
+		count = article.FavoritesCount
+		return nil
+	})
+	return count, err
+}
+
+// ReconcileFavoritesCounts recomputes every article's favorites count from
+// the favorites and returns the number of articles that had drifted
+func (r *ArticleRepository) ReconcileFavoritesCounts(ctx context.Context) (int, error) {
+	var repaired int
+	err := r.write(ctx, func(s *state) error {
+		for id, article := range s.articles {
+			if count := s.favoritesCount(id); article.FavoritesCount != count {
+				article.FavoritesCount = count
+				s.articles[id] = article
+				repaired++
+			}
+		}
+		return nil
+	})
+	return repaired, err
+}
+
+// adjustFavoritesCount adds delta to an article's favorites count
+func (s *state) adjustFavoritesCount(articleID, delta int) {
+	article := s.articles[articleID]
+	article.FavoritesCount += delta
+	s.articles[articleID] = article
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// CommentRepository stores comments on articles in memory
+type CommentRepository struct {
+	conn
+}
+
+// Create creates a new comment
+func (r *CommentRepository) Create(ctx context.Context, comment *model.Comment) error {
+	return r.write(ctx, func(s *state) error {
+		_, authorExists := s.users[comment.AuthorID]
+		_, articleExists := s.articles[comment.ArticleID]
+		if !authorExists || !articleExists {
+			return fmt.Errorf("failed to create comment: %w", errForeignKey)
+		}
+
+		now := time.Now()
+		comment.ID = s.nextID("comments")
+		comment.CreatedAt = now
+		comment.UpdatedAt = now
+
+		stored := *comment
+		stored.Author = nil
+		s.comments[comment.ID] = stored
+		return nil
+	})
+}
+
+// GetByArticleSlug retrieves the comments of an article, newest first
+func (r *CommentRepository) GetByArticleSlug(ctx context.Context, slug string) ([]*model.Comment, error) {
+	var comments []*model.Comment
+	err := r.read(ctx, func(s *state) error {
+		article, ok := s.articleBySlug(slug)
+		if !ok {
+			return nil
+		}
+
+		for _, comment := range s.comments {
+			if comment.ArticleID == article.ID {
+				comments = append(comments, s.withAuthor(comment))
+			}
+		}
+		sort.SliceStable(comments, func(i, j int) bool {
+			return comments[i].CreatedAt.After(comments[j].CreatedAt)
+		})
+		return nil
+	})
+	return comments, err
+}
+
+// GetByID retrieves a comment by ID
+func (r *CommentRepository) GetByID(ctx context.Context, id int) (*model.Comment, error) {
+	var comment *model.Comment
+	err := r.read(ctx, func(s *state) error {
+		found, ok := s.comments[id]
+		if !ok {
+			return fmt.Errorf("comment not found")
+		}
+		comment = s.withAuthor(found)
+		return nil
+	})
+	return comment, err
+}
+
+// Delete deletes a comment by ID
+func (r *CommentRepository) Delete(ctx context.Context, id int) error {
+	return r.write(ctx, func(s *state) error {
+		if _, ok := s.comments[id]; !ok {
+			return fmt.Errorf("comment not found")
+		}
+
+		delete(s.comments, id)
+		return nil
+	})
+}
+
+// GetArticleIDBySlug retrieves an article's ID by slug
+func (r *CommentRepository) GetArticleIDBySlug(ctx context.Context, slug string) (int, error) {
+	var articleID int
+	err := r.read(ctx, func(s *state) error {
+		article, ok := s.articleBySlug(slug)
+		if !ok {
+			return fmt.Errorf("article not found")
+		}
+		articleID = article.ID
+		return nil
+	})
+	return articleID, err
+}
+
+// withAuthor copies a comment and fills in its author's profile
+func (s *state) withAuthor(comment model.Comment) *model.Comment {
+	author := s.users[comment.AuthorID]
+	comment.Author = &model.ProfileResponse{
+		Username: author.Username,
+		Bio:      author.Bio,
+		Image:    author.Image,
+	}
+	return &comment
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// GetRenders retrieves the cached renders of the current revisions of
+// articles made by the given renderer, keyed by article ID
+func (r *ArticleRepository) GetRenders(ctx context.Context, renderer int, articleIDs []int) (map[int]model.ArticleRender, error) {
+	renders := map[int]model.ArticleRender{}
+	err := r.read(ctx, func(s *state) error {
+		for _, id := range articleIDs {
+			article, ok := s.articles[id]
+			if !ok {
+				continue
+			}
+			if render, ok := s.renders[pair{id, article.Revision}]; ok && render.Renderer == renderer {
+				renders[id] = render
+			}
+		}
+		return nil
+	})
+	return renders, err
+}
+
+// SaveRender caches the render of a revision, replacing one made by
+// another renderer
+func (r *ArticleRepository) SaveRender(ctx context.Context, render *model.ArticleRender) error {
+	return r.write(ctx, func(s *state) error {
+		if _, ok := s.articles[render.ArticleID]; !ok {
+			return fmt.Errorf("failed to save render: %w", errForeignKey)
+		}
+
+		render.CreatedAt = time.Now()
+		s.renders[pair{render.ArticleID, render.Revision}] = *render
+		return nil
+	})
+}
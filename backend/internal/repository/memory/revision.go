@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// CreateRevision stores an earlier version of an article as its next
+// revision and sets the revision's number
+func (r *ArticleRepository) CreateRevision(ctx context.Context, revision *model.ArticleRevision) error {
+	return r.write(ctx, func(s *state) error {
+		if _, ok := s.articles[revision.ArticleID]; !ok {
+			return fmt.Errorf("failed to create revision: %w", errForeignKey)
+		}
+		if _, ok := s.users[revision.EditorID]; !ok {
+			return fmt.Errorf("failed to create revision: %w", errForeignKey)
+		}
+
+		revision.Revision = 1
+		for _, stored := range s.revisions {
+			if stored.ArticleID == revision.ArticleID && stored.Revision >= revision.Revision {
+				revision.Revision = stored.Revision + 1
+			}
+		}
+		revision.ID = s.nextID("article_revisions")
+		revision.CreatedAt = time.Now()
+
+		// Tags are stored as a list, like the JSON column
+		stored := *revision
+		stored.TagList = append([]string{}, revision.TagList...)
+		s.revisions[stored.ID] = stored
+		return nil
+	})
+}
+
+// GetRevisions retrieves the revisions of an article, newest first
+func (r *ArticleRepository) GetRevisions(ctx context.Context, articleID int) ([]model.ArticleRevision, error) {
+	revisions := []model.ArticleRevision{}
+	err := r.read(ctx, func(s *state) error {
+		for _, revision := range s.revisions {
+			if revision.ArticleID == articleID {
+				revisions = append(revisions, revision)
+			}
+		}
+		sort.Slice(revisions, func(i, j int) bool {
+			return revisions[i].Revision > revisions[j].Revision
+		})
+		return nil
+	})
+	return revisions, err
+}
+
+// GetRevision retrieves one revision of an article by its number
+func (r *ArticleRepository) GetRevision(ctx context.Context, articleID, number int) (*model.ArticleRevision, error) {
+	var revision *model.ArticleRevision
+	err := r.read(ctx, func(s *state) error {
+		for _, stored := range s.revisions {
+			if stored.ArticleID == articleID && stored.Revision == number {
+				found := stored
+				revision = &found
+				return nil
+			}
+		}
+		return fmt.Errorf("revision not found")
+	})
+	return revision, err
+}
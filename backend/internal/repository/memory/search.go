@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+)
+
+// snippetWords is how many words a search snippet holds
+const snippetWords = 24
+
+// SearchArticles returns the articles matching every word of query, best
+// match first, each with a highlighted snippet. A word matches any word it
+// is a prefix of, which stands in for the database's stemming.
+func (r *ArticleRepository) SearchArticles(ctx context.Context, query string, limit, offset int) ([]model.SearchResult, int, error) {
+	terms := repository.SearchTerms(query)
+	results := []model.SearchResult{}
+	var totalCount int
+	err := r.read(ctx, func(s *state) error {
+		if len(terms) == 0 {
+			return nil
+		}
+
+		type scored struct {
+			article model.Article
+			score   int
+		}
+		var matches []scored
+		for _, article := range s.articles {
+			if article.Status != model.ArticlePublished {
+				continue
+			}
+
+			// Weighted like the SQLite ranking: title, tags, description, body
+			fields := []struct {
+				text   string
+				weight int
+			}{
+				{article.Title, 4},
+				{strings.Join(s.articleTags(article.ID), " "), 3},
+				{article.Description, 2},
+				{article.Body, 1},
+			}
+
+			score := 0
+			for _, term := range terms {
+				hits := 0
+				for _, field := range fields {
+					hits += field.weight * countMatches(field.text, term)
+				}
+				if hits == 0 {
+					score = 0
+					break
+				}
+				score += hits
+			}
+			if score > 0 {
+				matches = append(matches, scored{article, score})
+			}
+		}
+
+		sort.Slice(matches, func(i, j int) bool {
+			if matches[i].score != matches[j].score {
+				return matches[i].score > matches[j].score
+			}
+			return matches[i].article.ID > matches[j].article.ID
+		})
+
+		totalCount = len(matches)
+		if offset >= len(matches) {
+			return nil
+		}
+		matches = matches[offset:]
+		if limit < len(matches) {
+			matches = matches[:limit]
+		}
+
+		for _, match := range matches {
+			// The snippet comes from the first of these with a match
+			var snippet string
+			for _, text := range []string{
+				match.article.Description + " " + match.article.Body,
+				match.article.Title,
+				strings.Join(s.articleTags(match.article.ID), " "),
+			} {
+				if snippet = highlight(text, terms); strings.Contains(snippet, "<mark>") {
+					break
+				}
+			}
+			results = append(results, model.SearchResult{Article: match.article, Snippet: snippet})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, totalCount, nil
+}
+
+// searchWord normalizes a word of text the way search terms are
+func searchWord(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+}
+
+// matchesTerm reports whether a word of text matches a search term
+func matchesTerm(word string, terms ...string) bool {
+	word = searchWord(word)
+	for _, term := range terms {
+		if word != "" && strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// countMatches counts the words of text matching term
+func countMatches(text, term string) int {
+	count := 0
+	for _, word := range strings.Fields(text) {
+		if matchesTerm(word, term) {
+			count++
+		}
+	}
+	return count
+}
+
+// highlight returns an escaped window of text around its first match with
+// the matching words in <mark>
+func highlight(text string, terms []string) string {
+	words := strings.Fields(text)
+	start := 0
+	for i, word := range words {
+		if matchesTerm(word, terms...) {
+			start = i - snippetWords/4
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetWords
+	if end > len(words) {
+		end = len(words)
+	}
+
+	parts := make([]string, 0, end-start+2)
+	if start > 0 {
+		parts = append(parts, "…")
+	}
+	for _, word := range words[start:end] {
+		if matchesTerm(word, terms...) {
+			parts = append(parts, "<mark>"+html.EscapeString(word)+"</mark>")
+		} else {
+			parts = append(parts, html.EscapeString(word))
+		}
+	}
+	if end < len(words) {
+		parts = append(parts, "…")
+	}
+	return strings.Join(parts, " ")
+}
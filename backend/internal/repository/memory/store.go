@@ -0,0 +1,237 @@
+// Package memory implements the repository interfaces in memory. It mirrors
+// the SQL repositories closely enough for service and handler tests: the
+// same filters, ordering and pagination, the same error messages, and the
+// database's unique, check and foreign key constraints.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+)
+
+// errForeignKey matches SQLite's message for foreign key violations
+var errForeignKey = fmt.Errorf("FOREIGN KEY constraint failed")
+
+// uniqueViolation matches SQLite's message for unique constraint violations
+func uniqueViolation(columns string) error {
+	return fmt.Errorf("UNIQUE constraint failed: %s", columns)
+}
+
+// pair keys the join tables
+type pair [2]int
+
+// state holds the rows of every table
+type state struct {
+	users     map[int]model.User
+	articles  map[int]model.Article
+	tags      map[int]string
+	tagLinks  map[pair]time.Time // (article ID, tag ID)
+	follows   map[pair]time.Time // (follower ID, followed ID)
+	favorites map[pair]time.Time // (user ID, article ID)
+	comments  map[int]model.Comment
+	revisions map[int]model.ArticleRevision
+	renders   map[pair]model.ArticleRender // (article ID, revision)
+	lastID    map[string]int
+}
+
+// newState returns empty tables
+func newState() *state {
+	return &state{
+		users:     map[int]model.User{},
+		articles:  map[int]model.Article{},
+		tags:      map[int]string{},
+		tagLinks:  map[pair]time.Time{},
+		follows:   map[pair]time.Time{},
+		favorites: map[pair]time.Time{},
+		comments:  map[int]model.Comment{},
+		revisions: map[int]model.ArticleRevision{},
+		renders:   map[pair]model.ArticleRender{},
+		lastID:    map[string]int{},
+	}
+}
+
+// clone copies every table so a failed write can be undone
+func (s *state) clone() *state {
+	c := newState()
+	for k, v := range s.users {
+		c.users[k] = v
+	}
+	for k, v := range s.articles {
+		c.articles[k] = v
+	}
+	for k, v := range s.tags {
+		c.tags[k] = v
+	}
+	for k, v := range s.tagLinks {
+		c.tagLinks[k] = v
+	}
+	for k, v := range s.follows {
+		c.follows[k] = v
+	}
+	for k, v := range s.favorites {
+		c.favorites[k] = v
+	}
+	for k, v := range s.comments {
+		c.comments[k] = v
+	}
+	for k, v := range s.revisions {
+		c.revisions[k] = v
+	}
+	for k, v := range s.renders {
+		c.renders[k] = v
+	}
+	for k, v := range s.lastID {
+		c.lastID[k] = v
+	}
+	return c
+}
+
+// nextID returns the next auto-increment ID of a table
+func (s *state) nextID(table string) int {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// articleBySlug finds an article by slug
+func (s *state) articleBySlug(slug string) (model.Article, bool) {
+	for _, article := range s.articles {
+		if article.Slug == slug {
+			return article, true
+		}
+	}
+	return model.Article{}, false
+}
+
+// userByUsername finds a user by username
+func (s *state) userByUsername(username string) (model.User, bool) {
+	for _, user := range s.users {
+		if user.Username == username {
+			return user, true
+		}
+	}
+	return model.User{}, false
+}
+
+// tagID finds a tag by name
+func (s *state) tagID(name string) (int, bool) {
+	for id, tagName := range s.tags {
+		if tagName == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Store holds the data shared by the in-memory repositories
+type Store struct {
+	mu   sync.Mutex
+	data *state
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{data: newState()}
+}
+
+// Users returns the user repository of the store
+func (s *Store) Users() *UserRepository {
+	return &UserRepository{conn{store: s}}
+}
+
+// Articles returns the article repository of the store
+func (s *Store) Articles() *ArticleRepository {
+	return &ArticleRepository{conn{store: s}}
+}
+
+// Tags returns the tag repository of the store
+func (s *Store) Tags() *TagRepository {
+	return &TagRepository{conn{store: s}}
+}
+
+// Comments returns the comment repository of the store
+func (s *Store) Comments() *CommentRepository {
+	return &CommentRepository{conn{store: s}}
+}
+
+// Do runs fn with repositories bound to a transaction. Transactions run one
+// at a time, and every write made in fn is undone when fn fails.
+func (s *Store) Do(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.data.clone()
+	tx := conn{store: s, inTx: true}
+	repos := &repository.Repositories{
+		Users:    &UserRepository{tx},
+		Articles: &ArticleRepository{tx},
+		Tags:     &TagRepository{tx},
+		Comments: &CommentRepository{tx},
+	}
+
+	if err := fn(repos); err != nil {
+		s.data = snapshot
+		return err
+	}
+
+	return nil
+}
+
+// conn gives a repository access to the store's tables, either on its own
+// or inside a transaction that already holds the lock
+type conn struct {
+	store *Store
+	inTx  bool
+}
+
+// read runs fn against the tables
+func (c conn) read(ctx context.Context, fn func(s *state) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !c.inTx {
+		c.store.mu.Lock()
+		defer c.store.mu.Unlock()
+	}
+
+	return fn(c.store.data)
+}
+
+// write runs fn against the tables and undoes its changes if it fails, like
+// a single SQL statement or repository-level transaction
+func (c conn) write(ctx context.Context, fn func(s *state) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !c.inTx {
+		c.store.mu.Lock()
+		defer c.store.mu.Unlock()
+	}
+
+	snapshot := c.store.data.clone()
+	if err := fn(c.store.data); err != nil {
+		c.store.data = snapshot
+		return err
+	}
+
+	return nil
+}
+
+// The in-memory repositories implement the store interfaces
+var (
+	_ repository.UserStore    = (*UserRepository)(nil)
+	_ repository.ArticleStore = (*ArticleRepository)(nil)
+	_ repository.TagStore     = (*TagRepository)(nil)
+	_ repository.CommentStore = (*CommentRepository)(nil)
+	_ repository.Transactor   = (*Store)(nil)
+)
@@ -0,0 +1,805 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository/memory"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/migrations"
+)
+
+// backend is one implementation of the repository interfaces
+type backend struct {
+	repos *repository.Repositories
+	uow   repository.Transactor
+}
+
+// forEachBackend runs fn against the in-memory store and against the SQL
+// repositories on a migrated SQLite database, so every assertion made in fn
+// checks that the two behave alike
+func forEachBackend(t *testing.T, fn func(t *testing.T, b backend)) {
+	t.Helper()
+
+	t.Run("memory", func(t *testing.T) {
+		store := memory.NewStore()
+		fn(t, backend{
+			repos: &repository.Repositories{
+				Users:    store.Users(),
+				Articles: store.Articles(),
+				Tags:     store.Tags(),
+				Comments: store.Comments(),
+			},
+			uow: store,
+		})
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		database, err := db.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), db.SQLite)
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		t.Cleanup(func() { database.Close() })
+
+		if err := db.NewMigrationManager(database.DB, db.SQLite).RunMigrations(migrations.FS); err != nil {
+			t.Fatalf("Failed to run migrations: %v", err)
+		}
+
+		fn(t, backend{
+			repos: &repository.Repositories{
+				Users:    repository.NewUserRepository(database),
+				Articles: repository.NewArticleRepository(database),
+				Tags:     repository.NewTagRepository(database),
+				Comments: repository.NewCommentRepository(database),
+			},
+			uow: repository.NewUnitOfWork(database),
+		})
+	})
+}
+
+// createUser inserts a user with the given username
+func createUser(t *testing.T, repos *repository.Repositories, username string) *model.User {
+	t.Helper()
+
+	user := &model.User{Email: username + "@example.com", Username: username, PasswordHash: "hash"}
+	if err := repos.Users.Create(context.Background(), user); err != nil {
+		t.Fatalf("Failed to create user %s: %v", username, err)
+	}
+	return user
+}
+
+// createArticle inserts an article with the given slug and tags
+func createArticle(t *testing.T, repos *repository.Repositories, slug string, authorID int, tags ...string) *model.Article {
+	ctx := context.Background()
+	t.Helper()
+
+	article := &model.Article{Slug: slug, Title: "Title " + slug, Description: "About " + slug, Body: "Body " + slug, AuthorID: authorID}
+	if err := repos.Articles.Create(ctx, article); err != nil {
+		t.Fatalf("Failed to create article %s: %v", slug, err)
+	}
+	if err := repos.Tags.CreateTagsForArticle(ctx, article.ID, tags); err != nil {
+		t.Fatalf("Failed to create tags for %s: %v", slug, err)
+	}
+	return article
+}
+
+// slugs lists the slugs of articles in order
+func slugs(articles []model.Article) []string {
+	result := []string{}
+	for _, article := range articles {
+		result = append(result, article.Slug)
+	}
+	return result
+}
+
+// wantErr fails the test unless err's message contains want
+func wantErr(t *testing.T, op string, err error, want string) {
+	t.Helper()
+
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("%s error = %v, want %q", op, err, want)
+	}
+}
+
+func TestUniqueConstraints(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		celeb := createUser(t, b.repos, "celeb")
+
+		err := b.repos.Users.Create(ctx, &model.User{Email: "jake@example.com", Username: "other", PasswordHash: "hash"})
+		wantErr(t, "Create() duplicate email", err, "UNIQUE constraint failed: users.email")
+		err = b.repos.Users.Create(ctx, &model.User{Email: "other@example.com", Username: "jake", PasswordHash: "hash"})
+		wantErr(t, "Create() duplicate username", err, "UNIQUE constraint failed: users.username")
+
+		celeb.Username = "jake"
+		wantErr(t, "Update() to taken username", b.repos.Users.Update(ctx, celeb), "UNIQUE constraint failed: users.username")
+
+		createArticle(t, b.repos, "dragons", jake.ID)
+		err = b.repos.Articles.Create(ctx, &model.Article{Slug: "dragons", Title: "t", Description: "d", Body: "b", AuthorID: jake.ID})
+		wantErr(t, "Create() duplicate slug", err, "UNIQUE constraint failed: articles.slug")
+		err = b.repos.Articles.Create(ctx, &model.Article{Slug: "orphan", Title: "t", Description: "d", Body: "b", AuthorID: 999})
+		wantErr(t, "Create() unknown author", err, "FOREIGN KEY constraint failed")
+
+		createArticle(t, b.repos, "wyverns", jake.ID)
+		_, err = b.repos.Articles.Update(ctx, "wyverns", map[string]interface{}{"slug": "dragons"})
+		wantErr(t, "Update() to taken slug", err, "UNIQUE constraint failed: articles.slug")
+
+		wantErr(t, "FollowUser() self", b.repos.Users.FollowUser(ctx, jake.ID, jake.ID), "CHECK constraint failed")
+		if err := b.repos.Users.FollowUser(ctx, jake.ID, celeb.ID); err != nil {
+			t.Fatalf("FollowUser() error = %v", err)
+		}
+		wantErr(t, "FollowUser() twice", b.repos.Users.FollowUser(ctx, jake.ID, celeb.ID), "UNIQUE constraint failed")
+		wantErr(t, "UnfollowUser() unknown", b.repos.Users.UnfollowUser(ctx, celeb.ID, jake.ID), "follow relationship not found")
+
+		article, _ := b.repos.Articles.GetBySlug(ctx, "dragons")
+		if err := b.repos.Articles.FavoriteArticle(ctx, celeb.ID, article.ID); err != nil {
+			t.Fatalf("FavoriteArticle() error = %v", err)
+		}
+		wantErr(t, "FavoriteArticle() twice", b.repos.Articles.FavoriteArticle(ctx, celeb.ID, article.ID), "UNIQUE constraint failed")
+		wantErr(t, "FavoriteArticle() unknown article", b.repos.Articles.FavoriteArticle(ctx, celeb.ID, 999), "FOREIGN KEY constraint failed")
+	})
+}
+
+func TestNotFoundErrors(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		_, err := b.repos.Users.GetByID(ctx, 999)
+		wantErr(t, "GetByID()", err, "user not found")
+		_, err = b.repos.Users.GetByEmail(ctx, "nobody@example.com")
+		wantErr(t, "GetByEmail()", err, "user not found")
+		_, err = b.repos.Users.GetProfileByUsername(ctx, "nobody", nil)
+		wantErr(t, "GetProfileByUsername()", err, "user not found")
+
+		_, err = b.repos.Articles.GetBySlug(ctx, "missing")
+		wantErr(t, "GetBySlug()", err, "article not found")
+		_, err = b.repos.Articles.Update(ctx, "missing", map[string]interface{}{"body": "x"})
+		wantErr(t, "Update()", err, "article not found")
+		wantErr(t, "Delete()", b.repos.Articles.Delete(ctx, "missing"), "article not found")
+		wantErr(t, "UnfavoriteArticle()", b.repos.Articles.UnfavoriteArticle(ctx, 1, 1), "favorite not found")
+
+		_, err = b.repos.Comments.GetByID(ctx, 999)
+		wantErr(t, "Comments.GetByID()", err, "comment not found")
+		wantErr(t, "Comments.Delete()", b.repos.Comments.Delete(ctx, 999), "comment not found")
+		_, err = b.repos.Comments.GetArticleIDBySlug(ctx, "missing")
+		wantErr(t, "GetArticleIDBySlug()", err, "article not found")
+	})
+}
+
+func TestArticleFiltersAndPagination(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		celeb := createUser(t, b.repos, "celeb")
+		reader := createUser(t, b.repos, "reader")
+
+		first := createArticle(t, b.repos, "first", jake.ID, "go", "sql")
+		second := createArticle(t, b.repos, "second", celeb.ID, "go")
+		third := createArticle(t, b.repos, "third", celeb.ID)
+		createArticle(t, b.repos, "fourth", jake.ID, "sql")
+
+		for _, userID := range []int{celeb.ID, reader.ID} {
+			if err := b.repos.Articles.FavoriteArticle(ctx, userID, first.ID); err != nil {
+				t.Fatalf("FavoriteArticle() error = %v", err)
+			}
+		}
+		if err := b.repos.Articles.FavoriteArticle(ctx, reader.ID, second.ID); err != nil {
+			t.Fatalf("FavoriteArticle() error = %v", err)
+		}
+
+		tests := []struct {
+			name          string
+			limit, offset int
+			filter        repository.ArticleFilter
+			wantSlugs     []string
+			wantCount     int
+		}{
+			{name: "all", limit: 10, wantSlugs: []string{"fourth", "third", "second", "first"}, wantCount: 4},
+			{name: "first page", limit: 2, wantSlugs: []string{"fourth", "third"}, wantCount: 4},
+			{name: "second page", limit: 2, offset: 2, wantSlugs: []string{"second", "first"}, wantCount: 4},
+			{name: "past the end", limit: 2, offset: 10, wantSlugs: []string{}, wantCount: 4},
+			{name: "tag", limit: 10, filter: repository.ArticleFilter{Tags: []string{"go"}}, wantSlugs: []string{"second", "first"}, wantCount: 2},
+			{name: "unknown tag", limit: 10, filter: repository.ArticleFilter{Tags: []string{"rust"}}, wantSlugs: []string{}, wantCount: 0},
+			{name: "any tag", limit: 10, filter: repository.ArticleFilter{Tags: []string{"go", "sql"}}, wantSlugs: []string{"fourth", "second", "first"}, wantCount: 3},
+			{name: "any tag paged", limit: 1, offset: 1, filter: repository.ArticleFilter{Tags: []string{"go", "sql"}}, wantSlugs: []string{"second"}, wantCount: 3},
+			{name: "all tags", limit: 10, filter: repository.ArticleFilter{Tags: []string{"go", "sql"}, MatchAllTags: true}, wantSlugs: []string{"first"}, wantCount: 1},
+			{name: "all tags with unknown", limit: 10, filter: repository.ArticleFilter{Tags: []string{"go", "rust"}, MatchAllTags: true}, wantSlugs: []string{}, wantCount: 0},
+			{name: "excluded tag", limit: 10, filter: repository.ArticleFilter{ExcludeTags: []string{"sql"}}, wantSlugs: []string{"third", "second"}, wantCount: 2},
+			{name: "tag and excluded tag", limit: 10, filter: repository.ArticleFilter{Tags: []string{"go"}, ExcludeTags: []string{"sql"}}, wantSlugs: []string{"second"}, wantCount: 1},
+			{name: "author", limit: 10, filter: repository.ArticleFilter{Authors: []string{"jake"}}, wantSlugs: []string{"fourth", "first"}, wantCount: 2},
+			{name: "authors", limit: 10, filter: repository.ArticleFilter{Authors: []string{"jake", "celeb", "nobody"}}, wantSlugs: []string{"fourth", "third", "second", "first"}, wantCount: 4},
+			{name: "favorited", limit: 10, filter: repository.ArticleFilter{Favorited: "reader"}, wantSlugs: []string{"second", "first"}, wantCount: 2},
+			{name: "unknown favoriter", limit: 10, filter: repository.ArticleFilter{Favorited: "nobody"}, wantSlugs: []string{}, wantCount: 0},
+			{name: "since", limit: 10, filter: repository.ArticleFilter{Since: second.CreatedAt}, wantSlugs: []string{"fourth", "third", "second"}, wantCount: 3},
+			{name: "until", limit: 10, filter: repository.ArticleFilter{Until: third.CreatedAt}, wantSlugs: []string{"second", "first"}, wantCount: 2},
+			{name: "since and until", limit: 10, filter: repository.ArticleFilter{Since: second.CreatedAt, Until: third.CreatedAt}, wantSlugs: []string{"second"}, wantCount: 1},
+			{name: "combined", limit: 10, filter: repository.ArticleFilter{Tags: []string{"sql"}, Authors: []string{"jake"}, Favorited: "celeb"}, wantSlugs: []string{"first"}, wantCount: 1},
+		}
+
+		for _, tt := range tests {
+			articles, count, err := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: tt.limit, Offset: tt.offset}, tt.filter)
+			if err != nil {
+				t.Fatalf("%s: GetArticles() error = %v", tt.name, err)
+			}
+			if got := slugs(articles); !reflect.DeepEqual(got, tt.wantSlugs) || count != tt.wantCount {
+				t.Errorf("%s: GetArticles() = %v, %d; want %v, %d", tt.name, got, count, tt.wantSlugs, tt.wantCount)
+			}
+		}
+
+		articles, _, _ := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 10}, repository.ArticleFilter{})
+		favorites := map[string]int{}
+		for _, article := range articles {
+			favorites[article.Slug] = article.FavoritesCount
+		}
+		if want := map[string]int{"first": 2, "second": 1, "third": 0, "fourth": 0}; !reflect.DeepEqual(favorites, want) {
+			t.Errorf("GetArticles() favorites counts = %v, want %v", favorites, want)
+		}
+		if fetched, _ := b.repos.Articles.GetBySlug(ctx, "first"); fetched.FavoritesCount != 2 {
+			t.Errorf("GetBySlug() favorites count = %d, want 2", fetched.FavoritesCount)
+		}
+		if repaired, err := b.repos.Articles.ReconcileFavoritesCounts(ctx); err != nil || repaired != 0 {
+			t.Errorf("ReconcileFavoritesCounts() = %d, %v; want nothing to repair", repaired, err)
+		}
+
+		if err := b.repos.Users.FollowUser(ctx, reader.ID, celeb.ID); err != nil {
+			t.Fatalf("FollowUser() error = %v", err)
+		}
+		feed, count, err := b.repos.Articles.GetFeedArticles(ctx, repository.Page{Limit: 1, Offset: 1}, reader.ID)
+		if err != nil || count != 2 || !reflect.DeepEqual(slugs(feed), []string{"second"}) {
+			t.Errorf("GetFeedArticles() = %v, %d, %v; want [second] of 2", slugs(feed), count, err)
+		}
+	})
+}
+
+func TestBatchLookups(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		celeb := createUser(t, b.repos, "celeb")
+		reader := createUser(t, b.repos, "reader")
+
+		first := createArticle(t, b.repos, "first", jake.ID, "sql", "go")
+		second := createArticle(t, b.repos, "second", celeb.ID)
+		third := createArticle(t, b.repos, "third", celeb.ID, "rust")
+
+		if err := b.repos.Users.FollowUser(ctx, reader.ID, celeb.ID); err != nil {
+			t.Fatalf("FollowUser() error = %v", err)
+		}
+		if err := b.repos.Articles.FavoriteArticle(ctx, reader.ID, third.ID); err != nil {
+			t.Fatalf("FavoriteArticle() error = %v", err)
+		}
+
+		users, err := b.repos.Users.GetByIDs(ctx, []int{jake.ID, celeb.ID, 999})
+		if err != nil || len(users) != 2 || users[jake.ID].Username != "jake" || users[celeb.ID].Username != "celeb" {
+			t.Errorf("GetByIDs() = %v, %v; want jake and celeb", users, err)
+		}
+
+		followed, err := b.repos.Users.GetFollowedIDs(ctx, reader.ID, []int{jake.ID, celeb.ID})
+		if want := map[int]bool{celeb.ID: true}; err != nil || !reflect.DeepEqual(followed, want) {
+			t.Errorf("GetFollowedIDs() = %v, %v; want %v", followed, err, want)
+		}
+
+		favorited, err := b.repos.Articles.GetFavoritedIDs(ctx, reader.ID, []int{first.ID, second.ID, third.ID})
+		if want := map[int]bool{third.ID: true}; err != nil || !reflect.DeepEqual(favorited, want) {
+			t.Errorf("GetFavoritedIDs() = %v, %v; want %v", favorited, err, want)
+		}
+
+		tags, err := b.repos.Tags.GetTagsForArticles(ctx, []int{first.ID, second.ID, third.ID})
+		if want := map[int][]string{first.ID: {"go", "sql"}, third.ID: {"rust"}}; err != nil || !reflect.DeepEqual(tags, want) {
+			t.Errorf("GetTagsForArticles() = %v, %v; want %v", tags, err, want)
+		}
+
+		// Empty lists need no query
+		if users, err := b.repos.Users.GetByIDs(ctx, nil); err != nil || len(users) != 0 {
+			t.Errorf("GetByIDs(nil) = %v, %v; want none", users, err)
+		}
+		if tags, err := b.repos.Tags.GetTagsForArticles(ctx, nil); err != nil || len(tags) != 0 {
+			t.Errorf("GetTagsForArticles(nil) = %v, %v; want none", tags, err)
+		}
+	})
+}
+
+func TestTagsAndCascades(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		celeb := createUser(t, b.repos, "celeb")
+
+		first := createArticle(t, b.repos, "first", jake.ID, "go", "sql")
+		createArticle(t, b.repos, "second", jake.ID, "go")
+
+		// Creating tags again ignores links that already exist
+		if err := b.repos.Tags.CreateTagsForArticle(ctx, first.ID, []string{"go", "testing"}); err != nil {
+			t.Fatalf("CreateTagsForArticle() error = %v", err)
+		}
+		tags, _ := b.repos.Tags.GetTagsForArticle(ctx, first.ID)
+		if !reflect.DeepEqual(tags, []string{"go", "sql", "testing"}) {
+			t.Errorf("GetTagsForArticle() = %v, want [go sql testing]", tags)
+		}
+		popular, _ := b.repos.Tags.GetPopularTags(ctx, 2)
+		if !reflect.DeepEqual(popular, []string{"go", "sql"}) {
+			t.Errorf("GetPopularTags() = %v, want [go sql]", popular)
+		}
+
+		// Replacing tags rejects duplicates and leaves the old tags in place
+		wantErr(t, "UpdateTagsForArticle() duplicate", b.repos.Tags.UpdateTagsForArticle(ctx, first.ID, []string{"a", "a"}), "UNIQUE constraint failed")
+		tags, _ = b.repos.Articles.GetArticleTags(ctx, first.ID)
+		if !reflect.DeepEqual(tags, []string{"go", "sql", "testing"}) {
+			t.Errorf("GetArticleTags() after failed update = %v, want [go sql testing]", tags)
+		}
+		if exists, _ := b.repos.Tags.TagExists(ctx, "a"); exists {
+			t.Error("Expected the failed update to create no tags")
+		}
+
+		comment := &model.Comment{Body: "Nice", AuthorID: celeb.ID, ArticleID: first.ID}
+		if err := b.repos.Comments.Create(ctx, comment); err != nil {
+			t.Fatalf("Comments.Create() error = %v", err)
+		}
+		comments, err := b.repos.Comments.GetByArticleSlug(ctx, "first")
+		if err != nil || len(comments) != 1 || comments[0].Author.Username != "celeb" {
+			t.Fatalf("GetByArticleSlug() = %v, %v; want one comment by celeb", comments, err)
+		}
+		if err := b.repos.Articles.FavoriteArticle(ctx, celeb.ID, first.ID); err != nil {
+			t.Fatalf("FavoriteArticle() error = %v", err)
+		}
+
+		if err := b.repos.Articles.Delete(ctx, "first"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := b.repos.Comments.GetByID(ctx, comment.ID); err == nil {
+			t.Error("Expected deleting an article to delete its comments")
+		}
+		if favorited, _ := b.repos.Articles.IsFavorited(ctx, celeb.ID, first.ID); favorited {
+			t.Error("Expected deleting an article to delete its favorites")
+		}
+		if count, _ := b.repos.Tags.GetArticleCountByTag(ctx, "sql"); count != 0 {
+			t.Errorf("GetArticleCountByTag(sql) = %d, want 0", count)
+		}
+
+		if err := b.repos.Tags.DeleteUnusedTags(ctx); err != nil {
+			t.Fatalf("DeleteUnusedTags() error = %v", err)
+		}
+		all, _ := b.repos.Tags.GetAllTags(ctx)
+		if !reflect.DeepEqual(all, []string{"go"}) {
+			t.Errorf("GetAllTags() = %v, want [go]", all)
+		}
+	})
+}
+
+func TestTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		errInjected := errors.New("injected failure")
+
+		err := b.uow.Do(ctx, func(repos *repository.Repositories) error {
+			article := createArticle(t, repos, "draft", jake.ID, "go")
+
+			// Writes are visible inside the transaction
+			if tags, _ := repos.Articles.GetArticleTags(ctx, article.ID); len(tags) != 1 {
+				return fmt.Errorf("tags inside transaction = %v", tags)
+			}
+			return errInjected
+		})
+		if !errors.Is(err, errInjected) {
+			t.Fatalf("Do() error = %v, want injected failure", err)
+		}
+
+		if exists, _ := b.repos.Articles.CheckArticleExists(ctx, "draft"); exists {
+			t.Error("Expected the rolled back article to be gone")
+		}
+		if exists, _ := b.repos.Tags.TagExists(ctx, "go"); exists {
+			t.Error("Expected the rolled back tag to be gone")
+		}
+
+		err = b.uow.Do(ctx, func(repos *repository.Repositories) error {
+			createArticle(t, repos, "published", jake.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if exists, _ := b.repos.Articles.CheckArticleExists(ctx, "published"); !exists {
+			t.Error("Expected the committed article to exist")
+		}
+	})
+}
+
+func TestCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	forEachBackend(t, func(t *testing.T, b backend) {
+		if _, err := b.repos.Users.GetByID(ctx, 1); !errors.Is(err, context.Canceled) {
+			t.Errorf("GetByID() error = %v, want context canceled", err)
+		}
+		if _, _, err := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 10}, repository.ArticleFilter{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("GetArticles() error = %v, want context canceled", err)
+		}
+		err := b.uow.Do(ctx, func(repos *repository.Repositories) error { return nil })
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Do() error = %v, want context canceled", err)
+		}
+	})
+}
+
+func TestSearchArticles(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+
+		createArticle(t, b.repos, "alpha", jake.ID)
+		createArticle(t, b.repos, "beta", jake.ID)
+		createArticle(t, b.repos, "gamma", jake.ID, "dragon")
+		createArticle(t, b.repos, "delta", jake.ID)
+		if _, err := b.repos.Articles.Update(ctx, "alpha", map[string]interface{}{"title": "Dragon riders"}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if _, err := b.repos.Articles.Update(ctx, "beta", map[string]interface{}{"body": "Then a dragon appears"}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		tests := []struct {
+			name          string
+			query         string
+			limit, offset int
+			wantSlugs     []string
+			wantCount     int
+		}{
+			{name: "title before tags before body", query: "dragon", limit: 10, wantSlugs: []string{"alpha", "gamma", "beta"}, wantCount: 3},
+			{name: "second page", query: "dragon", limit: 2, offset: 2, wantSlugs: []string{"beta"}, wantCount: 3},
+			{name: "every word must match", query: "dragon appears", limit: 10, wantSlugs: []string{"beta"}, wantCount: 1},
+			{name: "no match", query: "wyvern", limit: 10, wantSlugs: []string{}, wantCount: 0},
+			{name: "no words", query: "?!", limit: 10, wantSlugs: []string{}, wantCount: 0},
+		}
+
+		for _, tt := range tests {
+			results, count, err := b.repos.Articles.SearchArticles(ctx, tt.query, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("%s: SearchArticles() error = %v", tt.name, err)
+			}
+			var articles []model.Article
+			for _, result := range results {
+				articles = append(articles, result.Article)
+				if !strings.Contains(result.Snippet, "<mark>") {
+					t.Errorf("%s: snippet of %s = %q, want a highlighted match", tt.name, result.Article.Slug, result.Snippet)
+				}
+			}
+			if got := slugs(articles); !reflect.DeepEqual(got, tt.wantSlugs) || count != tt.wantCount {
+				t.Errorf("%s: SearchArticles() = %v, %d; want %v, %d", tt.name, got, count, tt.wantSlugs, tt.wantCount)
+			}
+		}
+	})
+}
+
+func TestCursorPages(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		for _, slug := range []string{"one", "two", "three", "four", "five"} {
+			createArticle(t, b.repos, slug, jake.ID)
+		}
+
+		first, count, err := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 2}, repository.ArticleFilter{})
+		if err != nil || !reflect.DeepEqual(slugs(first), []string{"five", "four"}) {
+			t.Fatalf("GetArticles() = %v, %v; want [five four]", slugs(first), err)
+		}
+
+		tests := []struct {
+			name      string
+			cursor    *repository.Cursor
+			wantSlugs []string
+		}{
+			{"after four", repository.CursorFor(first[1], repository.SortNewest, false), []string{"three", "two"}},
+			{"after five", repository.CursorFor(first[0], repository.SortNewest, false), []string{"four", "three"}},
+			{"before four", repository.CursorFor(first[1], repository.SortNewest, true), []string{"five"}},
+			{"before five", repository.CursorFor(first[0], repository.SortNewest, true), []string{}},
+		}
+
+		for _, tt := range tests {
+			articles, total, err := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 2, Cursor: tt.cursor}, repository.ArticleFilter{})
+			if err != nil {
+				t.Fatalf("%s: GetArticles() error = %v", tt.name, err)
+			}
+			if got := slugs(articles); !reflect.DeepEqual(got, tt.wantSlugs) || total != count {
+				t.Errorf("%s: GetArticles() = %v, %d; want %v, %d", tt.name, got, total, tt.wantSlugs, count)
+			}
+		}
+
+		oldest, err := b.repos.Articles.GetBySlug(ctx, "one")
+		if err != nil {
+			t.Fatalf("GetBySlug() error = %v", err)
+		}
+		articles, _, err := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 2, Cursor: repository.CursorFor(*oldest, repository.SortNewest, true)}, repository.ArticleFilter{})
+		if err != nil || !reflect.DeepEqual(slugs(articles), []string{"three", "two"}) {
+			t.Errorf("GetArticles(before one) = %v, %v; want [three two]", slugs(articles), err)
+		}
+	})
+}
+
+func TestSortedPages(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		celeb := createUser(t, b.repos, "celeb")
+
+		articles := map[string]*model.Article{}
+		for _, slug := range []string{"one", "two", "three", "four", "five"} {
+			articles[slug] = createArticle(t, b.repos, slug, jake.ID)
+		}
+		for _, like := range []struct {
+			userID int
+			slug   string
+		}{{jake.ID, "three"}, {celeb.ID, "three"}, {celeb.ID, "one"}} {
+			if err := b.repos.Articles.FavoriteArticle(ctx, like.userID, articles[like.slug].ID); err != nil {
+				t.Fatalf("FavoriteArticle() error = %v", err)
+			}
+		}
+		for _, slug := range []string{"two", "two", "four"} {
+			comment := &model.Comment{Body: "Nice", AuthorID: celeb.ID, ArticleID: articles[slug].ID}
+			if err := b.repos.Comments.Create(ctx, comment); err != nil {
+				t.Fatalf("Create() comment error = %v", err)
+			}
+		}
+		if _, err := b.repos.Articles.Update(ctx, "one", map[string]interface{}{"body": "Edited"}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		tests := []struct {
+			sort repository.Sort
+			want []string
+		}{
+			{repository.SortNewest, []string{"five", "four", "three", "two", "one"}},
+			{repository.SortOldest, []string{"one", "two", "three", "four", "five"}},
+			{repository.SortMostFavorited, []string{"three", "one", "five", "four", "two"}},
+			{repository.SortRecentlyUpdated, []string{"one", "five", "four", "three", "two"}},
+			{repository.SortMostCommented, []string{"two", "four", "five", "three", "one"}},
+		}
+
+		for _, tt := range tests {
+			all, count, err := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 10, Sort: tt.sort}, repository.ArticleFilter{})
+			if err != nil || count != 5 || !reflect.DeepEqual(slugs(all), tt.want) {
+				t.Errorf("%s: GetArticles() = %v, %d, %v; want %v", tt.sort, slugs(all), count, err, tt.want)
+				continue
+			}
+
+			// Cursors walk the same order two at a time, both ways
+			var forward []string
+			page := repository.Page{Limit: 2, Sort: tt.sort}
+			for {
+				articles, _, err := b.repos.Articles.GetArticles(ctx, page, repository.ArticleFilter{})
+				if err != nil {
+					t.Fatalf("%s: GetArticles() error = %v", tt.sort, err)
+				}
+				if len(articles) == 0 {
+					break
+				}
+				forward = append(forward, slugs(articles)...)
+				page.Cursor = repository.CursorFor(articles[len(articles)-1], tt.sort, false)
+			}
+
+			var backward []string
+			page = repository.Page{Limit: 2, Sort: tt.sort, Cursor: repository.CursorFor(all[len(all)-1], tt.sort, true)}
+			for {
+				articles, _, err := b.repos.Articles.GetArticles(ctx, page, repository.ArticleFilter{})
+				if err != nil {
+					t.Fatalf("%s: GetArticles() error = %v", tt.sort, err)
+				}
+				if len(articles) == 0 {
+					break
+				}
+				backward = append(slugs(articles), backward...)
+				page.Cursor = repository.CursorFor(articles[0], tt.sort, true)
+			}
+			backward = append(backward, tt.want[len(tt.want)-1])
+
+			if !reflect.DeepEqual(forward, tt.want) || !reflect.DeepEqual(backward, tt.want) {
+				t.Errorf("%s: pages forward = %v, backward = %v; want %v", tt.sort, forward, backward, tt.want)
+			}
+		}
+	})
+}
+
+func TestDraftsAndScheduling(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		reader := createUser(t, b.repos, "reader")
+		if err := b.repos.Users.FollowUser(ctx, reader.ID, jake.ID); err != nil {
+			t.Fatalf("FollowUser() error = %v", err)
+		}
+
+		createArticle(t, b.repos, "live", jake.ID, "go")
+		now := time.Now()
+		soon, later := now.Add(time.Hour), now.Add(2*time.Hour)
+		for _, article := range []*model.Article{
+			{Slug: "draft", Status: model.ArticleDraft},
+			{Slug: "soon", Status: model.ArticleScheduled, PublishAt: &soon},
+			{Slug: "later", Status: model.ArticleScheduled, PublishAt: &later},
+		} {
+			article.Title, article.Description, article.Body, article.AuthorID = "Dragons", "d", "dragons", jake.ID
+			if err := b.repos.Articles.Create(ctx, article); err != nil {
+				t.Fatalf("Create(%s) error = %v", article.Slug, err)
+			}
+			if err := b.repos.Tags.CreateTagsForArticle(ctx, article.ID, []string{"secret"}); err != nil {
+				t.Fatalf("CreateTagsForArticle() error = %v", err)
+			}
+		}
+
+		live, _ := b.repos.Articles.GetBySlug(ctx, "live")
+		if live.Status != model.ArticlePublished || live.PublishAt == nil || !live.PublishAt.Equal(live.CreatedAt) {
+			t.Errorf("GetBySlug(live) = %s at %v, want published at creation", live.Status, live.PublishAt)
+		}
+		if draft, _ := b.repos.Articles.GetBySlug(ctx, "draft"); draft.Status != model.ArticleDraft || draft.PublishAt != nil {
+			t.Errorf("GetBySlug(draft) = %s at %v, want an unscheduled draft", draft.Status, draft.PublishAt)
+		}
+
+		// Only published articles are listed, searched and counted
+		if articles, count, _ := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 10}, repository.ArticleFilter{}); count != 1 || !reflect.DeepEqual(slugs(articles), []string{"live"}) {
+			t.Errorf("GetArticles() = %v, %d; want [live]", slugs(articles), count)
+		}
+		if feed, count, _ := b.repos.Articles.GetFeedArticles(ctx, repository.Page{Limit: 10}, reader.ID); count != 1 || !reflect.DeepEqual(slugs(feed), []string{"live"}) {
+			t.Errorf("GetFeedArticles() = %v, %d; want [live]", slugs(feed), count)
+		}
+		if results, count, _ := b.repos.Articles.SearchArticles(ctx, "dragons", 10, 0); count != 0 || len(results) != 0 {
+			t.Errorf("SearchArticles() = %d results of %d, want none", len(results), count)
+		}
+		if tags, _ := b.repos.Tags.GetAllTags(ctx); !reflect.DeepEqual(tags, []string{"go"}) {
+			t.Errorf("GetAllTags() = %v, want [go]", tags)
+		}
+		if tags, _ := b.repos.Tags.GetPopularTags(ctx, 10); !reflect.DeepEqual(tags, []string{"go"}) {
+			t.Errorf("GetPopularTags() = %v, want [go]", tags)
+		}
+		if count, _ := b.repos.Tags.GetArticleCountByTag(ctx, "secret"); count != 0 {
+			t.Errorf("GetArticleCountByTag(secret) = %d, want 0", count)
+		}
+
+		unpublished := repository.ArticleFilter{AuthorID: jake.ID, Unpublished: true}
+		if articles, count, _ := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 10}, unpublished); count != 3 || !reflect.DeepEqual(slugs(articles), []string{"later", "soon", "draft"}) {
+			t.Errorf("GetArticles(unpublished) = %v, %d; want [later soon draft]", slugs(articles), count)
+		}
+		if articles, count, _ := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 10}, repository.ArticleFilter{AuthorID: reader.ID, Unpublished: true}); count != 0 || len(articles) != 0 {
+			t.Errorf("GetArticles(other author's unpublished) = %v, %d; want none", slugs(articles), count)
+		}
+
+		if next, err := b.repos.Articles.NextScheduledAt(ctx); err != nil || !next.Equal(soon) {
+			t.Errorf("NextScheduledAt() = %v, %v; want %v", next, err, soon)
+		}
+		if published, err := b.repos.Articles.PublishScheduled(ctx, now); err != nil || published != 0 {
+			t.Errorf("PublishScheduled(now) = %d, %v; want 0", published, err)
+		}
+		if published, err := b.repos.Articles.PublishScheduled(ctx, soon.Add(time.Minute)); err != nil || published != 1 {
+			t.Errorf("PublishScheduled(soon) = %d, %v; want 1", published, err)
+		}
+		if articles, _, _ := b.repos.Articles.GetArticles(ctx, repository.Page{Limit: 10}, repository.ArticleFilter{}); !reflect.DeepEqual(slugs(articles), []string{"soon", "live"}) {
+			t.Errorf("GetArticles() after publishing = %v, want [soon live]", slugs(articles))
+		}
+		if next, err := b.repos.Articles.NextScheduledAt(ctx); err != nil || !next.Equal(later) {
+			t.Errorf("NextScheduledAt() = %v, %v; want %v", next, err, later)
+		}
+
+		if published, _ := b.repos.Articles.PublishScheduled(ctx, later); published != 1 {
+			t.Errorf("PublishScheduled(later) = %d, want 1", published)
+		}
+		if next, err := b.repos.Articles.NextScheduledAt(ctx); err != nil || !next.IsZero() {
+			t.Errorf("NextScheduledAt() with nothing scheduled = %v, %v; want zero", next, err)
+		}
+
+		_, err := b.repos.Articles.Update(ctx, "draft", map[string]interface{}{"status": "archived"})
+		wantErr(t, "Update() unknown status", err, "CHECK constraint failed")
+	})
+}
+
+func TestArticleRevisions(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		dragons := createArticle(t, b.repos, "dragons", jake.ID)
+		wyverns := createArticle(t, b.repos, "wyverns", jake.ID)
+
+		for i, tags := range [][]string{{"dragons", "go"}, nil} {
+			revision := &model.ArticleRevision{ArticleID: dragons.ID, EditorID: jake.ID, Title: fmt.Sprintf("Title %d", i+1), Description: "d", Body: "b", TagList: tags}
+			if err := b.repos.Articles.CreateRevision(ctx, revision); err != nil {
+				t.Fatalf("CreateRevision() error = %v", err)
+			}
+			if revision.Revision != i+1 || revision.ID == 0 || revision.CreatedAt.IsZero() {
+				t.Errorf("CreateRevision() = %+v, want revision %d", revision, i+1)
+			}
+		}
+		// Each article numbers its own revisions
+		other := &model.ArticleRevision{ArticleID: wyverns.ID, EditorID: jake.ID, Title: "t", Description: "d", Body: "b"}
+		if err := b.repos.Articles.CreateRevision(ctx, other); err != nil || other.Revision != 1 {
+			t.Errorf("CreateRevision() of another article = %d, %v; want revision 1", other.Revision, err)
+		}
+
+		revisions, err := b.repos.Articles.GetRevisions(ctx, dragons.ID)
+		if err != nil || len(revisions) != 2 {
+			t.Fatalf("GetRevisions() = %v, %v; want 2 revisions", revisions, err)
+		}
+		if revisions[0].Revision != 2 || revisions[1].Revision != 1 {
+			t.Errorf("GetRevisions() order = %d, %d; want newest first", revisions[0].Revision, revisions[1].Revision)
+		}
+		if !reflect.DeepEqual(revisions[1].TagList, []string{"dragons", "go"}) || !reflect.DeepEqual(revisions[0].TagList, []string{}) {
+			t.Errorf("GetRevisions() tags = %v, %v; want [dragons go] and []", revisions[1].TagList, revisions[0].TagList)
+		}
+
+		revision, err := b.repos.Articles.GetRevision(ctx, dragons.ID, 1)
+		if err != nil || revision.Title != "Title 1" || revision.EditorID != jake.ID {
+			t.Errorf("GetRevision(1) = %+v, %v; want Title 1 by jake", revision, err)
+		}
+		_, err = b.repos.Articles.GetRevision(ctx, dragons.ID, 3)
+		wantErr(t, "GetRevision() unknown", err, "revision not found")
+		err = b.repos.Articles.CreateRevision(ctx, &model.ArticleRevision{ArticleID: 999, EditorID: jake.ID})
+		wantErr(t, "CreateRevision() unknown article", err, "FOREIGN KEY constraint failed")
+
+		if err := b.repos.Articles.Delete(ctx, "dragons"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if revisions, err := b.repos.Articles.GetRevisions(ctx, dragons.ID); err != nil || len(revisions) != 0 {
+			t.Errorf("GetRevisions() after delete = %v, %v; want none", revisions, err)
+		}
+		if revisions, _ := b.repos.Articles.GetRevisions(ctx, wyverns.ID); len(revisions) != 1 {
+			t.Errorf("GetRevisions() of another article after delete = %d, want 1", len(revisions))
+		}
+	})
+}
+
+func TestArticleRenders(t *testing.T) {
+	ctx := context.Background()
+	forEachBackend(t, func(t *testing.T, b backend) {
+		jake := createUser(t, b.repos, "jake")
+		dragons := createArticle(t, b.repos, "dragons", jake.ID)
+		wyverns := createArticle(t, b.repos, "wyverns", jake.ID)
+		if dragons.Revision != 1 {
+			t.Errorf("Create() revision = %d, want 1", dragons.Revision)
+		}
+
+		// Every update makes a new version, even one changing no columns
+		updated, err := b.repos.Articles.Update(ctx, "dragons", map[string]interface{}{"body": "Fire"})
+		if err != nil || updated.Revision != 2 {
+			t.Fatalf("Update() = %+v, %v; want revision 2", updated, err)
+		}
+		if updated, err := b.repos.Articles.Update(ctx, "wyverns", map[string]interface{}{}); err != nil || updated.Revision != 2 {
+			t.Errorf("Update() without changes = %+v, %v; want revision 2", updated, err)
+		}
+
+		for _, render := range []*model.ArticleRender{
+			{ArticleID: dragons.ID, Revision: 1, Renderer: 1, BodyHTML: "<p>old</p>", Excerpt: "old"},
+			{ArticleID: dragons.ID, Revision: 2, Renderer: 1, BodyHTML: "<p>Fire</p>", Excerpt: "Fire"},
+			{ArticleID: wyverns.ID, Revision: 2, Renderer: 0, BodyHTML: "<p>stale</p>", Excerpt: "stale"},
+		} {
+			if err := b.repos.Articles.SaveRender(ctx, render); err != nil {
+				t.Fatalf("SaveRender() error = %v", err)
+			}
+		}
+
+		// Only renders of the current revision by the renderer are found
+		renders, err := b.repos.Articles.GetRenders(ctx, 1, []int{dragons.ID, wyverns.ID, 999})
+		if err != nil || len(renders) != 1 || renders[dragons.ID].Revision != 2 || renders[dragons.ID].BodyHTML != "<p>Fire</p>" {
+			t.Errorf("GetRenders() = %+v, %v; want revision 2 of dragons", renders, err)
+		}
+		if renders, err := b.repos.Articles.GetRenders(ctx, 1, nil); err != nil || len(renders) != 0 {
+			t.Errorf("GetRenders() of no articles = %v, %v; want none", renders, err)
+		}
+
+		// Saving again replaces the render of another renderer
+		render := &model.ArticleRender{ArticleID: wyverns.ID, Revision: 2, Renderer: 1, BodyHTML: "<p>Wings</p>", Excerpt: "Wings"}
+		if err := b.repos.Articles.SaveRender(ctx, render); err != nil {
+			t.Fatalf("SaveRender() again error = %v", err)
+		}
+		if renders, _ := b.repos.Articles.GetRenders(ctx, 1, []int{wyverns.ID}); renders[wyverns.ID].Excerpt != "Wings" {
+			t.Errorf("GetRenders() after replacing = %+v, want Wings", renders)
+		}
+
+		err = b.repos.Articles.SaveRender(ctx, &model.ArticleRender{ArticleID: 999, Revision: 1, Renderer: 1})
+		wantErr(t, "SaveRender() unknown article", err, "FOREIGN KEY constraint failed")
+	})
+}
@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// TagRepository stores tags and their links to articles in memory
+type TagRepository struct {
+	conn
+}
+
+// GetPopularTags retrieves popular tags ordered by the number of published
+// articles using them
+func (r *TagRepository) GetPopularTags(ctx context.Context, limit int) ([]string, error) {
+	var tags []string
+	err := r.read(ctx, func(s *state) error {
+		counts := s.publishedTagCounts()
+
+		for name := range counts {
+			tags = append(tags, name)
+		}
+		sort.Slice(tags, func(i, j int) bool {
+			if counts[tags[i]] != counts[tags[j]] {
+				return counts[tags[i]] > counts[tags[j]]
+			}
+			return tags[i] < tags[j]
+		})
+
+		if limit >= 0 && limit < len(tags) {
+			tags = tags[:limit]
+		}
+		return nil
+	})
+	return tags, err
+}
+
+// GetAllTags retrieves all unique tags of published articles alphabetically
+func (r *TagRepository) GetAllTags(ctx context.Context) ([]string, error) {
+	var tags []string
+	err := r.read(ctx, func(s *state) error {
+		for name := range s.publishedTagCounts() {
+			tags = append(tags, name)
+		}
+		sort.Strings(tags)
+		return nil
+	})
+	return tags, err
+}
+
+// CreateTagsForArticle creates tags and associates them with an article
+func (r *TagRepository) CreateTagsForArticle(ctx context.Context, articleID int, tagNames []string) error {
+	if len(tagNames) == 0 {
+		return nil
+	}
+
+	return r.write(ctx, func(s *state) error {
+		for _, tagName := range tagNames {
+			// Link article to tag (ignore if already exists)
+			if err := s.linkTag(articleID, tagName, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateTagsForArticle updates tags for an article (replaces existing tags)
+func (r *TagRepository) UpdateTagsForArticle(ctx context.Context, articleID int, tagNames []string) error {
+	return r.write(ctx, func(s *state) error {
+		return s.replaceArticleTags(articleID, tagNames)
+	})
+}
+
+// GetTagsForArticle retrieves all tags for a specific article
+func (r *TagRepository) GetTagsForArticle(ctx context.Context, articleID int) ([]string, error) {
+	var tags []string
+	err := r.read(ctx, func(s *state) error {
+		tags = s.articleTags(articleID)
+		return nil
+	})
+	return tags, err
+}
+
+// GetTagsForArticles retrieves the tags of several articles at once, keyed
+// by article ID
+func (r *TagRepository) GetTagsForArticles(ctx context.Context, articleIDs []int) (map[int][]string, error) {
+	tags := make(map[int][]string, len(articleIDs))
+	err := r.read(ctx, func(s *state) error {
+		for _, id := range articleIDs {
+			if articleTags := s.articleTags(id); len(articleTags) > 0 {
+				tags[id] = articleTags
+			}
+		}
+		return nil
+	})
+	return tags, err
+}
+
+// GetArticleCountByTag gets the number of published articles for a specific tag
+func (r *TagRepository) GetArticleCountByTag(ctx context.Context, tagName string) (int, error) {
+	var count int
+	err := r.read(ctx, func(s *state) error {
+		count = s.publishedTagCounts()[tagName]
+		return nil
+	})
+	return count, err
+}
+
+// DeleteUnusedTags removes tags that are not associated with any articles
+func (r *TagRepository) DeleteUnusedTags(ctx context.Context) error {
+	return r.write(ctx, func(s *state) error {
+		used := map[int]bool{}
+		for key := range s.tagLinks {
+			used[key[1]] = true
+		}
+		for id := range s.tags {
+			if !used[id] {
+				delete(s.tags, id)
+			}
+		}
+		return nil
+	})
+}
+
+// TagExists checks if a tag exists by name
+func (r *TagRepository) TagExists(ctx context.Context, tagName string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func(s *state) error {
+		_, exists = s.tagID(tagName)
+		return nil
+	})
+	return exists, err
+}
+
+// publishedTagCounts counts the published articles of each tag, leaving
+// out tags without any
+func (s *state) publishedTagCounts() map[string]int {
+	counts := map[string]int{}
+	for key := range s.tagLinks {
+		if s.articles[key[0]].Status == model.ArticlePublished {
+			counts[s.tags[key[1]]]++
+		}
+	}
+	return counts
+}
+
+// articleTags lists the tag names of an article alphabetically
+func (s *state) articleTags(articleID int) []string {
+	var tags []string
+	for key := range s.tagLinks {
+		if key[0] == articleID {
+			tags = append(tags, s.tags[key[1]])
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// replaceArticleTags replaces the tags of an article
+func (s *state) replaceArticleTags(articleID int, tagNames []string) error {
+	for key := range s.tagLinks {
+		if key[0] == articleID {
+			delete(s.tagLinks, key)
+		}
+	}
+
+	for _, tagName := range tagNames {
+		if err := s.linkTag(articleID, tagName, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkTag links an article to a tag, creating the tag if needed. A link
+// that already exists is a unique violation unless ignoreExisting is set.
+func (s *state) linkTag(articleID int, tagName string, ignoreExisting bool) error {
+	tagID, ok := s.tagID(tagName)
+	if !ok {
+		tagID = s.nextID("tags")
+		s.tags[tagID] = tagName
+	}
+
+	key := pair{articleID, tagID}
+	if _, linked := s.tagLinks[key]; linked {
+		if ignoreExisting {
+			return nil
+		}
+		return fmt.Errorf("failed to link article to tag %s: %w", tagName,
+			uniqueViolation("article_tags.article_id, article_tags.tag_id"))
+	}
+	if _, ok := s.articles[articleID]; !ok {
+		return fmt.Errorf("failed to link article to tag %s: %w", tagName, errForeignKey)
+	}
+
+	s.tagLinks[key] = s.articles[articleID].CreatedAt
+	return nil
+}
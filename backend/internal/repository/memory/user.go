@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// UserRepository stores users and follow relationships in memory
+type UserRepository struct {
+	conn
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
+	var user *model.User
+	err := r.read(ctx, func(s *state) error {
+		found, ok := s.users[id]
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+		user = &found
+		return nil
+	})
+	return user, err
+}
+
+// GetByIDs retrieves the users with the given IDs, keyed by ID
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []int) (map[int]*model.User, error) {
+	users := make(map[int]*model.User, len(ids))
+	err := r.read(ctx, func(s *state) error {
+		for _, id := range ids {
+			if found, ok := s.users[id]; ok {
+				users[id] = &found
+			}
+		}
+		return nil
+	})
+	return users, err
+}
+
+// GetByEmail retrieves a user by email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user *model.User
+	err := r.read(ctx, func(s *state) error {
+		for _, found := range s.users {
+			if found.Email == email {
+				user = &found
+				return nil
+			}
+		}
+		return fmt.Errorf("user not found")
+	})
+	return user, err
+}
+
+// GetByUsername retrieves a user by username
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	var user *model.User
+	err := r.read(ctx, func(s *state) error {
+		found, ok := s.userByUsername(username)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+		user = &found
+		return nil
+	})
+	return user, err
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+	return r.write(ctx, func(s *state) error {
+		if err := checkUserUnique(s, user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		stored := *user
+		stored.ID = s.nextID("users")
+		stored.CreatedAt = time.Now()
+		stored.UpdatedAt = stored.CreatedAt
+		s.users[stored.ID] = stored
+
+		user.ID = stored.ID
+		return nil
+	})
+}
+
+// Update updates an existing user
+func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
+	return r.write(ctx, func(s *state) error {
+		stored, ok := s.users[user.ID]
+		if !ok {
+			return nil
+		}
+
+		if err := checkUserUnique(s, user); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		stored.Email = user.Email
+		stored.Username = user.Username
+		stored.PasswordHash = user.PasswordHash
+		stored.Bio = user.Bio
+		stored.Image = user.Image
+		stored.UpdatedAt = time.Now()
+		s.users[user.ID] = stored
+		return nil
+	})
+}
+
+// checkUserUnique enforces the unique email and username columns
+func checkUserUnique(s *state, user *model.User) error {
+	for id, other := range s.users {
+		if id == user.ID {
+			continue
+		}
+		if other.Email == user.Email {
+			return uniqueViolation("users.email")
+		}
+		if other.Username == user.Username {
+			return uniqueViolation("users.username")
+		}
+	}
+	return nil
+}
+
+// EmailExists checks if an email is already taken
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func(s *state) error {
+		for _, user := range s.users {
+			if user.Email == email {
+				exists = true
+			}
+		}
+		return nil
+	})
+	return exists, err
+}
+
+// UsernameExists checks if a username is already taken
+func (r *UserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func(s *state) error {
+		_, exists = s.userByUsername(username)
+		return nil
+	})
+	return exists, err
+}
+
+// FollowUser creates a follow relationship
+func (r *UserRepository) FollowUser(ctx context.Context, followerID, followedID int) error {
+	return r.write(ctx, func(s *state) error {
+		key := pair{followerID, followedID}
+		_, followerExists := s.users[followerID]
+		_, followedExists := s.users[followedID]
+		_, alreadyFollowing := s.follows[key]
+
+		switch {
+		case followerID == followedID:
+			return fmt.Errorf("failed to follow user: CHECK constraint failed: follower_id != followed_id")
+		case !followerExists || !followedExists:
+			return fmt.Errorf("failed to follow user: %w", errForeignKey)
+		case alreadyFollowing:
+			return fmt.Errorf("failed to follow user: %w", uniqueViolation("follows.follower_id, follows.followed_id"))
+		}
+
+		s.follows[key] = time.Now()
+		return nil
+	})
+}
+
+// UnfollowUser removes a follow relationship
+func (r *UserRepository) UnfollowUser(ctx context.Context, followerID, followedID int) error {
+	return r.write(ctx, func(s *state) error {
+		key := pair{followerID, followedID}
+		if _, ok := s.follows[key]; !ok {
+			return fmt.Errorf("follow relationship not found")
+		}
+
+		delete(s.follows, key)
+		return nil
+	})
+}
+
+// IsFollowing checks if a user is following another user
+func (r *UserRepository) IsFollowing(ctx context.Context, followerID, followedID int) (bool, error) {
+	var following bool
+	err := r.read(ctx, func(s *state) error {
+		_, following = s.follows[pair{followerID, followedID}]
+		return nil
+	})
+	return following, err
+}
+
+// GetFollowedIDs reports which of the given users a user follows
+func (r *UserRepository) GetFollowedIDs(ctx context.Context, followerID int, userIDs []int) (map[int]bool, error) {
+	followed := map[int]bool{}
+	err := r.read(ctx, func(s *state) error {
+		for _, id := range userIDs {
+			if _, ok := s.follows[pair{followerID, id}]; ok {
+				followed[id] = true
+			}
+		}
+		return nil
+	})
+	return followed, err
+}
+
+// GetProfileByUsername gets a user profile by username with follow status
+func (r *UserRepository) GetProfileByUsername(ctx context.Context, username string, currentUserID *int) (*model.ProfileResponse, error) {
+	var profile *model.ProfileResponse
+	err := r.read(ctx, func(s *state) error {
+		user, ok := s.userByUsername(username)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		profile = &model.ProfileResponse{
+			Username: user.Username,
+			Bio:      user.Bio,
+			Image:    user.Image,
+		}
+		if currentUserID != nil {
+			_, profile.Following = s.follows[pair{*currentUserID, user.ID}]
+		}
+		return nil
+	})
+	return profile, err
+}
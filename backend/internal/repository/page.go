@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// Sort is an order article lists can be read in
+type Sort string
+
+// Supported sorts
+const (
+	SortNewest          Sort = "newest"
+	SortOldest          Sort = "oldest"
+	SortMostFavorited   Sort = "most_favorited"
+	SortRecentlyUpdated Sort = "recently_updated"
+	SortMostCommented   Sort = "most_commented"
+)
+
+// commentsCountExpr counts the comments of article a
+const commentsCountExpr = "(SELECT COUNT(*) FROM comments c WHERE c.article_id = a.id)"
+
+// sortOrder is the SQL behind a sort: its key, and whether larger keys
+// come first. Articles with equal keys are ordered by ID in the same
+// direction.
+type sortOrder struct {
+	key        string
+	descending bool
+}
+
+// sortOrders is the whitelist of sorts
+var sortOrders = map[Sort]sortOrder{
+	SortNewest:          {"a.created_at", true},
+	SortOldest:          {"a.created_at", false},
+	SortMostFavorited:   {"a.favorites_count", true},
+	SortRecentlyUpdated: {"a.updated_at", true},
+	SortMostCommented:   {commentsCountExpr, true},
+}
+
+// Valid reports whether s is a supported sort
+func (s Sort) Valid() bool {
+	_, ok := sortOrders[s]
+	return ok
+}
+
+// Page selects a slice of an article list in the order of Sort (newest
+// first when empty): Limit articles starting at Offset or, when Cursor is
+// set, the Limit articles next to the cursor
+type Page struct {
+	Limit  int
+	Offset int
+	Sort   Sort
+	Cursor *Cursor
+}
+
+// sort returns the order of the page
+func (p Page) sort() sortOrder {
+	if order, ok := sortOrders[p.Sort]; ok {
+		return order
+	}
+	return sortOrders[SortNewest]
+}
+
+// Cursor is a position in an article list, between the article it was
+// taken from and its neighbours. The position is the article's sort key and
+// ID, so it stays put when articles are published while a reader pages
+// through.
+type Cursor struct {
+	Sort   Sort
+	Time   time.Time // key of the newest, oldest and recently updated sorts
+	Count  int       // key of the most favorited and most commented sorts
+	ID     int
+	Before bool // the page holds the articles before the position rather than after it
+}
+
+// CursorFor returns the cursor of article in a list read in sort, for the
+// articles after it or, with before set, the articles before it
+func CursorFor(article model.Article, sort Sort, before bool) *Cursor {
+	cursor := &Cursor{Sort: sort, ID: article.ID, Before: before}
+	switch sort {
+	case SortMostFavorited:
+		cursor.Count = article.FavoritesCount
+	case SortMostCommented:
+		cursor.Count = article.CommentsCount
+	case SortRecentlyUpdated:
+		cursor.Time = article.UpdatedAt
+	default:
+		cursor.Time = article.CreatedAt
+	}
+	return cursor
+}
+
+// key returns the sort key the cursor holds
+func (c *Cursor) key() interface{} {
+	if c.Sort == SortMostFavorited || c.Sort == SortMostCommented {
+		return c.Count
+	}
+	return c.Time
+}
+
+// pageClauses returns the condition a page adds to a list query on
+// articles a, its ORDER BY and LIMIT clauses, and their arguments. Pages
+// before a cursor are read in reverse and must be put back in order with
+// reverseArticles.
+func pageClauses(page Page) (condition, orderBy string, conditionArgs, limitArgs []interface{}) {
+	order := page.sort()
+	limitArgs = []interface{}{page.Limit, page.Offset}
+
+	// Reading before a cursor walks the order backwards from it
+	descending := order.descending
+	if page.Cursor != nil && page.Cursor.Before {
+		descending = !descending
+	}
+	direction, comparison := "ASC", ">"
+	if descending {
+		direction, comparison = "DESC", "<"
+	}
+	orderBy = "ORDER BY " + order.key + " " + direction + ", a.id " + direction
+
+	if page.Cursor == nil {
+		return "", orderBy, nil, limitArgs
+	}
+
+	// The cursor replaces the offset
+	limitArgs[1] = 0
+	condition = "(" + order.key + " " + comparison + " ? OR (" + order.key + " = ? AND a.id " + comparison + " ?))"
+	key := page.Cursor.key()
+	conditionArgs = []interface{}{key, key, page.Cursor.ID}
+	return condition, orderBy, conditionArgs, limitArgs
+}
+
+// selectsCommentsCount reports whether a list query must select the
+// comments count of each article, for the cursors of the most commented
+// sort
+func (p Page) selectsCommentsCount() bool {
+	return p.Sort == SortMostCommented
+}
+
+// reverseArticles puts a page read before a cursor back into order
+func reverseArticles(page Page, articles []model.Article) {
+	if page.Cursor == nil || !page.Cursor.Before {
+		return
+	}
+	for i, j := 0, len(articles)-1; i < j; i, j = i+1, j-1 {
+		articles[i], articles[j] = articles[j], articles[i]
+	}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// articleSlugs lists the slugs of articles in order
+func articleSlugs(articles []model.Article) []string {
+	result := []string{}
+	for _, article := range articles {
+		result = append(result, article.Slug)
+	}
+	return result
+}
+
+// TestKeysetPaginationUnderInserts pages through the list and the feed
+// while other writers publish, which shifts offset pages but must not move
+// cursor pages
+func TestKeysetPaginationUnderInserts(t *testing.T) {
+	ctx := context.Background()
+
+	lists := []struct {
+		name string
+		get  func(repo *ArticleRepository, page Page, readerID int) ([]model.Article, int, error)
+	}{
+		{"list", func(repo *ArticleRepository, page Page, readerID int) ([]model.Article, int, error) {
+			return repo.GetArticles(ctx, page, ArticleFilter{})
+		}},
+		{"feed", func(repo *ArticleRepository, page Page, readerID int) ([]model.Article, int, error) {
+			return repo.GetFeedArticles(ctx, page, readerID)
+		}},
+	}
+
+	for _, list := range lists {
+		t.Run(list.name, func(t *testing.T) {
+			forEachDialect(t, func(t *testing.T, database *db.Database) {
+				userRepo := NewUserRepository(database)
+				articleRepo := NewArticleRepository(database)
+				tagRepo := NewTagRepository(database)
+
+				author := createTestUser(t, userRepo, "author")
+				reader := createTestUser(t, userRepo, "reader")
+				if err := userRepo.FollowUser(ctx, reader.ID, author.ID); err != nil {
+					t.Fatalf("FollowUser() error = %v", err)
+				}
+				for i := 1; i <= 6; i++ {
+					createTestArticle(t, articleRepo, tagRepo, fmt.Sprintf("a%d", i), author.ID)
+				}
+
+				// Three articles share a timestamp, so the ID has to break the tie
+				tie := time.Now().Add(-time.Hour)
+				if _, err := database.DB.ExecContext(ctx, "UPDATE articles SET created_at = ? WHERE slug IN ('a2', 'a3', 'a4')", tie); err != nil {
+					t.Fatalf("Failed to tie timestamps: %v", err)
+				}
+				if _, err := database.DB.ExecContext(ctx, "UPDATE articles SET created_at = ? WHERE slug = 'a1'", tie.Add(-time.Minute)); err != nil {
+					t.Fatalf("Failed to age a1: %v", err)
+				}
+
+				get := func(page Page) []model.Article {
+					t.Helper()
+					articles, _, err := list.get(articleRepo, page, reader.ID)
+					if err != nil {
+						t.Fatalf("error = %v", err)
+					}
+					return articles
+				}
+				publish := func(slug string) error {
+					article := &model.Article{Slug: slug, Title: "New", Description: "New", Body: "New", AuthorID: author.ID}
+					return articleRepo.Create(ctx, article)
+				}
+
+				articles := get(Page{Limit: 2})
+				got := articleSlugs(articles)
+
+				var wg sync.WaitGroup
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 10; i++ {
+						if err := publish(fmt.Sprintf("concurrent-%d", i)); err != nil {
+							t.Errorf("Create() error = %v", err)
+						}
+					}
+				}()
+
+				for len(articles) > 0 {
+					// Publishing between pages must not shift the next one
+					if err := publish(fmt.Sprintf("between-%d", len(got))); err != nil {
+						t.Fatalf("Create() error = %v", err)
+					}
+					articles = get(Page{Limit: 2, Cursor: CursorFor(articles[len(articles)-1], SortNewest, false)})
+					got = append(got, articleSlugs(articles)...)
+				}
+				wg.Wait()
+
+				if want := []string{"a6", "a5", "a4", "a3", "a2", "a1"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("pages = %v, want %v exactly once each", got, want)
+				}
+
+				// Paging back from the oldest article walks the same order
+				oldest, err := articleRepo.GetBySlug(ctx, "a1")
+				if err != nil {
+					t.Fatalf("GetBySlug() error = %v", err)
+				}
+				back := get(Page{Limit: 2, Cursor: CursorFor(*oldest, SortNewest, true)})
+				if !reflect.DeepEqual(articleSlugs(back), []string{"a3", "a2"}) {
+					t.Fatalf("page before a1 = %v, want [a3 a2]", articleSlugs(back))
+				}
+				back = get(Page{Limit: 2, Cursor: CursorFor(back[0], SortNewest, true)})
+				if !reflect.DeepEqual(articleSlugs(back), []string{"a5", "a4"}) {
+					t.Errorf("page before a3 = %v, want [a5 a4]", articleSlugs(back))
+				}
+			})
+		})
+	}
+}
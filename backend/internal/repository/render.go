@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// GetRenders retrieves the cached renders of the current revisions of
+// articles made by the given renderer, keyed by article ID. Articles
+// without one are left out.
+func (r *ArticleRepository) GetRenders(ctx context.Context, renderer int, articleIDs []int) (map[int]model.ArticleRender, error) {
+	renders := map[int]model.ArticleRender{}
+	if len(articleIDs) == 0 {
+		return renders, nil
+	}
+
+	query := `
+		SELECT ar.article_id, ar.revision, ar.renderer, ar.body_html, ar.excerpt, ar.created_at
+		FROM article_renders ar
+		INNER JOIN articles a ON a.id = ar.article_id AND a.revision = ar.revision
+		WHERE ar.renderer = ? AND ar.article_id IN (` + placeholders(len(articleIDs)) + `)
+	`
+
+	args := append([]interface{}{renderer}, intArgs(articleIDs)...)
+	rows, err := r.reader.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renders: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var render model.ArticleRender
+		if err := rows.Scan(&render.ArticleID, &render.Revision, &render.Renderer, &render.BodyHTML, &render.Excerpt, &render.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan render: %w", err)
+		}
+		renders[render.ArticleID] = render
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate renders: %w", err)
+	}
+
+	return renders, nil
+}
+
+// SaveRender caches the render of a revision, replacing one made by
+// another renderer
+func (r *ArticleRepository) SaveRender(ctx context.Context, render *model.ArticleRender) error {
+	query := `
+		INSERT INTO article_renders (article_id, revision, renderer, body_html, excerpt, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (article_id, revision) DO UPDATE
+		SET renderer = excluded.renderer, body_html = excluded.body_html, excerpt = excluded.excerpt, created_at = excluded.created_at
+	`
+
+	render.CreatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query),
+		render.ArticleID, render.Revision, render.Renderer,
+		render.BodyHTML, render.Excerpt, render.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save render: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestRenderRepository(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		jake := createTestUser(t, userRepo, "jake")
+		article := createTestArticle(t, articleRepo, tagRepo, "dragons", jake.ID)
+
+		for _, excerpt := range []string{"first", "second"} {
+			render := &model.ArticleRender{ArticleID: article.ID, Revision: 1, Renderer: 1, BodyHTML: "<p>" + excerpt + "</p>", Excerpt: excerpt}
+			if err := articleRepo.SaveRender(ctx, render); err != nil {
+				t.Fatalf("SaveRender() error = %v", err)
+			}
+		}
+		renders, err := articleRepo.GetRenders(ctx, 1, []int{article.ID})
+		if err != nil || renders[article.ID].Excerpt != "second" {
+			t.Errorf("GetRenders() = %+v, %v; want the second render", renders, err)
+		}
+
+		// A new revision of the article is not rendered yet
+		if _, err := articleRepo.Update(ctx, "dragons", map[string]interface{}{"body": "Fire"}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if renders, err := articleRepo.GetRenders(ctx, 1, []int{article.ID}); err != nil || len(renders) != 0 {
+			t.Errorf("GetRenders() after update = %+v, %v; want none", renders, err)
+		}
+
+		if err := articleRepo.Delete(ctx, "dragons"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		var count int
+		if err := database.QueryRow("SELECT COUNT(*) FROM article_renders").Scan(&count); err != nil || count != 0 {
+			t.Errorf("article_renders after delete = %d, %v; want 0", count, err)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+// TestReadReplicaRouting uses two SQLite files as primary and replica and
+// gives them different contents, so each query reveals where it was routed
+func TestReadReplicaRouting(t *testing.T) {
+	ctx := context.Background()
+	for _, dialect := range testDialects {
+		t.Run(dialect.Name(), func(t *testing.T) {
+			replicaPath := filepath.Join(t.TempDir(), "replica.db")
+			primary := newTestDatabase(t, dialect)
+			replica := newTestDatabaseAt(t, replicaPath, dialect)
+
+			// Rows that only the replica has
+			replicaUsers := NewUserRepository(replica)
+			replicaArticles := NewArticleRepository(replica)
+			replicaTags := NewTagRepository(replica)
+			reader := createTestUser(t, replicaUsers, "reader")
+			replicaAuthor := createTestUser(t, replicaUsers, "replica-author")
+			createTestArticle(t, replicaArticles, replicaTags, "replica-article", replicaAuthor.ID, "replica-tag")
+			if err := replicaUsers.FollowUser(ctx, reader.ID, replicaAuthor.ID); err != nil {
+				t.Fatalf("FollowUser() error = %v", err)
+			}
+
+			// Rows that only the primary has
+			primaryUsers := NewUserRepository(primary)
+			primaryAuthor := createTestUser(t, primaryUsers, "primary-author")
+			createTestArticle(t, NewArticleRepository(primary), NewTagRepository(primary), "primary-article", primaryAuthor.ID, "primary-tag")
+
+			if err := primary.ConnectReplica(replicaPath); err != nil {
+				t.Fatalf("ConnectReplica() error = %v", err)
+			}
+
+			userRepo := NewUserRepository(primary)
+			articleRepo := NewArticleRepository(primary)
+			tagRepo := NewTagRepository(primary)
+
+			articles, _, err := articleRepo.GetArticles(ctx, Page{Limit: 10}, ArticleFilter{})
+			if err != nil || len(articles) != 1 || articles[0].Slug != "replica-article" {
+				t.Errorf("GetArticles() = %v, %v; want replica-article from the replica", articles, err)
+			}
+
+			feed, _, err := articleRepo.GetFeedArticles(ctx, Page{Limit: 10}, reader.ID)
+			if err != nil || len(feed) != 1 || feed[0].Slug != "replica-article" {
+				t.Errorf("GetFeedArticles() = %v, %v; want replica-article from the replica", feed, err)
+			}
+
+			tags, err := tagRepo.GetPopularTags(ctx, 10)
+			if err != nil || len(tags) != 1 || tags[0] != "replica-tag" {
+				t.Errorf("GetPopularTags() = %v, %v; want [replica-tag] from the replica", tags, err)
+			}
+
+			profile, err := userRepo.GetProfileByUsername(ctx, "replica-author", &reader.ID)
+			if err != nil || !profile.Following {
+				t.Errorf("GetProfileByUsername() = %+v, %v; want followed profile from the replica", profile, err)
+			}
+
+			// Writes and the reads that follow them stay on the primary
+			if _, err := articleRepo.GetBySlug(ctx, "primary-article"); err != nil {
+				t.Errorf("GetBySlug() error = %v, want primary-article from the primary", err)
+			}
+			if _, err := userRepo.GetByUsername(ctx, "replica-author"); err == nil {
+				t.Error("GetByUsername() found replica-author, want lookup on the primary")
+			}
+		})
+	}
+}
+
+func TestReaderWithoutReplica(t *testing.T) {
+	database := newTestDatabase(t, db.SQLite)
+	if database.Reader() != database.DB {
+		t.Error("Expected Reader() to fall back to the primary")
+	}
+}
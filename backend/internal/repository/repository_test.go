@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/migrations"
+)
+
+// testDialects lists the dialects every repository test runs against.
+// The postgres dialect runs on SQLite as a stand-in: SQLite accepts "$n"
+// placeholders and RETURNING, so the generated SQL is exercised end to end.
+var testDialects = []db.Dialect{db.SQLite, db.Postgres}
+
+// forEachDialect runs fn against a freshly migrated database per dialect
+func forEachDialect(t *testing.T, fn func(t *testing.T, database *db.Database)) {
+	t.Helper()
+
+	for _, dialect := range testDialects {
+		t.Run(dialect.Name(), func(t *testing.T) {
+			fn(t, newTestDatabase(t, dialect))
+		})
+	}
+}
+
+// newTestDatabase opens a temporary SQLite file using the given dialect
+func newTestDatabase(t *testing.T, dialect db.Dialect) *db.Database {
+	t.Helper()
+
+	return newTestDatabaseAt(t, filepath.Join(t.TempDir(), "test.db"), dialect)
+}
+
+// newTestDatabaseAt opens and migrates the SQLite file at path
+func newTestDatabaseAt(t *testing.T, path string, dialect db.Dialect) *db.Database {
+	t.Helper()
+
+	database, err := db.Open("sqlite3", path, dialect)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	// The schema is SQLite's regardless of the dialect under test
+	if err := db.NewMigrationManager(database.DB, db.SQLite).RunMigrations(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	return database
+}
+
+// createTestUser inserts a user with the given username
+func createTestUser(t *testing.T, repo *UserRepository, username string) *model.User {
+	ctx := context.Background()
+	t.Helper()
+
+	user := &model.User{
+		Email:        username + "@example.com",
+		Username:     username,
+		PasswordHash: "hash",
+	}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create user %s: %v", username, err)
+	}
+	return user
+}
+
+// createTestArticle inserts an article with the given slug and tags
+func createTestArticle(t *testing.T, articleRepo *ArticleRepository, tagRepo *TagRepository, slug string, authorID int, tags ...string) *model.Article {
+	ctx := context.Background()
+	t.Helper()
+
+	article := &model.Article{
+		Slug:        slug,
+		Title:       "Title " + slug,
+		Description: "Description " + slug,
+		Body:        "Body " + slug,
+		AuthorID:    authorID,
+	}
+	if err := articleRepo.Create(ctx, article); err != nil {
+		t.Fatalf("Failed to create article %s: %v", slug, err)
+	}
+	if err := tagRepo.CreateTagsForArticle(ctx, article.ID, tags); err != nil {
+		t.Fatalf("Failed to create tags for %s: %v", slug, err)
+	}
+	return article
+}
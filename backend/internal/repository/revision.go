@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// revisionColumns are the columns of article_revisions read into a
+// model.ArticleRevision, in the order of scanRevision
+const revisionColumns = "id, article_id, revision, editor_id, title, description, body, tag_list, created_at"
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRevision reads revisionColumns into a revision
+func scanRevision(row rowScanner) (*model.ArticleRevision, error) {
+	revision := &model.ArticleRevision{}
+	var tagList string
+	err := row.Scan(&revision.ID, &revision.ArticleID, &revision.Revision, &revision.EditorID,
+		&revision.Title, &revision.Description, &revision.Body, &tagList, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(tagList), &revision.TagList); err != nil {
+		return nil, fmt.Errorf("failed to decode revision tags: %w", err)
+	}
+	return revision, nil
+}
+
+// CreateRevision stores an earlier version of an article as its next
+// revision and sets the revision's number
+func (r *ArticleRepository) CreateRevision(ctx context.Context, revision *model.ArticleRevision) error {
+	tagList := revision.TagList
+	if tagList == nil {
+		tagList = []string{}
+	}
+	encoded, err := json.Marshal(tagList)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision tags: %w", err)
+	}
+
+	revision.CreatedAt = time.Now()
+
+	// Number and insert the revision in one transaction; the unique index on
+	// (article_id, revision) rejects a concurrent update numbering it too
+	return withinTx(ctx, r.db, func(tx db.Executor) error {
+		query := `SELECT COALESCE(MAX(revision), 0) + 1 FROM article_revisions WHERE article_id = ?`
+		if err := tx.QueryRowContext(ctx, r.dialect.Rebind(query), revision.ArticleID).Scan(&revision.Revision); err != nil {
+			return fmt.Errorf("failed to number revision: %w", err)
+		}
+
+		query = `
+			INSERT INTO article_revisions (article_id, revision, editor_id, title, description, body, tag_list, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		id, err := r.dialect.InsertReturningID(ctx, tx, query,
+			revision.ArticleID, revision.Revision, revision.EditorID,
+			revision.Title, revision.Description, revision.Body,
+			string(encoded), revision.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create revision: %w", err)
+		}
+
+		revision.ID = int(id)
+		return nil
+	})
+}
+
+// GetRevisions retrieves the revisions of an article, newest first
+func (r *ArticleRepository) GetRevisions(ctx context.Context, articleID int) ([]model.ArticleRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM article_revisions WHERE article_id = ? ORDER BY revision DESC`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []model.ArticleRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, *revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision retrieves one revision of an article by its number
+func (r *ArticleRepository) GetRevision(ctx context.Context, articleID, number int) (*model.ArticleRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM article_revisions WHERE article_id = ? AND revision = ?`
+
+	revision, err := scanRevision(r.db.QueryRowContext(ctx, r.dialect.Rebind(query), articleID, number))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("revision not found")
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestRevisionRepository(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		jake := createTestUser(t, userRepo, "jake")
+		article := createTestArticle(t, articleRepo, tagRepo, "dragons", jake.ID, "dragons")
+
+		for i := 1; i <= 2; i++ {
+			revision := &model.ArticleRevision{ArticleID: article.ID, EditorID: jake.ID, Title: "Dragons", Description: "d", Body: "b", TagList: []string{"dragons"}}
+			if err := articleRepo.CreateRevision(ctx, revision); err != nil {
+				t.Fatalf("CreateRevision() error = %v", err)
+			}
+			if revision.Revision != i || revision.ID != i {
+				t.Errorf("CreateRevision() = revision %d with ID %d, want %d", revision.Revision, revision.ID, i)
+			}
+		}
+
+		// Revisions join the transaction of a unit of work
+		errFailed := errors.New("failed")
+		err := NewUnitOfWork(database).Do(ctx, func(repos *Repositories) error {
+			revision := &model.ArticleRevision{ArticleID: article.ID, EditorID: jake.ID, Title: "Rolled back"}
+			if err := repos.Articles.CreateRevision(ctx, revision); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		if err != errFailed {
+			t.Fatalf("Do() error = %v, want %v", err, errFailed)
+		}
+
+		revisions, err := articleRepo.GetRevisions(ctx, article.ID)
+		if err != nil || len(revisions) != 2 {
+			t.Fatalf("GetRevisions() = %v, %v; want 2 revisions", revisions, err)
+		}
+		if revisions[0].Revision != 2 || !reflect.DeepEqual(revisions[0].TagList, []string{"dragons"}) {
+			t.Errorf("GetRevisions()[0] = %+v, want revision 2 tagged dragons", revisions[0])
+		}
+
+		if _, err := articleRepo.GetRevision(ctx, article.ID, 3); err == nil || err.Error() != "revision not found" {
+			t.Errorf("GetRevision() rolled back revision error = %v, want revision not found", err)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+const (
+	// maxSearchTerms bounds how many words of a query are searched for
+	maxSearchTerms = 16
+
+	// Snippets come back from the database with matches between these
+	// markers, so the surrounding text can be escaped before the markers
+	// become <mark> tags
+	snippetStart = "\x02"
+	snippetStop  = "\x03"
+)
+
+// searchColumnWeights weighs a match in each article_search column on
+// SQLite: title, description, body, tags
+var searchColumnWeights = []float64{4, 2, 1, 3}
+
+// SearchTerms splits a search query into lowercase words, dropping
+// punctuation and repeats. Every term must match for an article to be found.
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// SearchArticles returns the articles matching every word of query, best
+// match first, each with a highlighted snippet
+func (r *ArticleRepository) SearchArticles(ctx context.Context, query string, limit, offset int) ([]model.SearchResult, int, error) {
+	terms := SearchTerms(query)
+	if len(terms) == 0 {
+		return []model.SearchResult{}, 0, nil
+	}
+
+	if r.dialect == db.Postgres {
+		return r.searchPostgres(ctx, terms, limit, offset)
+	}
+	return r.searchSQLite(ctx, terms, limit, offset)
+}
+
+// searchSQLite ranks the FTS5 matches with bm25, whose scores are lower for
+// better matches, and fetches the page of articles
+func (r *ArticleRepository) searchSQLite(ctx context.Context, terms []string, limit, offset int) ([]model.SearchResult, int, error) {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + term + `"`
+	}
+	match := strings.Join(quoted, " ")
+
+	var totalCount int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM article_search
+		JOIN articles a ON a.id = article_search.rowid
+		WHERE article_search MATCH ? AND ` + publishedCondition
+	if err := r.reader.QueryRowContext(ctx, countQuery, match).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get search count: %w", err)
+	}
+
+	// Equal scores fall back to newest first, as the article lists do
+	weights := make([]string, len(searchColumnWeights))
+	args := []interface{}{match}
+	for i, weight := range searchColumnWeights {
+		weights[i] = "?"
+		args = append(args, weight)
+	}
+	pageQuery := `
+		SELECT ` + articleColumns + `, snippet(article_search, -1, char(2), char(3), '…', 24)
+		FROM article_search
+		JOIN articles a ON a.id = article_search.rowid
+		WHERE article_search MATCH ? AND ` + publishedCondition + `
+		ORDER BY bm25(article_search, ` + strings.Join(weights, ", ") + `), a.id DESC
+		LIMIT ? OFFSET ?
+	`
+	results, err := r.scanSearchResults(ctx, pageQuery, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalCount, nil
+}
+
+// searchPostgres ranks the tsvector matches with ts_rank_cd and highlights
+// the description and body with ts_headline
+func (r *ArticleRepository) searchPostgres(ctx context.Context, terms []string, limit, offset int) ([]model.SearchResult, int, error) {
+	text := strings.Join(terms, " ")
+
+	var totalCount int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM article_search s
+		JOIN articles a ON a.id = s.article_id
+		WHERE s.document @@ plainto_tsquery('english', ?) AND ` + publishedCondition
+	if err := r.reader.QueryRowContext(ctx, r.dialect.Rebind(countQuery), text).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get search count: %w", err)
+	}
+
+	pageQuery := `
+		SELECT ` + articleColumns + `,
+		       ts_headline('english', a.description || ' ' || a.body, q,
+		                   'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MinWords=10, MaxWords=24')
+		FROM article_search s
+		JOIN articles a ON a.id = s.article_id
+		CROSS JOIN plainto_tsquery('english', ?) q
+		WHERE s.document @@ q AND ` + publishedCondition + `
+		ORDER BY ts_rank_cd(s.document, q) DESC, a.id DESC
+		LIMIT ? OFFSET ?
+	`
+	results, err := r.scanSearchResults(ctx, r.dialect.Rebind(pageQuery), text, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalCount, nil
+}
+
+// scanSearchResults runs a query selecting the article columns followed by
+// a marked-up snippet
+func (r *ArticleRepository) scanSearchResults(ctx context.Context, query string, args ...interface{}) ([]model.SearchResult, error) {
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search articles: %w", err)
+	}
+	defer rows.Close()
+
+	results := []model.SearchResult{}
+	for rows.Next() {
+		var result model.SearchResult
+		var snippet string
+		article := &result.Article
+		err := rows.Scan(append(articleDest(article), &snippet)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		result.Snippet = highlightSnippet(snippet)
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search results: %w", err)
+	}
+
+	return results, nil
+}
+
+// highlightSnippet escapes a snippet and turns its match markers into
+// <mark> tags
+func highlightSnippet(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, snippetStart, "<mark>")
+	return strings.ReplaceAll(snippet, snippetStop, "</mark>")
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/migrations"
+)
+
+// The search queries are written per dialect, so unlike the other
+// repository tests these only run against SQLite
+
+// createSearchArticle inserts an article with the given title and body
+func createSearchArticle(t *testing.T, repo *ArticleRepository, slug, title, body string, authorID int) *model.Article {
+	t.Helper()
+
+	article := &model.Article{Slug: slug, Title: title, Description: "A story", Body: body, AuthorID: authorID}
+	if err := repo.Create(context.Background(), article); err != nil {
+		t.Fatalf("Failed to create article %s: %v", slug, err)
+	}
+	return article
+}
+
+// searchSlugs searches for query and returns the slugs found in order and
+// the total count
+func searchSlugs(t *testing.T, repo *ArticleRepository, query string, limit, offset int) ([]string, int) {
+	t.Helper()
+
+	results, total, err := repo.SearchArticles(context.Background(), query, limit, offset)
+	if err != nil {
+		t.Fatalf("SearchArticles(%q) error = %v", query, err)
+	}
+	found := []string{}
+	for _, result := range results {
+		found = append(found, result.Article.Slug)
+	}
+	return found, total
+}
+
+func TestSearchIndexFollowsWrites(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDatabase(t, db.SQLite)
+	userRepo := NewUserRepository(database)
+	articleRepo := NewArticleRepository(database)
+
+	author := createTestUser(t, userRepo, "author")
+	article := createSearchArticle(t, articleRepo, "dragons", "Training dragons", "You have to believe", author.ID)
+
+	// Porter stemming matches other forms of a word
+	if found, _ := searchSlugs(t, articleRepo, "dragon", 10, 0); !reflect.DeepEqual(found, []string{"dragons"}) {
+		t.Errorf("search after create = %v, want [dragons]", found)
+	}
+
+	if _, err := articleRepo.Update(ctx, "dragons", map[string]interface{}{"title": "Training wyverns"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if found, _ := searchSlugs(t, articleRepo, "dragon", 10, 0); len(found) != 0 {
+		t.Errorf("search for the old title = %v, want none", found)
+	}
+	if found, _ := searchSlugs(t, articleRepo, "wyvern", 10, 0); len(found) != 1 {
+		t.Errorf("search for the new title = %v, want [dragons]", found)
+	}
+
+	if err := articleRepo.SetArticleTags(ctx, article.ID, []string{"fantasy"}); err != nil {
+		t.Fatalf("SetArticleTags() error = %v", err)
+	}
+	if found, _ := searchSlugs(t, articleRepo, "fantasy", 10, 0); len(found) != 1 {
+		t.Errorf("search for a tag = %v, want [dragons]", found)
+	}
+	if err := articleRepo.SetArticleTags(ctx, article.ID, nil); err != nil {
+		t.Fatalf("SetArticleTags() error = %v", err)
+	}
+	if found, _ := searchSlugs(t, articleRepo, "fantasy", 10, 0); len(found) != 0 {
+		t.Errorf("search for a removed tag = %v, want none", found)
+	}
+
+	if err := articleRepo.Delete(ctx, "dragons"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if found, _ := searchSlugs(t, articleRepo, "wyvern", 10, 0); len(found) != 0 {
+		t.Errorf("search after delete = %v, want none", found)
+	}
+}
+
+func TestSearchRankingAndPagination(t *testing.T) {
+	database := newTestDatabase(t, db.SQLite)
+	userRepo := NewUserRepository(database)
+	articleRepo := NewArticleRepository(database)
+
+	author := createTestUser(t, userRepo, "author")
+	createSearchArticle(t, articleRepo, "in-body", "Gardening", "Some notes on a dragon fruit tree", author.ID)
+	createSearchArticle(t, articleRepo, "in-title", "Dragon fruit", "Some notes on a tree", author.ID)
+	createSearchArticle(t, articleRepo, "unrelated", "Cooking", "Some notes on a stew", author.ID)
+
+	found, total := searchSlugs(t, articleRepo, "dragon fruit", 10, 0)
+	if !reflect.DeepEqual(found, []string{"in-title", "in-body"}) || total != 2 {
+		t.Errorf("search = %v (%d), want title match before body match, 2 total", found, total)
+	}
+
+	found, total = searchSlugs(t, articleRepo, "dragon fruit", 1, 1)
+	if !reflect.DeepEqual(found, []string{"in-body"}) || total != 2 {
+		t.Errorf("second page = %v (%d), want [in-body], 2 total", found, total)
+	}
+
+	found, total = searchSlugs(t, articleRepo, "dragon fruit", 10, 5)
+	if len(found) != 0 || total != 2 {
+		t.Errorf("page past the end = %v (%d), want none, 2 total", found, total)
+	}
+
+	// Punctuation is not FTS query syntax
+	if found, total := searchSlugs(t, articleRepo, `"dragon" OR -(stew*`, 10, 0); total != 0 {
+		t.Errorf("search with syntax characters = %v, want none", found)
+	}
+	if found, total := searchSlugs(t, articleRepo, "?!", 10, 0); total != 0 || found == nil {
+		t.Errorf("search without words = %v (%d), want empty", found, total)
+	}
+}
+
+func TestSearchSnippetsAreEscaped(t *testing.T) {
+	database := newTestDatabase(t, db.SQLite)
+	userRepo := NewUserRepository(database)
+	articleRepo := NewArticleRepository(database)
+
+	author := createTestUser(t, userRepo, "author")
+	createSearchArticle(t, articleRepo, "markup", "Markup", "Never trust <script>dragon</script> input", author.ID)
+
+	results, _, err := articleRepo.SearchArticles(context.Background(), "dragon", 10, 0)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("SearchArticles() = %v, %v; want one result", results, err)
+	}
+	if want := "&lt;script&gt;<mark>dragon</mark>&lt;/script&gt;"; !strings.Contains(results[0].Snippet, want) {
+		t.Errorf("Snippet = %q, want it to contain %q", results[0].Snippet, want)
+	}
+}
+
+func TestSearchIndexBackfilled(t *testing.T) {
+	database, err := db.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), db.SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	manager := db.NewMigrationManager(database.DB, db.SQLite)
+	if err := manager.MigrateTo(migrations.FS, 10); err != nil {
+		t.Fatalf("MigrateTo(10) error = %v", err)
+	}
+
+	// The repository writes columns of later migrations, so insert directly
+	articleRepo := NewArticleRepository(database)
+	author := createTestUser(t, NewUserRepository(database), "author")
+	result, err := database.Exec(`INSERT INTO articles (slug, title, description, body, author_id)
+		VALUES ('dragons', 'Training dragons', 'Description', 'You have to believe', ?)`, author.ID)
+	if err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	articleID, _ := result.LastInsertId()
+	if err := articleRepo.SetArticleTags(context.Background(), int(articleID), []string{"fantasy"}); err != nil {
+		t.Fatalf("SetArticleTags() error = %v", err)
+	}
+
+	if err := manager.RunMigrations(migrations.FS); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	if found, _ := searchSlugs(t, articleRepo, "dragons fantasy", 10, 0); !reflect.DeepEqual(found, []string{"dragons"}) {
+		t.Errorf("search after backfill = %v, want [dragons]", found)
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Dragon training", []string{"dragon", "training"}},
+		{`"dragon" OR -(stew*`, []string{"dragon", "or", "stew"}},
+		{"dragon DRAGON dragon", []string{"dragon"}},
+		{"  ?! ", []string{}},
+		{"café 2024", []string{"café", "2024"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := SearchTerms(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchTerms(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,32 +1,40 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
 )
 
 // TagRepository handles tag database operations
 type TagRepository struct {
-	db *sql.DB
+	db      db.Executor
+	reader  db.Executor // read replica, or the primary when none is configured
+	dialect db.Dialect
 }
 
 // NewTagRepository creates a new tag repository
-func NewTagRepository(db *sql.DB) *TagRepository {
-	return &TagRepository{db: db}
+func NewTagRepository(database *db.Database) *TagRepository {
+	return &TagRepository{db: database.Primary(), reader: database.ReadOnly(), dialect: database.Dialect()}
 }
 
-// GetPopularTags retrieves popular tags ordered by usage count
-func (r *TagRepository) GetPopularTags(limit int) ([]string, error) {
+// GetPopularTags retrieves popular tags ordered by the number of published
+// articles using them
+func (r *TagRepository) GetPopularTags(ctx context.Context, limit int) ([]string, error) {
 	query := `
 		SELECT t.name
 		FROM tags t
 		INNER JOIN article_tags at ON t.id = at.tag_id
+		INNER JOIN articles a ON a.id = at.article_id
+		WHERE ` + publishedCondition + `
 		GROUP BY t.id, t.name
 		ORDER BY COUNT(at.article_id) DESC, t.name ASC
 		LIMIT ?
 	`
 
-	rows, err := r.db.Query(query, limit)
+	rows, err := r.reader.QueryContext(ctx, r.dialect.Rebind(query), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query popular tags: %w", err)
 	}
@@ -48,15 +56,17 @@ func (r *TagRepository) GetPopularTags(limit int) ([]string, error) {
 	return tags, nil
 }
 
-// GetAllTags retrieves all unique tags alphabetically
-func (r *TagRepository) GetAllTags() ([]string, error) {
+// GetAllTags retrieves all unique tags of published articles alphabetically
+func (r *TagRepository) GetAllTags(ctx context.Context) ([]string, error) {
 	query := `
-		SELECT DISTINCT name
-		FROM tags
-		ORDER BY name ASC
+		SELECT t.name
+		FROM tags t
+		WHERE EXISTS (SELECT 1 FROM article_tags at JOIN articles a ON a.id = at.article_id
+			WHERE at.tag_id = t.id AND ` + publishedCondition + `)
+		ORDER BY t.name ASC
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query all tags: %w", err)
 	}
@@ -79,74 +89,66 @@ func (r *TagRepository) GetAllTags() ([]string, error) {
 }
 
 // CreateTagsForArticle creates tags and associates them with an article
-func (r *TagRepository) CreateTagsForArticle(articleID int, tagNames []string) error {
+func (r *TagRepository) CreateTagsForArticle(ctx context.Context, articleID int, tagNames []string) error {
 	if len(tagNames) == 0 {
 		return nil
 	}
 
-	tx, err := r.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	for _, tagName := range tagNames {
-		// Get or create tag
-		tagID, err := r.getOrCreateTag(tx, tagName)
-		if err != nil {
-			return fmt.Errorf("failed to get or create tag %s: %w", tagName, err)
-		}
-
-		// Link article to tag (ignore if already exists)
-		_, err = tx.Exec(
-			"INSERT OR IGNORE INTO article_tags (article_id, tag_id) VALUES (?, ?)",
-			articleID, tagID,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to link article to tag %s: %w", tagName, err)
+	return withinTx(ctx, r.db, func(tx db.Executor) error {
+		for _, tagName := range tagNames {
+			// Get or create tag
+			tagID, err := r.getOrCreateTag(ctx, tx, tagName)
+			if err != nil {
+				return fmt.Errorf("failed to get or create tag %s: %w", tagName, err)
+			}
+
+			// Link article to tag (ignore if already exists)
+			_, err = tx.ExecContext(ctx,
+				r.dialect.Rebind("INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?) ON CONFLICT (article_id, tag_id) DO NOTHING"),
+				articleID, tagID,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to link article to tag %s: %w", tagName, err)
+			}
 		}
-	}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 // UpdateTagsForArticle updates tags for an article (replaces existing tags)
-func (r *TagRepository) UpdateTagsForArticle(articleID int, tagNames []string) error {
-	tx, err := r.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Delete existing article tags
-	_, err = tx.Exec("DELETE FROM article_tags WHERE article_id = ?", articleID)
-	if err != nil {
-		return fmt.Errorf("failed to delete existing tags: %w", err)
-	}
-
-	// Add new tags
-	for _, tagName := range tagNames {
-		// Get or create tag
-		tagID, err := r.getOrCreateTag(tx, tagName)
+func (r *TagRepository) UpdateTagsForArticle(ctx context.Context, articleID int, tagNames []string) error {
+	return withinTx(ctx, r.db, func(tx db.Executor) error {
+		// Delete existing article tags
+		_, err := tx.ExecContext(ctx, r.dialect.Rebind("DELETE FROM article_tags WHERE article_id = ?"), articleID)
 		if err != nil {
-			return fmt.Errorf("failed to get or create tag %s: %w", tagName, err)
+			return fmt.Errorf("failed to delete existing tags: %w", err)
 		}
 
-		// Link article to tag
-		_, err = tx.Exec(
-			"INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?)",
-			articleID, tagID,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to link article to tag %s: %w", tagName, err)
+		// Add new tags
+		for _, tagName := range tagNames {
+			// Get or create tag
+			tagID, err := r.getOrCreateTag(ctx, tx, tagName)
+			if err != nil {
+				return fmt.Errorf("failed to get or create tag %s: %w", tagName, err)
+			}
+
+			// Link article to tag
+			_, err = tx.ExecContext(ctx,
+				r.dialect.Rebind("INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?)"),
+				articleID, tagID,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to link article to tag %s: %w", tagName, err)
+			}
 		}
-	}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 // GetTagsForArticle retrieves all tags for a specific article
-func (r *TagRepository) GetTagsForArticle(articleID int) ([]string, error) {
+func (r *TagRepository) GetTagsForArticle(ctx context.Context, articleID int) ([]string, error) {
 	query := `
 		SELECT t.name 
 		FROM tags t
@@ -155,7 +157,7 @@ func (r *TagRepository) GetTagsForArticle(articleID int) ([]string, error) {
 		ORDER BY t.name ASC
 	`
 
-	rows, err := r.db.Query(query, articleID)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), articleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get article tags: %w", err)
 	}
@@ -177,17 +179,56 @@ func (r *TagRepository) GetTagsForArticle(articleID int) ([]string, error) {
 	return tags, nil
 }
 
-// GetArticleCountByTag gets the number of articles for a specific tag
-func (r *TagRepository) GetArticleCountByTag(tagName string) (int, error) {
+// GetTagsForArticles retrieves the tags of several articles at once, keyed
+// by article ID. Articles without tags are left out of the map.
+func (r *TagRepository) GetTagsForArticles(ctx context.Context, articleIDs []int) (map[int][]string, error) {
+	tags := make(map[int][]string, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return tags, nil
+	}
+
+	query := `
+		SELECT at.article_id, t.name
+		FROM tags t
+		INNER JOIN article_tags at ON t.id = at.tag_id
+		WHERE at.article_id IN (` + placeholders(len(articleIDs)) + `)
+		ORDER BY at.article_id, t.name ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), intArgs(articleIDs)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID int
+		var tag string
+		if err := rows.Scan(&articleID, &tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags[articleID] = append(tags[articleID], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetArticleCountByTag gets the number of published articles for a specific tag
+func (r *TagRepository) GetArticleCountByTag(ctx context.Context, tagName string) (int, error) {
 	query := `
 		SELECT COUNT(at.article_id)
 		FROM tags t
 		INNER JOIN article_tags at ON t.id = at.tag_id
-		WHERE t.name = ?
+		INNER JOIN articles a ON a.id = at.article_id
+		WHERE t.name = ? AND ` + publishedCondition + `
 	`
 
 	var count int
-	err := r.db.QueryRow(query, tagName).Scan(&count)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), tagName).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get article count for tag: %w", err)
 	}
@@ -196,7 +237,7 @@ func (r *TagRepository) GetArticleCountByTag(tagName string) (int, error) {
 }
 
 // DeleteUnusedTags removes tags that are not associated with any articles
-func (r *TagRepository) DeleteUnusedTags() error {
+func (r *TagRepository) DeleteUnusedTags(ctx context.Context) error {
 	query := `
 		DELETE FROM tags 
 		WHERE id NOT IN (
@@ -204,7 +245,7 @@ func (r *TagRepository) DeleteUnusedTags() error {
 		)
 	`
 
-	_, err := r.db.Exec(query)
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query))
 	if err != nil {
 		return fmt.Errorf("failed to delete unused tags: %w", err)
 	}
@@ -213,11 +254,11 @@ func (r *TagRepository) DeleteUnusedTags() error {
 }
 
 // TagExists checks if a tag exists by name
-func (r *TagRepository) TagExists(tagName string) (bool, error) {
+func (r *TagRepository) TagExists(ctx context.Context, tagName string) (bool, error) {
 	query := `SELECT COUNT(*) FROM tags WHERE name = ?`
 
 	var count int
-	err := r.db.QueryRow(query, tagName).Scan(&count)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), tagName).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check tag existence: %w", err)
 	}
@@ -226,10 +267,10 @@ func (r *TagRepository) TagExists(tagName string) (bool, error) {
 }
 
 // getOrCreateTag gets an existing tag ID or creates a new tag
-func (r *TagRepository) getOrCreateTag(tx *sql.Tx, tagName string) (int, error) {
+func (r *TagRepository) getOrCreateTag(ctx context.Context, tx db.Executor, tagName string) (int, error) {
 	// Try to get existing tag
 	var tagID int
-	err := tx.QueryRow("SELECT id FROM tags WHERE name = ?", tagName).Scan(&tagID)
+	err := tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT id FROM tags WHERE name = ?"), tagName).Scan(&tagID)
 	if err == nil {
 		return tagID, nil
 	}
@@ -239,15 +280,10 @@ func (r *TagRepository) getOrCreateTag(tx *sql.Tx, tagName string) (int, error)
 	}
 
 	// Create new tag
-	result, err := tx.Exec("INSERT INTO tags (name) VALUES (?)", tagName)
+	id, err := r.dialect.InsertReturningID(ctx, tx, "INSERT INTO tags (name) VALUES (?)", tagName)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create tag: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("failed to get tag ID: %w", err)
-	}
-
 	return int(id), nil
 }
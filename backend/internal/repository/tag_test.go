@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+func TestTagRepository(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		userRepo := NewUserRepository(database)
+		articleRepo := NewArticleRepository(database)
+		tagRepo := NewTagRepository(database)
+
+		author := createTestUser(t, userRepo, "author")
+		first := createTestArticle(t, articleRepo, tagRepo, "first", author.ID, "go", "sql")
+		createTestArticle(t, articleRepo, tagRepo, "second", author.ID, "go")
+
+		// Linking the same tag twice must be ignored rather than fail
+		if err := tagRepo.CreateTagsForArticle(ctx, first.ID, []string{"go"}); err != nil {
+			t.Fatalf("CreateTagsForArticle() duplicate error = %v", err)
+		}
+
+		popular, err := tagRepo.GetPopularTags(ctx, 10)
+		if err != nil {
+			t.Fatalf("GetPopularTags() error = %v", err)
+		}
+		if !reflect.DeepEqual(popular, []string{"go", "sql"}) {
+			t.Errorf("GetPopularTags() = %v, want [go sql]", popular)
+		}
+
+		count, err := tagRepo.GetArticleCountByTag(ctx, "go")
+		if err != nil || count != 2 {
+			t.Errorf("GetArticleCountByTag(go) = %d, %v; want 2", count, err)
+		}
+
+		if err := tagRepo.UpdateTagsForArticle(ctx, first.ID, []string{"rust"}); err != nil {
+			t.Fatalf("UpdateTagsForArticle() error = %v", err)
+		}
+		tags, err := tagRepo.GetTagsForArticle(ctx, first.ID)
+		if err != nil || !reflect.DeepEqual(tags, []string{"rust"}) {
+			t.Errorf("GetTagsForArticle() = %v, %v; want [rust]", tags, err)
+		}
+
+		if err := tagRepo.DeleteUnusedTags(ctx); err != nil {
+			t.Fatalf("DeleteUnusedTags() error = %v", err)
+		}
+		exists, err := tagRepo.TagExists(ctx, "sql")
+		if err != nil || exists {
+			t.Errorf("TagExists(sql) = %v, %v; want false after cleanup", exists, err)
+		}
+
+		all, err := tagRepo.GetAllTags(ctx)
+		if err != nil || !reflect.DeepEqual(all, []string{"go", "rust"}) {
+			t.Errorf("GetAllTags() = %v, %v; want [go rust]", all, err)
+		}
+	})
+}
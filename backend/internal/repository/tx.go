@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+// Repositories groups repositories that share one transaction
+type Repositories struct {
+	Users    UserStore
+	Articles ArticleStore
+	Tags     TagStore
+	Comments CommentStore
+}
+
+// UnitOfWork runs several repository operations atomically
+type UnitOfWork struct {
+	db      db.Conn
+	dialect db.Dialect
+}
+
+// NewUnitOfWork creates a new unit of work on the primary database
+func NewUnitOfWork(database *db.Database) *UnitOfWork {
+	return &UnitOfWork{db: database.Primary(), dialect: database.Dialect()}
+}
+
+// Do runs fn with repositories bound to a new transaction. The transaction
+// is committed when fn returns nil and rolled back otherwise. Reads made
+// through the repositories see the transaction's own writes.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos *Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	repos := &Repositories{
+		Users:    &UserRepository{db: tx, reader: tx, dialect: u.dialect},
+		Articles: &ArticleRepository{db: tx, reader: tx, dialect: u.dialect},
+		Tags:     &TagRepository{db: tx, reader: tx, dialect: u.dialect},
+		Comments: &CommentRepository{db: tx, dialect: u.dialect},
+	}
+
+	if err := fn(repos); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// withinTx runs fn in a transaction on exec. A repository bound to a unit of
+// work joins its transaction; otherwise a transaction is begun for fn alone.
+func withinTx(ctx context.Context, exec db.Executor, fn func(tx db.Executor) error) error {
+	if tx, ok := exec.(db.Tx); ok {
+		return fn(tx)
+	}
+
+	conn, ok := exec.(db.Conn)
+	if !ok {
+		return fmt.Errorf("cannot begin a transaction on %T", exec)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
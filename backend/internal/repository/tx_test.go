@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		uow := NewUnitOfWork(database)
+		userRepo := NewUserRepository(database)
+		author := createTestUser(t, userRepo, "author")
+		reader := createTestUser(t, userRepo, "reader")
+
+		// Writes made in the transaction are visible to it and committed together
+		err := uow.Do(ctx, func(repos *Repositories) error {
+			article := &model.Article{Slug: "committed", Title: "Committed", Description: "d", Body: "b", AuthorID: author.ID}
+			if err := repos.Articles.Create(ctx, article); err != nil {
+				return err
+			}
+			if err := repos.Tags.CreateTagsForArticle(ctx, article.ID, []string{"go"}); err != nil {
+				return err
+			}
+
+			tags, err := repos.Tags.GetTagsForArticle(ctx, article.ID)
+			if err != nil {
+				return err
+			}
+			if len(tags) != 1 {
+				t.Errorf("GetTagsForArticle() in transaction = %v, want [go]", tags)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+
+		articleRepo := NewArticleRepository(database)
+		if exists, err := articleRepo.CheckArticleExists(ctx, "committed"); err != nil || !exists {
+			t.Errorf("CheckArticleExists() after commit = %v, %v; want true", exists, err)
+		}
+
+		// A failure part way through rolls back every repository's writes
+		errFailed := errors.New("failed")
+		err = uow.Do(ctx, func(repos *Repositories) error {
+			article := &model.Article{Slug: "rolled-back", Title: "Rolled back", Description: "d", Body: "b", AuthorID: author.ID}
+			if err := repos.Articles.Create(ctx, article); err != nil {
+				return err
+			}
+			if err := repos.Tags.CreateTagsForArticle(ctx, article.ID, []string{"rust"}); err != nil {
+				return err
+			}
+			if err := repos.Users.FollowUser(ctx, reader.ID, author.ID); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		if !errors.Is(err, errFailed) {
+			t.Fatalf("Do() error = %v, want %v", err, errFailed)
+		}
+
+		if exists, err := articleRepo.CheckArticleExists(ctx, "rolled-back"); err != nil || exists {
+			t.Errorf("CheckArticleExists() after rollback = %v, %v; want false", exists, err)
+		}
+		tagRepo := NewTagRepository(database)
+		if exists, err := tagRepo.TagExists(ctx, "rust"); err != nil || exists {
+			t.Errorf("TagExists() after rollback = %v, %v; want false", exists, err)
+		}
+		if following, err := userRepo.IsFollowing(ctx, reader.ID, author.ID); err != nil || following {
+			t.Errorf("IsFollowing() after rollback = %v, %v; want false", following, err)
+		}
+	})
+}
@@ -1,31 +1,35 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
 )
 
 // UserRepository handles user data operations
 type UserRepository struct {
-	db *sql.DB
+	db      db.Executor
+	reader  db.Executor // read replica, or the primary when none is configured
+	dialect db.Dialect
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(database *db.Database) *UserRepository {
+	return &UserRepository{db: database.Primary(), reader: database.ReadOnly(), dialect: database.Dialect()}
 }
 
 // GetByID retrieves a user by ID
-func (r *UserRepository) GetByID(id int) (*model.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
 	query := `
 		SELECT id, email, username, password_hash, bio, image, created_at, updated_at
 		FROM users WHERE id = ?
 	`
 
 	var user model.User
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), id).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Username,
@@ -46,15 +50,58 @@ func (r *UserRepository) GetByID(id int) (*model.User, error) {
 	return &user, nil
 }
 
+// GetByIDs retrieves the users with the given IDs, keyed by ID. Missing
+// users are left out of the map.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []int) (map[int]*model.User, error) {
+	users := make(map[int]*model.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	query := `
+		SELECT id, email, username, password_hash, bio, image, created_at, updated_at
+		FROM users WHERE id IN (` + placeholders(len(ids)) + `)
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), intArgs(ids)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users by ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user model.User
+		if err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.Username,
+			&user.PasswordHash,
+			&user.Bio,
+			&user.Image,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users[user.ID] = &user
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return users, nil
+}
+
 // GetByEmail retrieves a user by email
-func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	query := `
 		SELECT id, email, username, password_hash, bio, image, created_at, updated_at
 		FROM users WHERE email = ?
 	`
 
 	var user model.User
-	err := r.db.QueryRow(query, email).Scan(
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), email).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Username,
@@ -76,14 +123,14 @@ func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
 }
 
 // GetByUsername retrieves a user by username
-func (r *UserRepository) GetByUsername(username string) (*model.User, error) {
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	query := `
 		SELECT id, email, username, password_hash, bio, image, created_at, updated_at
 		FROM users WHERE username = ?
 	`
 
 	var user model.User
-	err := r.db.QueryRow(query, username).Scan(
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), username).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Username,
@@ -105,35 +152,30 @@ func (r *UserRepository) GetByUsername(username string) (*model.User, error) {
 }
 
 // Create creates a new user
-func (r *UserRepository) Create(user *model.User) error {
+func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 	query := `
 		INSERT INTO users (email, username, password_hash, bio, image)
 		VALUES (?, ?, ?, ?, ?)
 	`
 
-	result, err := r.db.Exec(query, user.Email, user.Username, user.PasswordHash, user.Bio, user.Image)
+	id, err := r.dialect.InsertReturningID(ctx, r.db, query, user.Email, user.Username, user.PasswordHash, user.Bio, user.Image)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert ID: %w", err)
-	}
-
 	user.ID = int(id)
 	return nil
 }
 
 // Update updates an existing user
-func (r *UserRepository) Update(user *model.User) error {
+func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 	query := `
 		UPDATE users 
 		SET email = ?, username = ?, password_hash = ?, bio = ?, image = ?
 		WHERE id = ?
 	`
 
-	_, err := r.db.Exec(query, user.Email, user.Username, user.PasswordHash, user.Bio, user.Image, user.ID)
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), user.Email, user.Username, user.PasswordHash, user.Bio, user.Image, user.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -142,11 +184,11 @@ func (r *UserRepository) Update(user *model.User) error {
 }
 
 // EmailExists checks if an email is already taken
-func (r *UserRepository) EmailExists(email string) (bool, error) {
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	query := `SELECT COUNT(*) FROM users WHERE email = ?`
 
 	var count int
-	err := r.db.QueryRow(query, email).Scan(&count)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), email).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check email existence: %w", err)
 	}
@@ -155,11 +197,11 @@ func (r *UserRepository) EmailExists(email string) (bool, error) {
 }
 
 // UsernameExists checks if a username is already taken
-func (r *UserRepository) UsernameExists(username string) (bool, error) {
+func (r *UserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
 	query := `SELECT COUNT(*) FROM users WHERE username = ?`
 
 	var count int
-	err := r.db.QueryRow(query, username).Scan(&count)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), username).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check username existence: %w", err)
 	}
@@ -168,10 +210,10 @@ func (r *UserRepository) UsernameExists(username string) (bool, error) {
 }
 
 // FollowUser creates a follow relationship
-func (r *UserRepository) FollowUser(followerID, followedID int) error {
+func (r *UserRepository) FollowUser(ctx context.Context, followerID, followedID int) error {
 	query := `INSERT INTO follows (follower_id, followed_id) VALUES (?, ?)`
 
-	_, err := r.db.Exec(query, followerID, followedID)
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), followerID, followedID)
 	if err != nil {
 		return fmt.Errorf("failed to follow user: %w", err)
 	}
@@ -180,10 +222,10 @@ func (r *UserRepository) FollowUser(followerID, followedID int) error {
 }
 
 // UnfollowUser removes a follow relationship
-func (r *UserRepository) UnfollowUser(followerID, followedID int) error {
+func (r *UserRepository) UnfollowUser(ctx context.Context, followerID, followedID int) error {
 	query := `DELETE FROM follows WHERE follower_id = ? AND followed_id = ?`
 
-	result, err := r.db.Exec(query, followerID, followedID)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), followerID, followedID)
 	if err != nil {
 		return fmt.Errorf("failed to unfollow user: %w", err)
 	}
@@ -201,11 +243,11 @@ func (r *UserRepository) UnfollowUser(followerID, followedID int) error {
 }
 
 // IsFollowing checks if a user is following another user
-func (r *UserRepository) IsFollowing(followerID, followedID int) (bool, error) {
+func (r *UserRepository) IsFollowing(ctx context.Context, followerID, followedID int) (bool, error) {
 	query := `SELECT COUNT(*) FROM follows WHERE follower_id = ? AND followed_id = ?`
 
 	var count int
-	err := r.db.QueryRow(query, followerID, followedID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), followerID, followedID).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check follow status: %w", err)
 	}
@@ -213,28 +255,54 @@ func (r *UserRepository) IsFollowing(followerID, followedID int) (bool, error) {
 	return count > 0, nil
 }
 
-// GetProfileByUsername gets a user profile by username with follow status
-func (r *UserRepository) GetProfileByUsername(username string, currentUserID *int) (*model.ProfileResponse, error) {
-	user, err := r.GetByUsername(username)
+// GetFollowedIDs reports which of the given users a user follows
+func (r *UserRepository) GetFollowedIDs(ctx context.Context, followerID int, userIDs []int) (map[int]bool, error) {
+	if len(userIDs) == 0 {
+		return map[int]bool{}, nil
+	}
+
+	query := `SELECT followed_id FROM follows WHERE follower_id = ? AND followed_id IN (` + placeholders(len(userIDs)) + `)`
+
+	args := append([]interface{}{followerID}, intArgs(userIDs)...)
+	followed, err := queryIDSet(ctx, r.db, r.dialect.Rebind(query), args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to check follow statuses: %w", err)
 	}
 
-	profile := &model.ProfileResponse{
-		Username:  user.Username,
-		Bio:       user.Bio,
-		Image:     user.Image,
-		Following: false,
+	return followed, nil
+}
+
+// GetProfileByUsername gets a user profile by username with follow status.
+// It reads from the replica; callers that just changed the follow state
+// build the profile themselves.
+func (r *UserRepository) GetProfileByUsername(ctx context.Context, username string, currentUserID *int) (*model.ProfileResponse, error) {
+	query := `
+		SELECT u.username, u.bio, u.image,
+		       (SELECT COUNT(*) FROM follows f WHERE f.follower_id = ? AND f.followed_id = u.id)
+		FROM users u WHERE u.username = ?
+	`
+
+	followerID := 0
+	if currentUserID != nil {
+		followerID = *currentUserID
 	}
 
-	// Check if current user is following this user
-	if currentUserID != nil && *currentUserID > 0 {
-		isFollowing, err := r.IsFollowing(*currentUserID, user.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check follow status: %w", err)
+	var profile model.ProfileResponse
+	var following int
+	err := r.reader.QueryRowContext(ctx, r.dialect.Rebind(query), followerID, username).Scan(
+		&profile.Username,
+		&profile.Bio,
+		&profile.Image,
+		&following,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
 		}
-		profile.Following = isFollowing
+		return nil, fmt.Errorf("failed to get profile: %w", err)
 	}
 
-	return profile, nil
+	profile.Following = following > 0
+	return &profile, nil
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+)
+
+func TestUserRepositoryCRUD(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		repo := NewUserRepository(database)
+
+		jake := createTestUser(t, repo, "jake")
+		if jake.ID == 0 {
+			t.Fatal("Expected user ID to be set after create")
+		}
+		second := createTestUser(t, repo, "celeb")
+		if second.ID == jake.ID {
+			t.Fatalf("Expected distinct IDs, both got %d", jake.ID)
+		}
+
+		byID, err := repo.GetByID(ctx, jake.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if byID.Username != "jake" {
+			t.Errorf("GetByID() username = %s, want jake", byID.Username)
+		}
+
+		byEmail, err := repo.GetByEmail(ctx, "jake@example.com")
+		if err != nil || byEmail.ID != jake.ID {
+			t.Errorf("GetByEmail() = %v, %v; want user %d", byEmail, err, jake.ID)
+		}
+
+		if _, err := repo.GetByUsername(ctx, "nobody"); err == nil || err.Error() != "user not found" {
+			t.Errorf("GetByUsername() error = %v, want user not found", err)
+		}
+
+		exists, err := repo.EmailExists(ctx, "jake@example.com")
+		if err != nil || !exists {
+			t.Errorf("EmailExists() = %v, %v; want true", exists, err)
+		}
+		exists, err = repo.UsernameExists(ctx, "nobody")
+		if err != nil || exists {
+			t.Errorf("UsernameExists() = %v, %v; want false", exists, err)
+		}
+
+		jake.Bio = "I work at statefarm"
+		if err := repo.Update(ctx, jake); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		updated, _ := repo.GetByID(ctx, jake.ID)
+		if updated.Bio != "I work at statefarm" {
+			t.Errorf("Update() bio = %q, want updated bio", updated.Bio)
+		}
+
+		if err := repo.Create(ctx, jake); err == nil {
+			t.Error("Expected duplicate email to fail")
+		}
+	})
+}
+
+func TestUserRepositoryFollows(t *testing.T) {
+	ctx := context.Background()
+	forEachDialect(t, func(t *testing.T, database *db.Database) {
+		repo := NewUserRepository(database)
+
+		jake := createTestUser(t, repo, "jake")
+		celeb := createTestUser(t, repo, "celeb")
+
+		if err := repo.FollowUser(ctx, jake.ID, celeb.ID); err != nil {
+			t.Fatalf("FollowUser() error = %v", err)
+		}
+
+		following, err := repo.IsFollowing(ctx, jake.ID, celeb.ID)
+		if err != nil || !following {
+			t.Errorf("IsFollowing() = %v, %v; want true", following, err)
+		}
+
+		profile, err := repo.GetProfileByUsername(ctx, "celeb", &jake.ID)
+		if err != nil {
+			t.Fatalf("GetProfileByUsername() error = %v", err)
+		}
+		if !profile.Following {
+			t.Error("Expected profile to be followed by jake")
+		}
+
+		profile, err = repo.GetProfileByUsername(ctx, "celeb", nil)
+		if err != nil || profile.Following {
+			t.Errorf("GetProfileByUsername() anonymous = %v, %v; want not following", profile, err)
+		}
+
+		if err := repo.UnfollowUser(ctx, jake.ID, celeb.ID); err != nil {
+			t.Fatalf("UnfollowUser() error = %v", err)
+		}
+		if err := repo.UnfollowUser(ctx, jake.ID, celeb.ID); err == nil || err.Error() != "follow relationship not found" {
+			t.Errorf("UnfollowUser() twice error = %v, want follow relationship not found", err)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+// Package seed populates a database with demo data through the services,
+// either generated from a seed value or loaded from a fixture file
+package seed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture describes a data set. Users are referenced by username and
+// articles by title.
+type Fixture struct {
+	Users     []User     `json:"users" yaml:"users"`
+	Articles  []Article  `json:"articles" yaml:"articles"`
+	Follows   []Follow   `json:"follows" yaml:"follows"`
+	Favorites []Favorite `json:"favorites" yaml:"favorites"`
+	Comments  []Comment  `json:"comments" yaml:"comments"`
+}
+
+// User is a user account to create
+type User struct {
+	Username string `json:"username" yaml:"username"`
+	Email    string `json:"email" yaml:"email"`
+	Password string `json:"password" yaml:"password"`
+	Bio      string `json:"bio,omitempty" yaml:"bio,omitempty"`
+	Image    string `json:"image,omitempty" yaml:"image,omitempty"`
+}
+
+// Article is an article to publish
+type Article struct {
+	Author      string   `json:"author" yaml:"author"`
+	Title       string   `json:"title" yaml:"title"`
+	Description string   `json:"description" yaml:"description"`
+	Body        string   `json:"body" yaml:"body"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Follow makes Follower follow Followed
+type Follow struct {
+	Follower string `json:"follower" yaml:"follower"`
+	Followed string `json:"followed" yaml:"followed"`
+}
+
+// Favorite makes User favorite the article with the given title
+type Favorite struct {
+	User    string `json:"user" yaml:"user"`
+	Article string `json:"article" yaml:"article"`
+}
+
+// Comment is a comment by Author on the article with the given title
+type Comment struct {
+	Author  string `json:"author" yaml:"author"`
+	Article string `json:"article" yaml:"article"`
+	Body    string `json:"body" yaml:"body"`
+}
+
+// LoadFixture reads a fixture from a .yaml, .yml or .json file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &fixture)
+	case ".json":
+		err = json.Unmarshal(data, &fixture)
+	default:
+		return nil, fmt.Errorf("unsupported fixture format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	return &fixture, nil
+}
+
+// Validate checks that every reference in the fixture resolves
+func (f *Fixture) Validate() error {
+	users := make(map[string]bool, len(f.Users))
+	for _, user := range f.Users {
+		if users[user.Username] {
+			return fmt.Errorf("duplicate user: %s", user.Username)
+		}
+		users[user.Username] = true
+	}
+
+	articles := make(map[string]bool, len(f.Articles))
+	for _, article := range f.Articles {
+		if articles[article.Title] {
+			return fmt.Errorf("duplicate article title: %s", article.Title)
+		}
+		articles[article.Title] = true
+		if !users[article.Author] {
+			return fmt.Errorf("article %q: unknown author %s", article.Title, article.Author)
+		}
+	}
+
+	for _, follow := range f.Follows {
+		if !users[follow.Follower] || !users[follow.Followed] {
+			return fmt.Errorf("follow %s -> %s: unknown user", follow.Follower, follow.Followed)
+		}
+	}
+
+	for _, favorite := range f.Favorites {
+		if !users[favorite.User] || !articles[favorite.Article] {
+			return fmt.Errorf("favorite %s -> %q: unknown user or article", favorite.User, favorite.Article)
+		}
+	}
+
+	for _, comment := range f.Comments {
+		if !users[comment.Author] || !articles[comment.Article] {
+			return fmt.Errorf("comment %s -> %q: unknown user or article", comment.Author, comment.Article)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// GenerateOptions controls the size of a generated data set
+type GenerateOptions struct {
+	Seed      int64 // the same seed always produces the same fixture
+	Users     int
+	Articles  int
+	Follows   int
+	Favorites int
+	Comments  int
+	Password  string // password of every generated user
+}
+
+// DefaultGenerateOptions returns a small data set suitable for local development
+func DefaultGenerateOptions() GenerateOptions {
+	return GenerateOptions{
+		Seed:      1,
+		Users:     10,
+		Articles:  30,
+		Follows:   20,
+		Favorites: 60,
+		Comments:  60,
+		Password:  "password123",
+	}
+}
+
+var (
+	adjectives = []string{"brave", "calm", "clever", "eager", "gentle", "happy", "jolly", "kind", "lively", "proud", "quiet", "swift", "witty", "zesty"}
+	nouns      = []string{"otter", "falcon", "panda", "badger", "heron", "lynx", "tiger", "koala", "raven", "walrus", "gecko", "bison", "moose", "crane"}
+	topics     = []string{"golang", "react", "sql", "testing", "devops", "design", "career", "security", "performance", "docker", "typescript", "databases"}
+	words      = []string{
+		"build", "scale", "debug", "deploy", "refactor", "measure", "simple", "robust", "fast", "modern",
+		"service", "query", "index", "cache", "pipeline", "container", "component", "pattern", "release", "schema",
+		"team", "review", "habit", "lesson", "mistake", "guide", "journey", "trick", "tool", "workflow",
+	}
+)
+
+// Generate builds a fixture of random but reproducible users, articles,
+// follows, favorites and comments. Counts that exceed what the data set
+// allows, such as more follows than user pairs, are capped.
+func Generate(opts GenerateOptions) *Fixture {
+	rng := rand.New(rand.NewSource(opts.Seed))
+	fixture := &Fixture{}
+
+	usernames := make(map[string]bool, opts.Users)
+	for len(fixture.Users) < opts.Users {
+		username := fmt.Sprintf("%s_%s%d", pick(rng, adjectives), pick(rng, nouns), rng.Intn(100))
+		if usernames[username] {
+			// Names without an adjective are numbered by user, so they never
+			// repeat and data sets may be larger than the word lists allow
+			username = fmt.Sprintf("%s_%d", pick(rng, nouns), len(fixture.Users)+1)
+		}
+		usernames[username] = true
+		fixture.Users = append(fixture.Users, User{
+			Username: username,
+			Email:    username + "@example.com",
+			Password: opts.Password,
+			Bio:      fmt.Sprintf("I write about %s and %s.", pick(rng, topics), pick(rng, topics)),
+		})
+	}
+	if len(fixture.Users) == 0 {
+		return fixture
+	}
+
+	titles := make(map[string]bool, opts.Articles)
+	for len(fixture.Articles) < opts.Articles {
+		title := fmt.Sprintf("How to %s a %s %s", pick(rng, words), pick(rng, words), pick(rng, words))
+		if titles[title] {
+			title = fmt.Sprintf("%s, part %d", title, len(fixture.Articles)+1)
+		}
+		titles[title] = true
+
+		tags := map[string]bool{}
+		for i := rng.Intn(4); i > 0; i-- {
+			tags[pick(rng, topics)] = true
+		}
+
+		fixture.Articles = append(fixture.Articles, Article{
+			Author:      fixture.Users[rng.Intn(len(fixture.Users))].Username,
+			Title:       title,
+			Description: sentence(rng, 8),
+			Body:        paragraphs(rng, 1+rng.Intn(3)),
+			Tags:        sortedKeys(tags),
+		})
+	}
+
+	// Follows: distinct pairs, never following oneself
+	maxFollows := len(fixture.Users) * (len(fixture.Users) - 1)
+	followed := map[[2]int]bool{}
+	for len(fixture.Follows) < opts.Follows && len(fixture.Follows) < maxFollows {
+		follower, target := rng.Intn(len(fixture.Users)), rng.Intn(len(fixture.Users))
+		if follower == target || followed[[2]int{follower, target}] {
+			continue
+		}
+		followed[[2]int{follower, target}] = true
+		fixture.Follows = append(fixture.Follows, Follow{
+			Follower: fixture.Users[follower].Username,
+			Followed: fixture.Users[target].Username,
+		})
+	}
+
+	// Favorites: distinct user and article pairs
+	maxFavorites := len(fixture.Users) * len(fixture.Articles)
+	favorited := map[[2]int]bool{}
+	for len(fixture.Favorites) < opts.Favorites && len(fixture.Favorites) < maxFavorites {
+		user, article := rng.Intn(len(fixture.Users)), rng.Intn(len(fixture.Articles))
+		if favorited[[2]int{user, article}] {
+			continue
+		}
+		favorited[[2]int{user, article}] = true
+		fixture.Favorites = append(fixture.Favorites, Favorite{
+			User:    fixture.Users[user].Username,
+			Article: fixture.Articles[article].Title,
+		})
+	}
+
+	if len(fixture.Articles) > 0 {
+		for i := 0; i < opts.Comments; i++ {
+			fixture.Comments = append(fixture.Comments, Comment{
+				Author:  fixture.Users[rng.Intn(len(fixture.Users))].Username,
+				Article: fixture.Articles[rng.Intn(len(fixture.Articles))].Title,
+				Body:    sentence(rng, 5+rng.Intn(10)),
+			})
+		}
+	}
+
+	return fixture
+}
+
+// pick returns a random element of list
+func pick(rng *rand.Rand, list []string) string {
+	return list[rng.Intn(len(list))]
+}
+
+// sentence returns a capitalized sentence of n random words
+func sentence(rng *rand.Rand, n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = pick(rng, words)
+	}
+	text := strings.Join(parts, " ")
+	return strings.ToUpper(text[:1]) + text[1:] + "."
+}
+
+// paragraphs returns n Markdown paragraphs of random sentences
+func paragraphs(rng *rand.Rand, n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		sentences := make([]string, 2+rng.Intn(4))
+		for j := range sentences {
+			sentences[j] = sentence(rng, 6+rng.Intn(8))
+		}
+		parts[i] = strings.Join(sentences, " ")
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// sortedKeys returns the keys of set in a stable order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for _, topic := range topics {
+		if set[topic] {
+			keys = append(keys, topic)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,99 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/service"
+)
+
+// Services are the services used to create seed data, so seeded rows go
+// through the same validation and side effects as API requests
+type Services struct {
+	Users    *service.UserService
+	Articles *service.ArticleService
+	Profiles *service.ProfileService
+	Comments *service.CommentService
+}
+
+// Summary counts the records created by Apply
+type Summary struct {
+	Users     int
+	Articles  int
+	Follows   int
+	Favorites int
+	Comments  int
+}
+
+// Apply creates the fixture's records through the services
+func Apply(ctx context.Context, services Services, fixture *Fixture) (*Summary, error) {
+	if err := fixture.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+
+	summary := &Summary{}
+	userIDs := make(map[string]int, len(fixture.Users))
+	slugs := make(map[string]string, len(fixture.Articles))
+
+	for _, u := range fixture.Users {
+		var req model.CreateUserRequest
+		req.User.Username = u.Username
+		req.User.Email = u.Email
+		req.User.Password = u.Password
+
+		user, err := services.Users.CreateUser(ctx, req)
+		if err != nil {
+			return summary, fmt.Errorf("failed to create user %s: %w", u.Username, err)
+		}
+		userIDs[u.Username] = user.ID
+
+		if u.Bio != "" || u.Image != "" {
+			var update model.UpdateUserRequest
+			update.User.Bio = &u.Bio
+			update.User.Image = &u.Image
+			if _, err := services.Users.UpdateUser(ctx, user.ID, update); err != nil {
+				return summary, fmt.Errorf("failed to update profile of %s: %w", u.Username, err)
+			}
+		}
+		summary.Users++
+	}
+
+	for _, a := range fixture.Articles {
+		var req model.CreateArticleRequest
+		req.Article.Title = a.Title
+		req.Article.Description = a.Description
+		req.Article.Body = a.Body
+		req.Article.TagList = a.Tags
+
+		article, err := services.Articles.CreateArticle(ctx, req, userIDs[a.Author])
+		if err != nil {
+			return summary, fmt.Errorf("failed to create article %q: %w", a.Title, err)
+		}
+		slugs[a.Title] = article.Slug
+		summary.Articles++
+	}
+
+	for _, f := range fixture.Follows {
+		if _, err := services.Profiles.FollowUser(ctx, userIDs[f.Follower], f.Followed); err != nil {
+			return summary, fmt.Errorf("failed to follow %s as %s: %w", f.Followed, f.Follower, err)
+		}
+		summary.Follows++
+	}
+
+	for _, f := range fixture.Favorites {
+		if _, err := services.Articles.FavoriteArticle(ctx, slugs[f.Article], userIDs[f.User]); err != nil {
+			return summary, fmt.Errorf("failed to favorite %q as %s: %w", f.Article, f.User, err)
+		}
+		summary.Favorites++
+	}
+
+	for _, c := range fixture.Comments {
+		if _, err := services.Comments.CreateComment(ctx, slugs[c.Article], c.Body, userIDs[c.Author]); err != nil {
+			return summary, fmt.Errorf("failed to comment on %q as %s: %w", c.Article, c.Author, err)
+		}
+		summary.Comments++
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,177 @@
+package seed
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/service"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/migrations"
+)
+
+// newTestServices opens a migrated temporary database and wires the services
+func newTestServices(t *testing.T) (Services, *repository.ArticleRepository) {
+	t.Helper()
+
+	database, err := db.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), db.SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := db.NewMigrationManager(database.DB, db.SQLite).RunMigrations(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository(database)
+	articleRepo := repository.NewArticleRepository(database)
+	tagService := service.NewTagService(repository.NewTagRepository(database))
+
+	return Services{
+		Users:    service.NewUserService(userRepo),
+		Articles: service.NewArticleService(repository.NewUnitOfWork(database), articleRepo, userRepo, tagService),
+		Profiles: service.NewProfileService(userRepo),
+		Comments: service.NewCommentService(repository.NewCommentRepository(database), articleRepo, userRepo),
+	}, articleRepo
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	opts := DefaultGenerateOptions()
+
+	first := Generate(opts)
+	if !reflect.DeepEqual(first, Generate(opts)) {
+		t.Error("Expected the same seed to generate the same fixture")
+	}
+
+	opts.Seed = 2
+	if reflect.DeepEqual(first, Generate(opts)) {
+		t.Error("Expected a different seed to generate a different fixture")
+	}
+
+	if err := first.Validate(); err != nil {
+		t.Errorf("Generated fixture is invalid: %v", err)
+	}
+	if len(first.Users) != opts.Users || len(first.Articles) != opts.Articles || len(first.Comments) != opts.Comments {
+		t.Errorf("Generate() sizes = %d users, %d articles, %d comments; want %d, %d, %d",
+			len(first.Users), len(first.Articles), len(first.Comments), opts.Users, opts.Articles, opts.Comments)
+	}
+}
+
+func TestGenerateCapsRelationships(t *testing.T) {
+	fixture := Generate(GenerateOptions{Seed: 1, Users: 2, Articles: 1, Follows: 10, Favorites: 10, Password: "password123"})
+
+	if len(fixture.Follows) != 2 {
+		t.Errorf("Generate() follows = %d, want 2 for two users", len(fixture.Follows))
+	}
+	if len(fixture.Favorites) != 2 {
+		t.Errorf("Generate() favorites = %d, want 2 for two users and one article", len(fixture.Favorites))
+	}
+}
+
+func TestGenerateManyUsers(t *testing.T) {
+	// Far more users than adjective, noun and number combinations
+	const users = 50000
+	fixture := Generate(GenerateOptions{Seed: 1, Users: users, Password: "password123"})
+
+	if len(fixture.Users) != users {
+		t.Fatalf("Generate() users = %d, want %d", len(fixture.Users), users)
+	}
+	seen := make(map[string]bool, users)
+	for _, user := range fixture.Users {
+		if seen[user.Username] || len(user.Username) > 20 {
+			t.Fatalf("Generate() username %q is repeated or too long", user.Username)
+		}
+		seen[user.Username] = true
+	}
+}
+
+func TestLoadFixture(t *testing.T) {
+	yamlFixture, err := LoadFixture("../../fixtures/example.yaml")
+	if err != nil {
+		t.Fatalf("LoadFixture(yaml) error = %v", err)
+	}
+	if len(yamlFixture.Users) != 3 || len(yamlFixture.Articles) != 2 || yamlFixture.Articles[0].Tags[0] != "dragons" {
+		t.Errorf("LoadFixture(yaml) = %+v, want example data", yamlFixture)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	json := `{"users": [{"username": "jake", "email": "jake@example.com", "password": "jakejake1"}]}`
+	if err := os.WriteFile(path, []byte(json), 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	jsonFixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture(json) error = %v", err)
+	}
+	if len(jsonFixture.Users) != 1 || jsonFixture.Users[0].Username != "jake" {
+		t.Errorf("LoadFixture(json) = %+v, want one user", jsonFixture)
+	}
+
+	if _, err := LoadFixture("fixture.toml"); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
+
+func TestFixtureValidate(t *testing.T) {
+	users := []User{{Username: "jake"}}
+	articles := []Article{{Author: "jake", Title: "Dragons"}}
+
+	tests := []struct {
+		name    string
+		fixture Fixture
+		wantErr bool
+	}{
+		{"valid", Fixture{Users: users, Articles: articles, Comments: []Comment{{Author: "jake", Article: "Dragons"}}}, false},
+		{"duplicate user", Fixture{Users: append(users, users...)}, true},
+		{"unknown author", Fixture{Users: users, Articles: []Article{{Author: "jane", Title: "Dragons"}}}, true},
+		{"unknown follow", Fixture{Users: users, Follows: []Follow{{Follower: "jake", Followed: "jane"}}}, true},
+		{"unknown article", Fixture{Users: users, Articles: articles, Favorites: []Favorite{{User: "jake", Article: "Cats"}}}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.fixture.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	services, articleRepo := newTestServices(t)
+
+	fixture, err := LoadFixture("../../fixtures/example.yaml")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+
+	summary, err := Apply(ctx, services, fixture)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	expected := Summary{Users: 3, Articles: 2, Follows: 2, Favorites: 3, Comments: 2}
+	if *summary != expected {
+		t.Errorf("Apply() = %+v, want %+v", *summary, expected)
+	}
+
+	articles, count, err := articleRepo.GetArticles(ctx, repository.Page{Limit: 10}, repository.ArticleFilter{Tags: []string{"dragons"}})
+	if err != nil || count != 1 {
+		t.Fatalf("GetArticles(tag=dragons) = %d, %v; want 1", count, err)
+	}
+	if articles[0].FavoritesCount != 2 {
+		t.Errorf("favorites count = %d, want 2", articles[0].FavoritesCount)
+	}
+
+	profile, err := services.Profiles.GetProfile(ctx, "jake", nil)
+	if err != nil || profile.Bio != "I work at statefarm" {
+		t.Errorf("GetProfile(jake) = %+v, %v; want bio from fixture", profile, err)
+	}
+
+	// Seeding the same users twice fails instead of duplicating them
+	if _, err := Apply(ctx, services, fixture); err == nil {
+		t.Error("Expected re-applying the fixture to fail on existing users")
+	}
+}
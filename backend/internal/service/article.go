@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
@@ -10,22 +12,62 @@ import (
 
 // ArticleService handles article business logic
 type ArticleService struct {
-	articleRepo *repository.ArticleRepository
-	userRepo    *repository.UserRepository
+	uow         repository.Transactor
+	articleRepo repository.ArticleStore
+	userRepo    repository.UserStore
 	tagService  *TagService
+
+	// scheduled wakes the publisher when an article is scheduled
+	scheduled chan struct{}
 }
 
 // NewArticleService creates a new article service
-func NewArticleService(articleRepo *repository.ArticleRepository, userRepo *repository.UserRepository, tagService *TagService) *ArticleService {
+func NewArticleService(uow repository.Transactor, articleRepo repository.ArticleStore, userRepo repository.UserStore, tagService *TagService) *ArticleService {
 	return &ArticleService{
+		uow:         uow,
 		articleRepo: articleRepo,
 		userRepo:    userRepo,
 		tagService:  tagService,
+		scheduled:   make(chan struct{}, 1),
+	}
+}
+
+// visibleTo reports whether a user may see an article. Drafts and
+// scheduled articles are only visible to their author.
+func visibleTo(article *model.Article, userID int) bool {
+	return article.Status == model.ArticlePublished || article.AuthorID == userID
+}
+
+// publication validates the requested status and publish time of an
+// article and returns the values to store. Published articles record now
+// as their publication time.
+func publication(status string, publishAt *time.Time, now time.Time) (string, *time.Time, error) {
+	switch status {
+	case model.ArticlePublished, model.ArticleDraft:
+		if publishAt != nil {
+			return "", nil, fmt.Errorf("publishAt is only allowed for scheduled articles")
+		}
+		if status == model.ArticleDraft {
+			return status, nil, nil
+		}
+		return status, &now, nil
+	case model.ArticleScheduled:
+		if publishAt == nil {
+			return "", nil, fmt.Errorf("publishAt is required for scheduled articles")
+		}
+		if !publishAt.After(now) {
+			return "", nil, fmt.Errorf("publishAt must be in the future")
+		}
+		// Stored in local time like the other timestamps
+		at := publishAt.Local()
+		return status, &at, nil
+	default:
+		return "", nil, fmt.Errorf("status must be draft, scheduled or published")
 	}
 }
 
 // CreateArticle creates a new article
-func (s *ArticleService) CreateArticle(req model.CreateArticleRequest, authorID int) (*model.ArticleResponse, error) {
+func (s *ArticleService) CreateArticle(ctx context.Context, req model.CreateArticleRequest, authorID int) (*model.ArticleResponse, error) {
 	// Validate input
 	if req.Article.Title == "" {
 		return nil, fmt.Errorf("title is required")
@@ -37,6 +79,15 @@ func (s *ArticleService) CreateArticle(req model.CreateArticleRequest, authorID
 		return nil, fmt.Errorf("body is required")
 	}
 
+	status := req.Article.Status
+	if status == "" {
+		status = model.ArticlePublished
+	}
+	status, publishAt, err := publication(status, req.Article.PublishAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate unique slug
 	slug := utils.GenerateSlug(req.Article.Title)
 
@@ -47,48 +98,71 @@ func (s *ArticleService) CreateArticle(req model.CreateArticleRequest, authorID
 		Description: req.Article.Description,
 		Body:        req.Article.Body,
 		AuthorID:    authorID,
+		Status:      status,
+		PublishAt:   publishAt,
 	}
 
-	err := s.articleRepo.Create(article)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create article: %w", err)
-	}
+	// Create the article and its tags together so a failure leaves neither
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Articles.Create(ctx, article); err != nil {
+			return fmt.Errorf("failed to create article: %w", err)
+		}
 
-	// Set tags if provided
-	if len(req.Article.TagList) > 0 {
-		err = s.tagService.CreateTagsForArticle(article.ID, req.Article.TagList)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create article tags: %w", err)
+		if len(req.Article.TagList) > 0 {
+			if err := repos.Tags.CreateTagsForArticle(ctx, article.ID, cleanTags(req.Article.TagList)); err != nil {
+				return fmt.Errorf("failed to create article tags: %w", err)
+			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status == model.ArticleScheduled {
+		s.wakePublisher()
 	}
 
 	// Build response
-	return s.buildArticleResponse(article, authorID)
+	return s.buildArticleResponse(ctx, article, authorID)
 }
 
 // GetArticleBySlug retrieves an article by slug
-func (s *ArticleService) GetArticleBySlug(slug string, currentUserID int) (*model.ArticleResponse, error) {
-	article, err := s.articleRepo.GetBySlug(slug)
-	if err != nil {
-		return nil, err
-	}
+func (s *ArticleService) GetArticleBySlug(ctx context.Context, slug string, currentUserID int) (*model.ArticleResponse, error) {
+	return s.getArticle(ctx, slug, currentUserID, false)
+}
 
-	return s.buildArticleResponse(article, currentUserID)
+// GetRenderedArticleBySlug retrieves an article by slug along with its body
+// rendered to sanitized HTML and its excerpt
+func (s *ArticleService) GetRenderedArticleBySlug(ctx context.Context, slug string, currentUserID int) (*model.ArticleResponse, error) {
+	return s.getArticle(ctx, slug, currentUserID, true)
 }
 
-// UpdateArticle updates an existing article
-func (s *ArticleService) UpdateArticle(slug string, req model.UpdateArticleRequest, currentUserID int) (*model.ArticleResponse, error) {
-	// Get existing article to check ownership
-	article, err := s.articleRepo.GetBySlug(slug)
+// getArticle retrieves an article by slug, rendering its body if asked to
+func (s *ArticleService) getArticle(ctx context.Context, slug string, currentUserID int, bodyHTML bool) (*model.ArticleResponse, error) {
+	article, err := s.articleRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
+	if !visibleTo(article, currentUserID) {
+		return nil, fmt.Errorf("article not found")
+	}
 
-	// Check if current user is the author
-	if article.AuthorID != currentUserID {
-		return nil, fmt.Errorf("unauthorized: you can only update your own articles")
+	responses, err := s.buildArticleResponses(ctx, []model.Article{*article}, currentUserID)
+	if err != nil {
+		return nil, err
 	}
+	if bodyHTML {
+		if err := s.renderBodies(ctx, []model.Article{*article}, responses); err != nil {
+			return nil, err
+		}
+	}
+
+	return &responses[0], nil
+}
 
+// UpdateArticle updates an existing article
+func (s *ArticleService) UpdateArticle(ctx context.Context, slug string, req model.UpdateArticleRequest, currentUserID int) (*model.ArticleResponse, error) {
 	// Build update map
 	updates := make(map[string]interface{})
 
@@ -115,56 +189,168 @@ func (s *ArticleService) UpdateArticle(slug string, req model.UpdateArticleReque
 		updates["body"] = *req.Article.Body
 	}
 
-	// Update article
-	updatedArticle, err := s.articleRepo.Update(slug, updates)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update article: %w", err)
-	}
+	// Check ownership, update the article and replace its tags atomically
+	var updatedArticle *model.Article
+	err := s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		article, err := repos.Articles.GetBySlug(ctx, slug)
+		if err != nil {
+			return err
+		}
+
+		// Check if current user is the author
+		if !visibleTo(article, currentUserID) {
+			return fmt.Errorf("article not found")
+		}
+		if article.AuthorID != currentUserID {
+			return fmt.Errorf("unauthorized: you can only update your own articles")
+		}
+
+		// Publish, schedule or unschedule the article
+		if req.Article.Status != nil || req.Article.PublishAt != nil {
+			status := article.Status
+			if req.Article.Status != nil {
+				status = *req.Article.Status
+			}
+			status, publishAt, err := publication(status, req.Article.PublishAt, time.Now())
+			if err != nil {
+				return err
+			}
+			switch {
+			case article.Status == model.ArticlePublished && status != model.ArticlePublished:
+				return fmt.Errorf("published articles cannot be unpublished")
+			case article.Status != model.ArticlePublished:
+				// Already published articles keep their publication time
+				updates["status"] = status
+				updates["publish_at"] = publishAt
+			}
+		}
 
-	// Update tags if provided
-	if req.Article.TagList != nil {
-		err = s.tagService.UpdateTagsForArticle(updatedArticle.ID, req.Article.TagList)
+		// Keep the version this update replaces
+		tags, err := repos.Tags.GetTagsForArticle(ctx, article.ID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update article tags: %w", err)
+			return fmt.Errorf("failed to get article tags: %w", err)
+		}
+		revision := &model.ArticleRevision{
+			ArticleID:   article.ID,
+			EditorID:    currentUserID,
+			Title:       article.Title,
+			Description: article.Description,
+			Body:        article.Body,
+			TagList:     tags,
+		}
+		if err := repos.Articles.CreateRevision(ctx, revision); err != nil {
+			return fmt.Errorf("failed to store revision: %w", err)
+		}
+
+		updatedArticle, err = repos.Articles.Update(ctx, slug, updates)
+		if err != nil {
+			return fmt.Errorf("failed to update article: %w", err)
 		}
-	}
 
-	// If slug was updated, use the new slug
-	finalSlug := slug
-	if newSlug, ok := updates["slug"]; ok {
-		finalSlug = newSlug.(string)
+		// Update tags if provided
+		if req.Article.TagList != nil {
+			if err := repos.Tags.UpdateTagsForArticle(ctx, updatedArticle.ID, cleanTags(req.Article.TagList)); err != nil {
+				return fmt.Errorf("failed to update article tags: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updatedArticle.Status == model.ArticleScheduled {
+		s.wakePublisher()
 	}
 
-	return s.GetArticleBySlug(finalSlug, currentUserID)
+	return s.GetArticleBySlug(ctx, updatedArticle.Slug, currentUserID)
 }
 
 // DeleteArticle deletes an article
-func (s *ArticleService) DeleteArticle(slug string, currentUserID int) error {
-	// Get existing article to check ownership
-	article, err := s.articleRepo.GetBySlug(slug)
-	if err != nil {
-		return err
-	}
+func (s *ArticleService) DeleteArticle(ctx context.Context, slug string, currentUserID int) error {
+	// Check ownership and delete in one transaction so the article cannot
+	// change hands in between
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		article, err := repos.Articles.GetBySlug(ctx, slug)
+		if err != nil {
+			return err
+		}
 
-	// Check if current user is the author
-	if article.AuthorID != currentUserID {
-		return fmt.Errorf("unauthorized: you can only delete your own articles")
-	}
+		// Check if current user is the author
+		if !visibleTo(article, currentUserID) {
+			return fmt.Errorf("article not found")
+		}
+		if article.AuthorID != currentUserID {
+			return fmt.Errorf("unauthorized: you can only delete your own articles")
+		}
 
-	return s.articleRepo.Delete(slug)
+		return repos.Articles.Delete(ctx, slug)
+	})
 }
 
 // ArticleListParams represents parameters for listing articles
 type ArticleListParams struct {
-	Limit     int
-	Offset    int
-	Tag       string
-	Author    string
-	Favorited string
+	Limit       int
+	Offset      int
+	Cursor      string   // opaque cursor from a previous page; replaces Offset
+	Sort        string   // one of the repository sorts; newest first when empty
+	Tags        []string // articles with any of these tags
+	TagMode     string   // "any" (default) or "all" of Tags
+	ExcludeTags []string
+	Authors     []string
+	Favorited   string
+	Since       time.Time // created at or after; zero for no bound
+	Until       time.Time // created before; zero for no bound
+	BodyHTML    bool      // include the rendered body and excerpt
+}
+
+// filter validates the filter parameters and returns the repository filter
+func (p ArticleListParams) filter() (repository.ArticleFilter, error) {
+	filter := repository.ArticleFilter{
+		Tags:        p.Tags,
+		ExcludeTags: p.ExcludeTags,
+		Authors:     p.Authors,
+		Favorited:   p.Favorited,
+		Since:       p.Since,
+		Until:       p.Until,
+	}
+
+	switch p.TagMode {
+	case "", "any":
+	case "all":
+		filter.MatchAllTags = true
+	default:
+		return filter, fmt.Errorf("tag mode must be any or all")
+	}
+
+	if !p.Since.IsZero() && !p.Until.IsZero() && !p.Since.Before(p.Until) {
+		return filter, fmt.Errorf("since must be before until")
+	}
+
+	return filter, nil
 }
 
 // GetArticles retrieves a list of articles with filtering and pagination
-func (s *ArticleService) GetArticles(params ArticleListParams, currentUserID int) (*model.ArticlesResponse, error) {
+func (s *ArticleService) GetArticles(ctx context.Context, params ArticleListParams, currentUserID int) (*model.ArticlesResponse, error) {
+	// Set default limit
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100 // Max limit
+	}
+
+	filter, err := params.filter()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listArticles(ctx, params, filter, currentUserID)
+}
+
+// GetDrafts retrieves the current user's drafts and scheduled articles,
+// newest first
+func (s *ArticleService) GetDrafts(ctx context.Context, params ArticleListParams, currentUserID int) (*model.ArticlesResponse, error) {
 	// Set default limit
 	if params.Limit <= 0 {
 		params.Limit = 20
@@ -173,30 +359,45 @@ func (s *ArticleService) GetArticles(params ArticleListParams, currentUserID int
 		params.Limit = 100 // Max limit
 	}
 
+	filter := repository.ArticleFilter{AuthorID: currentUserID, Unpublished: true}
+	return s.listArticles(ctx, params, filter, currentUserID)
+}
+
+// listArticles retrieves a page of the articles matching filter
+func (s *ArticleService) listArticles(ctx context.Context, params ArticleListParams, filter repository.ArticleFilter, currentUserID int) (*model.ArticlesResponse, error) {
+	page, err := listPage(params)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get articles from repository
-	articles, totalCount, err := s.articleRepo.GetArticles(params.Limit, params.Offset, params.Tag, params.Author, params.Favorited)
+	articles, totalCount, err := s.articleRepo.GetArticles(ctx, page, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get articles: %w", err)
 	}
+	articles, next, prev := trimPage(articles, page, params.Limit)
 
 	// Build article responses
-	articleResponses := make([]model.ArticleResponse, 0, len(articles))
-	for _, article := range articles {
-		articleResponse, err := s.buildArticleResponse(&article, currentUserID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build article response: %w", err)
+	articleResponses, err := s.buildArticleResponses(ctx, articles, currentUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build article responses: %w", err)
+	}
+	if params.BodyHTML {
+		if err := s.renderBodies(ctx, articles, articleResponses); err != nil {
+			return nil, err
 		}
-		articleResponses = append(articleResponses, *articleResponse)
 	}
 
 	return &model.ArticlesResponse{
 		Articles:      articleResponses,
 		ArticlesCount: totalCount,
+		NextCursor:    next,
+		PrevCursor:    prev,
 	}, nil
 }
 
 // GetArticlesFeed retrieves user's personalized feed of articles
-func (s *ArticleService) GetArticlesFeed(params ArticleListParams, currentUserID int) (*model.ArticlesResponse, error) {
+func (s *ArticleService) GetArticlesFeed(ctx context.Context, params ArticleListParams, currentUserID int) (*model.ArticlesResponse, error) {
 	// Set default limit
 	if params.Limit <= 0 {
 		params.Limit = 10
@@ -205,132 +406,233 @@ func (s *ArticleService) GetArticlesFeed(params ArticleListParams, currentUserID
 		params.Limit = 100 // Max limit
 	}
 
+	page, err := listPage(params)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get feed articles from repository (articles from followed users)
-	articles, totalCount, err := s.articleRepo.GetFeedArticles(params.Limit, params.Offset, currentUserID)
+	articles, totalCount, err := s.articleRepo.GetFeedArticles(ctx, page, currentUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get feed articles: %w", err)
 	}
+	articles, next, prev := trimPage(articles, page, params.Limit)
 
 	// Build article responses
-	articleResponses := make([]model.ArticleResponse, 0, len(articles))
-	for _, article := range articles {
-		articleResponse, err := s.buildArticleResponse(&article, currentUserID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build article response: %w", err)
+	articleResponses, err := s.buildArticleResponses(ctx, articles, currentUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build article responses: %w", err)
+	}
+	if params.BodyHTML {
+		if err := s.renderBodies(ctx, articles, articleResponses); err != nil {
+			return nil, err
 		}
-		articleResponses = append(articleResponses, *articleResponse)
 	}
 
 	return &model.ArticlesResponse{
 		Articles:      articleResponses,
 		ArticlesCount: totalCount,
+		NextCursor:    next,
+		PrevCursor:    prev,
 	}, nil
 }
 
-// buildArticleResponse builds an article response with author information
-func (s *ArticleService) buildArticleResponse(article *model.Article, currentUserID int) (*model.ArticleResponse, error) {
-	// Get author information
-	author, err := s.userRepo.GetByID(article.AuthorID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get author: %w", err)
+// SearchArticles retrieves the articles matching a full-text query, best
+// match first
+func (s *ArticleService) SearchArticles(ctx context.Context, query string, params ArticleListParams, currentUserID int) (*model.ArticlesResponse, error) {
+	// Set default limit
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100 // Max limit
 	}
 
-	// Get article tags
-	tags, err := s.tagService.GetTagsForArticle(article.ID)
+	results, totalCount, err := s.articleRepo.SearchArticles(ctx, query, params.Limit, params.Offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get article tags: %w", err)
+		return nil, fmt.Errorf("failed to search articles: %w", err)
 	}
 
-	// TODO: Implement following check
-	// For now, set to false
-	following := false
+	// Build article responses
+	articles := make([]model.Article, 0, len(results))
+	for _, result := range results {
+		articles = append(articles, result.Article)
+	}
+	articleResponses, err := s.buildArticleResponses(ctx, articles, currentUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build article responses: %w", err)
+	}
+	for i, result := range results {
+		articleResponses[i].Snippet = result.Snippet
+	}
+	if params.BodyHTML {
+		if err := s.renderBodies(ctx, articles, articleResponses); err != nil {
+			return nil, err
+		}
+	}
 
-	// Check if current user has favorited this article
-	favorited := false
-	if currentUserID > 0 {
-		var err error
-		favorited, err = s.articleRepo.IsFavorited(currentUserID, article.ID)
-		if err != nil {
-			// Log error but don't fail the whole response
-			favorited = false
-		}
-	}
-
-	return &model.ArticleResponse{
-		Slug:           article.Slug,
-		Title:          article.Title,
-		Description:    article.Description,
-		Body:           article.Body,
-		TagList:        tags,
-		CreatedAt:      article.CreatedAt,
-		UpdatedAt:      article.UpdatedAt,
-		Favorited:      favorited,
-		FavoritesCount: article.FavoritesCount,
-		Author: model.AuthorProfile{
-			Username:  author.Username,
-			Bio:       author.Bio,
-			Image:     author.Image,
-			Following: following,
-		},
+	return &model.ArticlesResponse{
+		Articles:      articleResponses,
+		ArticlesCount: totalCount,
 	}, nil
 }
 
-// FavoriteArticle adds an article to user's favorites
-func (s *ArticleService) FavoriteArticle(slug string, userID int) (*model.ArticleResponse, error) {
-	// Get article by slug
-	article, err := s.articleRepo.GetBySlug(slug)
+// buildArticleResponse builds an article response with author information
+func (s *ArticleService) buildArticleResponse(ctx context.Context, article *model.Article, currentUserID int) (*model.ArticleResponse, error) {
+	responses, err := s.buildArticleResponses(ctx, []model.Article{*article}, currentUserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get article: %w", err)
+		return nil, err
 	}
+	return &responses[0], nil
+}
 
-	// Check if already favorited
-	isFavorited, err := s.articleRepo.IsFavorited(userID, article.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check favorite status: %w", err)
+// buildArticleResponses builds the responses for a list of articles. The
+// authors, tags, favorited and following flags of the whole list are loaded
+// in one query each, however long the list is.
+func (s *ArticleService) buildArticleResponses(ctx context.Context, articles []model.Article, currentUserID int) ([]model.ArticleResponse, error) {
+	articleIDs := make([]int, 0, len(articles))
+	authorIDs := make([]int, 0, len(articles))
+	seenAuthors := map[int]bool{}
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ID)
+		if !seenAuthors[article.AuthorID] {
+			seenAuthors[article.AuthorID] = true
+			authorIDs = append(authorIDs, article.AuthorID)
+		}
 	}
 
-	if isFavorited {
-		return nil, fmt.Errorf("article already favorited")
+	// Get author information
+	authors, err := s.userRepo.GetByIDs(ctx, authorIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authors: %w", err)
 	}
 
-	// Add to favorites
-	err = s.articleRepo.FavoriteArticle(userID, article.ID)
+	// Get article tags
+	tags, err := s.tagService.GetTagsForArticles(ctx, articleIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to favorite article: %w", err)
+		return nil, fmt.Errorf("failed to get article tags: %w", err)
 	}
 
-	// Get updated favorites count
-	favoritesCount, err := s.articleRepo.GetFavoritesCount(article.ID)
+	// Check which articles the current user has favorited and which
+	// authors they follow
+	favorited := map[int]bool{}
+	following := map[int]bool{}
+	if currentUserID > 0 {
+		if favorited, err = s.articleRepo.GetFavoritedIDs(ctx, currentUserID, articleIDs); err != nil {
+			return nil, fmt.Errorf("failed to get favorited status: %w", err)
+		}
+		if following, err = s.userRepo.GetFollowedIDs(ctx, currentUserID, authorIDs); err != nil {
+			return nil, fmt.Errorf("failed to get following status: %w", err)
+		}
+	}
+
+	responses := make([]model.ArticleResponse, 0, len(articles))
+	for _, article := range articles {
+		author, ok := authors[article.AuthorID]
+		if !ok {
+			return nil, fmt.Errorf("failed to get author: user not found")
+		}
+
+		responses = append(responses, model.ArticleResponse{
+			Slug:           article.Slug,
+			Title:          article.Title,
+			Description:    article.Description,
+			Body:           article.Body,
+			TagList:        tags[article.ID],
+			CreatedAt:      article.CreatedAt,
+			UpdatedAt:      article.UpdatedAt,
+			Favorited:      favorited[article.ID],
+			FavoritesCount: article.FavoritesCount,
+			Author: model.AuthorProfile{
+				Username:  author.Username,
+				Bio:       author.Bio,
+				Image:     author.Image,
+				Following: following[article.AuthorID],
+			},
+			Status:    article.Status,
+			PublishAt: article.PublishAt,
+		})
+	}
+
+	return responses, nil
+}
+
+// FavoriteArticle adds an article to user's favorites
+func (s *ArticleService) FavoriteArticle(ctx context.Context, slug string, userID int) (*model.ArticleResponse, error) {
+	var article *model.Article
+	err := s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		// Get article by slug
+		var err error
+		article, err = repos.Articles.GetBySlug(ctx, slug)
+		if err != nil {
+			return fmt.Errorf("failed to get article: %w", err)
+		}
+		if !visibleTo(article, userID) {
+			return fmt.Errorf("failed to get article: article not found")
+		}
+
+		// Check if already favorited
+		isFavorited, err := repos.Articles.IsFavorited(ctx, userID, article.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check favorite status: %w", err)
+		}
+
+		if isFavorited {
+			return fmt.Errorf("article already favorited")
+		}
+
+		// Add to favorites
+		if err := repos.Articles.FavoriteArticle(ctx, userID, article.ID); err != nil {
+			return fmt.Errorf("failed to favorite article: %w", err)
+		}
+
+		// Get updated favorites count
+		article.FavoritesCount, err = repos.Articles.GetFavoritesCount(ctx, article.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get favorites count: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get favorites count: %w", err)
+		return nil, err
 	}
-	article.FavoritesCount = favoritesCount
 
 	// Build and return article response
-	return s.buildArticleResponse(article, userID)
+	return s.buildArticleResponse(ctx, article, userID)
 }
 
 // UnfavoriteArticle removes an article from user's favorites
-func (s *ArticleService) UnfavoriteArticle(slug string, userID int) (*model.ArticleResponse, error) {
-	// Get article by slug
-	article, err := s.articleRepo.GetBySlug(slug)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get article: %w", err)
-	}
+func (s *ArticleService) UnfavoriteArticle(ctx context.Context, slug string, userID int) (*model.ArticleResponse, error) {
+	var article *model.Article
+	err := s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		// Get article by slug
+		var err error
+		article, err = repos.Articles.GetBySlug(ctx, slug)
+		if err != nil {
+			return fmt.Errorf("failed to get article: %w", err)
+		}
+		if !visibleTo(article, userID) {
+			return fmt.Errorf("failed to get article: article not found")
+		}
 
-	// Remove from favorites
-	err = s.articleRepo.UnfavoriteArticle(userID, article.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unfavorite article: %w", err)
-	}
+		// Remove from favorites
+		if err := repos.Articles.UnfavoriteArticle(ctx, userID, article.ID); err != nil {
+			return fmt.Errorf("failed to unfavorite article: %w", err)
+		}
 
-	// Get updated favorites count
-	favoritesCount, err := s.articleRepo.GetFavoritesCount(article.ID)
+		// Get updated favorites count
+		article.FavoritesCount, err = repos.Articles.GetFavoritesCount(ctx, article.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get favorites count: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get favorites count: %w", err)
+		return nil, err
 	}
-	article.FavoritesCount = favoritesCount
 
 	// Build and return article response
-	return s.buildArticleResponse(article, userID)
+	return s.buildArticleResponse(ctx, article, userID)
 }
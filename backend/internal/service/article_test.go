@@ -0,0 +1,874 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/db"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/migrations"
+)
+
+// newTestArticleService opens a migrated temporary database and wires an
+// article service to it. The statements it runs are recorded in the
+// database's query stats.
+func newTestArticleService(t testing.TB) (*ArticleService, *db.Database) {
+	t.Helper()
+
+	database, err := db.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), db.SQLite)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := db.NewMigrationManager(database.DB, db.SQLite).RunMigrations(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	database.SetQueryStats(db.NewQueryStats(0))
+
+	userRepo := repository.NewUserRepository(database)
+	tagService := NewTagService(repository.NewTagRepository(database))
+	return NewArticleService(repository.NewUnitOfWork(database), repository.NewArticleRepository(database), userRepo, tagService), database
+}
+
+// createTestUser inserts a user directly and returns its ID
+func createTestUser(t testing.TB, database *db.Database, username string) int {
+	t.Helper()
+
+	user := &model.User{Email: username + "@example.com", Username: username, PasswordHash: "hash"}
+	if err := repository.NewUserRepository(database).Create(context.Background(), user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	return user.ID
+}
+
+// failInserts makes every insert into table fail from now on
+func failInserts(t *testing.T, database *db.Database, table string) {
+	t.Helper()
+
+	trigger := "CREATE TRIGGER fail_" + table + " BEFORE INSERT ON " + table +
+		" BEGIN SELECT RAISE(ABORT, 'injected " + table + " failure'); END"
+	if _, err := database.Exec(trigger); err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+}
+
+// countRows returns the number of rows in table
+func countRows(t *testing.T, database *db.Database, table string) int {
+	t.Helper()
+
+	var count int
+	if err := database.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+		t.Fatalf("Failed to count %s: %v", table, err)
+	}
+	return count
+}
+
+// newArticleRequest builds a create request with the given title and tags
+func newArticleRequest(title string, tags ...string) model.CreateArticleRequest {
+	var req model.CreateArticleRequest
+	req.Article.Title = title
+	req.Article.Description = "Ever wonder how?"
+	req.Article.Body = "You have to believe"
+	req.Article.TagList = tags
+	return req
+}
+
+func TestCreateArticleRollsBackOnTagFailure(t *testing.T) {
+	ctx := context.Background()
+	articleService, database := newTestArticleService(t)
+	authorID := createTestUser(t, database, "author")
+
+	failInserts(t, database, "tags")
+
+	_, err := articleService.CreateArticle(ctx, newArticleRequest("Tagless", "dragons"), authorID)
+	if err == nil || !strings.Contains(err.Error(), "injected tags failure") {
+		t.Fatalf("CreateArticle() error = %v, want injected failure", err)
+	}
+
+	if got := countRows(t, database, "articles"); got != 0 {
+		t.Errorf("articles after failed create = %d, want 0", got)
+	}
+}
+
+func TestUpdateArticleRollsBackOnTagFailure(t *testing.T) {
+	ctx := context.Background()
+	articleService, database := newTestArticleService(t)
+	authorID := createTestUser(t, database, "author")
+
+	article, err := articleService.CreateArticle(ctx, newArticleRequest("Original", "dragons"), authorID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	failInserts(t, database, "article_tags")
+
+	title := "Renamed"
+	var req model.UpdateArticleRequest
+	req.Article.Title = &title
+	req.Article.TagList = []string{"training"}
+
+	_, err = articleService.UpdateArticle(ctx, article.Slug, req, authorID)
+	if err == nil || !strings.Contains(err.Error(), "injected article_tags failure") {
+		t.Fatalf("UpdateArticle() error = %v, want injected failure", err)
+	}
+
+	// Neither the new title and slug nor the removal of the old tags stuck
+	unchanged, err := articleService.GetArticleBySlug(ctx, article.Slug, 0)
+	if err != nil {
+		t.Fatalf("GetArticleBySlug() with original slug error = %v", err)
+	}
+	if unchanged.Title != "Original" || !reflect.DeepEqual(unchanged.TagList, []string{"dragons"}) {
+		t.Errorf("article after failed update = %+v, want original title and tags", unchanged)
+	}
+}
+
+func TestUpdateArticleRenamesSlug(t *testing.T) {
+	ctx := context.Background()
+	articleService, database := newTestArticleService(t)
+	authorID := createTestUser(t, database, "author")
+
+	article, err := articleService.CreateArticle(ctx, newArticleRequest("Original"), authorID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	title := "Renamed"
+	var req model.UpdateArticleRequest
+	req.Article.Title = &title
+
+	updated, err := articleService.UpdateArticle(ctx, article.Slug, req, authorID)
+	if err != nil {
+		t.Fatalf("UpdateArticle() error = %v", err)
+	}
+	if updated.Title != "Renamed" || updated.Slug == article.Slug || !strings.HasPrefix(updated.Slug, "renamed") {
+		t.Errorf("UpdateArticle() = %+v, want renamed article with a new slug", updated)
+	}
+
+	if _, err := articleService.GetArticleBySlug(ctx, article.Slug, 0); err == nil {
+		t.Error("Expected the old slug to be gone")
+	}
+}
+
+func TestArticleChangesRequireAuthor(t *testing.T) {
+	ctx := context.Background()
+	articleService, database := newTestArticleService(t)
+	authorID := createTestUser(t, database, "author")
+	otherID := createTestUser(t, database, "other")
+
+	article, err := articleService.CreateArticle(ctx, newArticleRequest("Mine", "dragons"), authorID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	title := "Stolen"
+	var req model.UpdateArticleRequest
+	req.Article.Title = &title
+	if _, err := articleService.UpdateArticle(ctx, article.Slug, req, otherID); err == nil || !strings.HasPrefix(err.Error(), "unauthorized") {
+		t.Errorf("UpdateArticle() by another user error = %v, want unauthorized", err)
+	}
+	if err := articleService.DeleteArticle(ctx, article.Slug, otherID); err == nil || !strings.HasPrefix(err.Error(), "unauthorized") {
+		t.Errorf("DeleteArticle() by another user error = %v, want unauthorized", err)
+	}
+
+	if err := articleService.DeleteArticle(ctx, article.Slug, authorID); err != nil {
+		t.Fatalf("DeleteArticle() error = %v", err)
+	}
+	if got := countRows(t, database, "article_tags"); got != 0 {
+		t.Errorf("article_tags after delete = %d, want 0", got)
+	}
+}
+
+func TestCreateArticleValidation(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+
+	tests := []struct {
+		name    string
+		mutate  func(req *model.CreateArticleRequest)
+		wantErr string
+	}{
+		{"missing title", func(req *model.CreateArticleRequest) { req.Article.Title = "" }, "title is required"},
+		{"missing description", func(req *model.CreateArticleRequest) { req.Article.Description = "" }, "description is required"},
+		{"missing body", func(req *model.CreateArticleRequest) { req.Article.Body = "" }, "body is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newArticleRequest("How to train your dragon")
+			tt.mutate(&req)
+
+			if _, err := services.Articles.CreateArticle(ctx, req, jake.ID); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("CreateArticle() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFavoriteArticle(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+
+	article, err := services.Articles.CreateArticle(ctx, newArticleRequest("How to train your dragon", "dragons"), jake.ID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	favorited, err := services.Articles.FavoriteArticle(ctx, article.Slug, celeb.ID)
+	if err != nil || !favorited.Favorited || favorited.FavoritesCount != 1 {
+		t.Fatalf("FavoriteArticle() = %+v, %v; want favorited once", favorited, err)
+	}
+	if _, err := services.Articles.FavoriteArticle(ctx, article.Slug, celeb.ID); err == nil || err.Error() != "article already favorited" {
+		t.Errorf("FavoriteArticle() twice error = %v, want article already favorited", err)
+	}
+
+	list, err := services.Articles.GetArticles(ctx, ArticleListParams{Favorited: "celeb"}, celeb.ID)
+	if err != nil || list.ArticlesCount != 1 || !list.Articles[0].Favorited {
+		t.Errorf("GetArticles(favorited=celeb) = %+v, %v; want the favorited article", list, err)
+	}
+
+	unfavorited, err := services.Articles.UnfavoriteArticle(ctx, article.Slug, celeb.ID)
+	if err != nil || unfavorited.Favorited || unfavorited.FavoritesCount != 0 {
+		t.Errorf("UnfavoriteArticle() = %+v, %v; want not favorited", unfavorited, err)
+	}
+}
+
+func TestArticlePublicationValidation(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	tests := []struct {
+		name      string
+		status    string
+		publishAt *time.Time
+		wantErr   string
+	}{
+		{"unknown status", "archived", nil, "status must be draft, scheduled or published"},
+		{"draft with time", model.ArticleDraft, &future, "publishAt is only allowed for scheduled articles"},
+		{"published with time", "", &future, "publishAt is only allowed for scheduled articles"},
+		{"scheduled without time", model.ArticleScheduled, nil, "publishAt is required for scheduled articles"},
+		{"scheduled in the past", model.ArticleScheduled, &past, "publishAt must be in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newArticleRequest("How to train your dragon")
+			req.Article.Status = tt.status
+			req.Article.PublishAt = tt.publishAt
+
+			if _, err := services.Articles.CreateArticle(ctx, req, jake.ID); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("CreateArticle() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArticleDrafts(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+	if _, err := services.Profiles.FollowUser(ctx, celeb.ID, "jake"); err != nil {
+		t.Fatalf("FollowUser() error = %v", err)
+	}
+
+	req := newArticleRequest("Secret dragons", "dragons")
+	req.Article.Status = model.ArticleDraft
+	draft, err := services.Articles.CreateArticle(ctx, req, jake.ID)
+	if err != nil || draft.Status != model.ArticleDraft || draft.PublishAt != nil {
+		t.Fatalf("CreateArticle(draft) = %+v, %v; want an unscheduled draft", draft, err)
+	}
+
+	// Only the author can see the draft
+	if _, err := services.Articles.GetArticleBySlug(ctx, draft.Slug, jake.ID); err != nil {
+		t.Errorf("GetArticleBySlug() by author error = %v", err)
+	}
+	for _, userID := range []int{celeb.ID, 0} {
+		if _, err := services.Articles.GetArticleBySlug(ctx, draft.Slug, userID); err == nil || err.Error() != "article not found" {
+			t.Errorf("GetArticleBySlug() by %d error = %v, want article not found", userID, err)
+		}
+	}
+	if _, err := services.Articles.FavoriteArticle(ctx, draft.Slug, celeb.ID); err == nil || !strings.HasSuffix(err.Error(), "article not found") {
+		t.Errorf("FavoriteArticle() of another user's draft error = %v, want article not found", err)
+	}
+	if err := services.Articles.DeleteArticle(ctx, draft.Slug, celeb.ID); err == nil || err.Error() != "article not found" {
+		t.Errorf("DeleteArticle() of another user's draft error = %v, want article not found", err)
+	}
+
+	list, err := services.Articles.GetArticles(ctx, ArticleListParams{}, jake.ID)
+	if err != nil || list.ArticlesCount != 0 {
+		t.Errorf("GetArticles() = %+v, %v; want the draft hidden", list, err)
+	}
+	feed, err := services.Articles.GetArticlesFeed(ctx, ArticleListParams{}, celeb.ID)
+	if err != nil || feed.ArticlesCount != 0 {
+		t.Errorf("GetArticlesFeed() = %+v, %v; want the draft hidden", feed, err)
+	}
+	if tags, err := services.Tags.GetAllTags(ctx); err != nil || len(tags) != 0 {
+		t.Errorf("GetAllTags() = %v, %v; want the draft's tags hidden", tags, err)
+	}
+
+	drafts, err := services.Articles.GetDrafts(ctx, ArticleListParams{}, jake.ID)
+	if err != nil || drafts.ArticlesCount != 1 || drafts.Articles[0].Slug != draft.Slug {
+		t.Errorf("GetDrafts() = %+v, %v; want the draft", drafts, err)
+	}
+	if drafts, err := services.Articles.GetDrafts(ctx, ArticleListParams{}, celeb.ID); err != nil || drafts.ArticlesCount != 0 {
+		t.Errorf("GetDrafts() of another user = %+v, %v; want none", drafts, err)
+	}
+
+	// Schedule it, then publish it right away
+	publishAt := time.Now().Add(time.Hour)
+	scheduled := model.ArticleScheduled
+	var update model.UpdateArticleRequest
+	update.Article.Status = &scheduled
+	update.Article.PublishAt = &publishAt
+	updated, err := services.Articles.UpdateArticle(ctx, draft.Slug, update, jake.ID)
+	if err != nil || updated.Status != model.ArticleScheduled || updated.PublishAt == nil || !updated.PublishAt.Equal(publishAt) {
+		t.Fatalf("UpdateArticle(scheduled) = %+v, %v; want scheduled at %v", updated, err, publishAt)
+	}
+
+	published := model.ArticlePublished
+	update.Article.Status = &published
+	update.Article.PublishAt = nil
+	updated, err = services.Articles.UpdateArticle(ctx, draft.Slug, update, jake.ID)
+	if err != nil || updated.Status != model.ArticlePublished || updated.PublishAt == nil || updated.PublishAt.After(time.Now()) {
+		t.Fatalf("UpdateArticle(published) = %+v, %v; want published now", updated, err)
+	}
+	if _, err := services.Articles.GetArticleBySlug(ctx, draft.Slug, celeb.ID); err != nil {
+		t.Errorf("GetArticleBySlug() of published article error = %v", err)
+	}
+	if feed, _ := services.Articles.GetArticlesFeed(ctx, ArticleListParams{}, celeb.ID); feed.ArticlesCount != 1 {
+		t.Errorf("GetArticlesFeed() count = %d, want the published article", feed.ArticlesCount)
+	}
+
+	draftStatus := model.ArticleDraft
+	update.Article.Status = &draftStatus
+	if _, err := services.Articles.UpdateArticle(ctx, draft.Slug, update, jake.ID); err == nil || err.Error() != "published articles cannot be unpublished" {
+		t.Errorf("UpdateArticle(draft) of published article error = %v, want published articles cannot be unpublished", err)
+	}
+}
+
+func TestPublishScheduled(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+
+	publishAt := time.Now().Add(time.Hour)
+	req := newArticleRequest("Dragons at dawn")
+	req.Article.Status = model.ArticleScheduled
+	req.Article.PublishAt = &publishAt
+	article, err := services.Articles.CreateArticle(ctx, req, jake.ID)
+	if err != nil {
+		t.Fatalf("CreateArticle(scheduled) error = %v", err)
+	}
+
+	published, next, err := services.Articles.PublishScheduled(ctx, time.Now())
+	if err != nil || published != 0 || !next.Equal(publishAt) {
+		t.Errorf("PublishScheduled(now) = %d, %v, %v; want 0 with next at %v", published, next, err, publishAt)
+	}
+
+	published, next, err = services.Articles.PublishScheduled(ctx, publishAt)
+	if err != nil || published != 1 || !next.IsZero() {
+		t.Errorf("PublishScheduled(publishAt) = %d, %v, %v; want 1 and nothing next", published, next, err)
+	}
+	if list, _ := services.Articles.GetArticles(ctx, ArticleListParams{}, 0); list.ArticlesCount != 1 || list.Articles[0].Slug != article.Slug {
+		t.Errorf("GetArticles() = %+v, want the published article", list)
+	}
+}
+
+func TestArticleAuthorFollowing(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+	reader := services.register(t, "reader")
+
+	followedArticle, err := services.Articles.CreateArticle(ctx, newArticleRequest("Followed"), celeb.ID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+	if _, err := services.Articles.CreateArticle(ctx, newArticleRequest("Unfollowed"), jake.ID); err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+	if _, err := services.Profiles.FollowUser(ctx, reader.ID, "celeb"); err != nil {
+		t.Fatalf("FollowUser() error = %v", err)
+	}
+
+	wantFollowing := map[string]bool{"celeb": true, "jake": false}
+	checkAuthors := func(call string, articles ...model.ArticleResponse) {
+		t.Helper()
+		for _, article := range articles {
+			if article.Author.Following != wantFollowing[article.Author.Username] {
+				t.Errorf("%s: author %s following = %v, want %v", call, article.Author.Username, article.Author.Following, wantFollowing[article.Author.Username])
+			}
+		}
+	}
+
+	article, err := services.Articles.GetArticleBySlug(ctx, followedArticle.Slug, reader.ID)
+	if err != nil {
+		t.Fatalf("GetArticleBySlug() error = %v", err)
+	}
+	checkAuthors("GetArticleBySlug()", *article)
+
+	list, err := services.Articles.GetArticles(ctx, ArticleListParams{}, reader.ID)
+	if err != nil || len(list.Articles) != 2 {
+		t.Fatalf("GetArticles() = %+v, %v; want 2 articles", list, err)
+	}
+	checkAuthors("GetArticles()", list.Articles...)
+
+	feed, err := services.Articles.GetArticlesFeed(ctx, ArticleListParams{}, reader.ID)
+	if err != nil || len(feed.Articles) != 1 {
+		t.Fatalf("GetArticlesFeed() = %+v, %v; want 1 article", feed, err)
+	}
+	checkAuthors("GetArticlesFeed()", feed.Articles...)
+
+	favorited, err := services.Articles.FavoriteArticle(ctx, followedArticle.Slug, reader.ID)
+	if err != nil {
+		t.Fatalf("FavoriteArticle() error = %v", err)
+	}
+	checkAuthors("FavoriteArticle()", *favorited)
+	unfavorited, err := services.Articles.UnfavoriteArticle(ctx, followedArticle.Slug, reader.ID)
+	if err != nil {
+		t.Fatalf("UnfavoriteArticle() error = %v", err)
+	}
+	checkAuthors("UnfavoriteArticle()", *unfavorited)
+
+	// Anonymous readers follow nobody
+	anonymous, err := services.Articles.GetArticles(ctx, ArticleListParams{}, 0)
+	if err != nil {
+		t.Fatalf("GetArticles() anonymous error = %v", err)
+	}
+	for _, article := range anonymous.Articles {
+		if article.Author.Following {
+			t.Errorf("GetArticles() anonymous: author %s following = true", article.Author.Username)
+		}
+	}
+}
+
+func TestGetArticlesAndFeed(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+
+	for _, title := range []string{"First", "Second"} {
+		if _, err := services.Articles.CreateArticle(ctx, newArticleRequest(title, "go"), celeb.ID); err != nil {
+			t.Fatalf("CreateArticle() error = %v", err)
+		}
+	}
+	if _, err := services.Articles.CreateArticle(ctx, newArticleRequest("Third", "sql"), jake.ID); err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	list, err := services.Articles.GetArticles(ctx, ArticleListParams{Tags: []string{"go"}, Limit: 1}, 0)
+	if err != nil || list.ArticlesCount != 2 || len(list.Articles) != 1 || list.Articles[0].Title != "Second" {
+		t.Errorf("GetArticles(tag=go, limit=1) = %+v, %v; want Second of 2", list, err)
+	}
+
+	feed, err := services.Articles.GetArticlesFeed(ctx, ArticleListParams{}, jake.ID)
+	if err != nil || feed.ArticlesCount != 0 {
+		t.Errorf("GetArticlesFeed() before following = %+v, %v; want empty", feed, err)
+	}
+	if _, err := services.Profiles.FollowUser(ctx, jake.ID, "celeb"); err != nil {
+		t.Fatalf("FollowUser() error = %v", err)
+	}
+	feed, err = services.Articles.GetArticlesFeed(ctx, ArticleListParams{}, jake.ID)
+	if err != nil || feed.ArticlesCount != 2 {
+		t.Errorf("GetArticlesFeed() = %+v, %v; want celeb's 2 articles", feed, err)
+	}
+}
+
+func TestArticleFilters(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+
+	for _, article := range []struct {
+		title  string
+		author int
+		tags   []string
+	}{
+		{"First", celeb.ID, []string{"go", "sql"}},
+		{"Second", celeb.ID, []string{"go"}},
+		{"Third", jake.ID, []string{"sql"}},
+	} {
+		if _, err := services.Articles.CreateArticle(ctx, newArticleRequest(article.title, article.tags...), article.author); err != nil {
+			t.Fatalf("CreateArticle() error = %v", err)
+		}
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name       string
+		params     ArticleListParams
+		wantTitles []string
+		wantErr    string
+	}{
+		{name: "any tag", params: ArticleListParams{Tags: []string{"go", "sql"}}, wantTitles: []string{"Third", "Second", "First"}},
+		{name: "all tags", params: ArticleListParams{Tags: []string{"go", "sql"}, TagMode: "all"}, wantTitles: []string{"First"}},
+		{name: "excluded tag", params: ArticleListParams{Tags: []string{"go"}, ExcludeTags: []string{"sql"}}, wantTitles: []string{"Second"}},
+		{name: "authors", params: ArticleListParams{Authors: []string{"jake", "nobody"}}, wantTitles: []string{"Third"}},
+		{name: "since", params: ArticleListParams{Since: now.Add(-time.Hour)}, wantTitles: []string{"Third", "Second", "First"}},
+		{name: "until", params: ArticleListParams{Until: now.Add(-time.Hour)}, wantTitles: []string{}},
+		{name: "invalid tag mode", params: ArticleListParams{TagMode: "some"}, wantErr: "tag mode must be any or all"},
+		{name: "empty range", params: ArticleListParams{Since: now, Until: now}, wantErr: "since must be before until"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := services.Articles.GetArticles(ctx, tt.params, 0)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("GetArticles() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetArticles() error = %v", err)
+			}
+			titles := []string{}
+			for _, article := range list.Articles {
+				titles = append(titles, article.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) || list.ArticlesCount != len(tt.wantTitles) {
+				t.Errorf("GetArticles() = %v of %d, want %v", titles, list.ArticlesCount, tt.wantTitles)
+			}
+		})
+	}
+}
+
+func TestArticleCursors(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	for _, title := range []string{"A1", "A2", "A3", "A4", "A5"} {
+		if _, err := services.Articles.CreateArticle(ctx, newArticleRequest(title), jake.ID); err != nil {
+			t.Fatalf("CreateArticle() error = %v", err)
+		}
+	}
+
+	titles := func(response *model.ArticlesResponse) []string {
+		result := []string{}
+		for _, article := range response.Articles {
+			result = append(result, article.Title)
+		}
+		return result
+	}
+
+	// Forward through every page, then back again from the last one
+	var pages [][]string
+	var last *model.ArticlesResponse
+	params := ArticleListParams{Limit: 2}
+	for {
+		page, err := services.Articles.GetArticles(ctx, params, 0)
+		if err != nil {
+			t.Fatalf("GetArticles() error = %v", err)
+		}
+		if page.ArticlesCount != 5 {
+			t.Errorf("GetArticles() count = %d, want 5 on every page", page.ArticlesCount)
+		}
+		if (len(pages) == 0) != (page.PrevCursor == "") {
+			t.Errorf("page %d prevCursor = %q, want one on every page but the first", len(pages), page.PrevCursor)
+		}
+		pages = append(pages, titles(page))
+		last = page
+		if page.NextCursor == "" {
+			break
+		}
+		params.Cursor = page.NextCursor
+	}
+	if want := [][]string{{"A5", "A4"}, {"A3", "A2"}, {"A1"}}; !reflect.DeepEqual(pages, want) {
+		t.Fatalf("forward pages = %v, want %v", pages, want)
+	}
+
+	back, err := services.Articles.GetArticles(ctx, ArticleListParams{Limit: 2, Cursor: last.PrevCursor}, 0)
+	if err != nil || !reflect.DeepEqual(titles(back), []string{"A3", "A2"}) || back.NextCursor == "" {
+		t.Fatalf("page before A1 = %v, %v; want [A3 A2] with a next cursor", titles(back), err)
+	}
+	back, err = services.Articles.GetArticles(ctx, ArticleListParams{Limit: 2, Cursor: back.PrevCursor}, 0)
+	if err != nil || !reflect.DeepEqual(titles(back), []string{"A5", "A4"}) || back.PrevCursor != "" {
+		t.Errorf("page before A3 = %v (prev %q), %v; want [A5 A4] as the first page", titles(back), back.PrevCursor, err)
+	}
+
+	// Offset pages hand out cursors too
+	offset, err := services.Articles.GetArticles(ctx, ArticleListParams{Limit: 2, Offset: 2}, 0)
+	if err != nil || offset.PrevCursor == "" || offset.NextCursor == "" {
+		t.Errorf("GetArticles(offset=2) = %+v, %v; want both cursors", offset, err)
+	}
+
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", "eyJ0IjoieCIsImlkIjoxfQ"} {
+		if _, err := services.Articles.GetArticles(ctx, ArticleListParams{Cursor: cursor}, 0); err == nil || err.Error() != "invalid cursor" {
+			t.Errorf("GetArticles(cursor=%q) error = %v, want invalid cursor", cursor, err)
+		}
+	}
+}
+
+func TestArticleSorts(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+
+	var slugs []string
+	for _, title := range []string{"Liked", "Later"} {
+		article, err := services.Articles.CreateArticle(ctx, newArticleRequest(title), jake.ID)
+		if err != nil {
+			t.Fatalf("CreateArticle() error = %v", err)
+		}
+		slugs = append(slugs, article.Slug)
+	}
+	if _, err := services.Articles.FavoriteArticle(ctx, slugs[0], celeb.ID); err != nil {
+		t.Fatalf("FavoriteArticle() error = %v", err)
+	}
+
+	tests := []struct {
+		sort      string
+		wantFirst string
+		wantErr   string
+	}{
+		{"", "Later", ""},
+		{"oldest", "Liked", ""},
+		{"most_favorited", "Liked", ""},
+		{"alphabetical", "", "invalid sort"},
+	}
+
+	for _, tt := range tests {
+		list, err := services.Articles.GetArticles(ctx, ArticleListParams{Sort: tt.sort}, 0)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("GetArticles(sort=%q) error = %v, want %s", tt.sort, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || len(list.Articles) != 2 || list.Articles[0].Title != tt.wantFirst {
+			t.Errorf("GetArticles(sort=%q) = %+v, %v; want %s first", tt.sort, list, err, tt.wantFirst)
+		}
+	}
+}
+
+// seedArticleList creates articles by authors with two tags each, and a
+// reader who favorites and follows some of them. It returns the reader's ID.
+func seedArticleList(t testing.TB, service *ArticleService, database *db.Database, articles, authors int) int {
+	t.Helper()
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepository(database)
+	reader := createTestUser(t, database, "reader")
+	authorIDs := make([]int, authors)
+	for i := range authorIDs {
+		authorIDs[i] = createTestUser(t, database, fmt.Sprintf("author%d", i))
+		if i%2 == 0 {
+			if err := userRepo.FollowUser(ctx, reader, authorIDs[i]); err != nil {
+				t.Fatalf("FollowUser() error = %v", err)
+			}
+		}
+	}
+
+	for i := 0; i < articles; i++ {
+		title := fmt.Sprintf("Article %d", i)
+		article, err := service.CreateArticle(ctx, newArticleRequest(title, "go", fmt.Sprintf("tag%d", i%3)), authorIDs[i%authors])
+		if err != nil {
+			t.Fatalf("CreateArticle() error = %v", err)
+		}
+		if i%3 == 0 {
+			if _, err := service.FavoriteArticle(ctx, article.Slug, reader); err != nil {
+				t.Fatalf("FavoriteArticle() error = %v", err)
+			}
+		}
+	}
+	return reader
+}
+
+// countQueries returns the number of statements recorded since the last reset
+func countQueries(database *db.Database) int64 {
+	var calls int64
+	for _, statement := range database.QueryStats().Snapshot() {
+		calls += statement.Calls
+	}
+	return calls
+}
+
+func TestArticleListQueryCount(t *testing.T) {
+	ctx := context.Background()
+	service, database := newTestArticleService(t)
+	reader := seedArticleList(t, service, database, 40, 4)
+
+	// A count, the page, then authors, tags, favorites and follows
+	const wantQueries = 6
+
+	for _, limit := range []int{1, 5, 20} {
+		database.QueryStats().Reset()
+		list, err := service.GetArticles(ctx, ArticleListParams{Limit: limit}, reader)
+		if err != nil {
+			t.Fatalf("GetArticles(limit=%d) error = %v", limit, err)
+		}
+		if len(list.Articles) != limit {
+			t.Errorf("GetArticles(limit=%d) = %d articles", limit, len(list.Articles))
+		}
+		if queries := countQueries(database); queries != wantQueries {
+			t.Errorf("GetArticles(limit=%d) ran %d queries, want %d", limit, queries, wantQueries)
+		}
+
+		database.QueryStats().Reset()
+		feed, err := service.GetArticlesFeed(ctx, ArticleListParams{Limit: limit}, reader)
+		if err != nil {
+			t.Fatalf("GetArticlesFeed(limit=%d) error = %v", limit, err)
+		}
+		if len(feed.Articles) != limit {
+			t.Errorf("GetArticlesFeed(limit=%d) = %d articles", limit, len(feed.Articles))
+		}
+		if queries := countQueries(database); queries != wantQueries {
+			t.Errorf("GetArticlesFeed(limit=%d) ran %d queries, want %d", limit, queries, wantQueries)
+		}
+	}
+
+	// The batched responses match the ones built article by article
+	articles, _, err := service.articleRepo.GetArticles(ctx, repository.Page{Limit: 20}, repository.ArticleFilter{})
+	if err != nil {
+		t.Fatalf("GetArticles() error = %v", err)
+	}
+	batched, err := service.buildArticleResponses(ctx, articles, reader)
+	if err != nil {
+		t.Fatalf("buildArticleResponses() error = %v", err)
+	}
+	oneByOne, err := buildArticleResponsesOneByOne(ctx, service, articles, reader)
+	if err != nil {
+		t.Fatalf("buildArticleResponsesOneByOne() error = %v", err)
+	}
+	if !reflect.DeepEqual(batched, oneByOne) {
+		t.Errorf("buildArticleResponses() = %+v, want %+v", batched, oneByOne)
+	}
+}
+
+// buildArticleResponsesOneByOne builds responses the way the service did
+// before batching, with four queries per article. It is the baseline of
+// BenchmarkArticleResponses.
+func buildArticleResponsesOneByOne(ctx context.Context, s *ArticleService, articles []model.Article, currentUserID int) ([]model.ArticleResponse, error) {
+	responses := make([]model.ArticleResponse, 0, len(articles))
+	for _, article := range articles {
+		author, err := s.userRepo.GetByID(ctx, article.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		tags, err := s.tagService.GetTagsForArticle(ctx, article.ID)
+		if err != nil {
+			return nil, err
+		}
+		var favorited, following bool
+		if currentUserID > 0 {
+			if favorited, err = s.articleRepo.IsFavorited(ctx, currentUserID, article.ID); err != nil {
+				return nil, err
+			}
+			if following, err = s.userRepo.IsFollowing(ctx, currentUserID, article.AuthorID); err != nil {
+				return nil, err
+			}
+		}
+
+		responses = append(responses, model.ArticleResponse{
+			Slug:           article.Slug,
+			Title:          article.Title,
+			Description:    article.Description,
+			Body:           article.Body,
+			TagList:        tags,
+			CreatedAt:      article.CreatedAt,
+			UpdatedAt:      article.UpdatedAt,
+			Favorited:      favorited,
+			FavoritesCount: article.FavoritesCount,
+			Author: model.AuthorProfile{
+				Username:  author.Username,
+				Bio:       author.Bio,
+				Image:     author.Image,
+				Following: following,
+			},
+			Status:    article.Status,
+			PublishAt: article.PublishAt,
+		})
+	}
+	return responses, nil
+}
+
+// BenchmarkArticleResponses compares building a 20 article page one article
+// at a time with building it in batches, reporting the queries per page:
+//
+//	go test ./internal/service -run '^$' -bench ArticleResponses
+func BenchmarkArticleResponses(b *testing.B) {
+	ctx := context.Background()
+	service, database := newTestArticleService(b)
+	reader := seedArticleList(b, service, database, 20, 5)
+
+	articles, _, err := service.articleRepo.GetArticles(ctx, repository.Page{Limit: 20}, repository.ArticleFilter{})
+	if err != nil {
+		b.Fatalf("GetArticles() error = %v", err)
+	}
+
+	builders := []struct {
+		name  string
+		build func() ([]model.ArticleResponse, error)
+	}{
+		{"one-by-one", func() ([]model.ArticleResponse, error) {
+			return buildArticleResponsesOneByOne(ctx, service, articles, reader)
+		}},
+		{"batched", func() ([]model.ArticleResponse, error) {
+			return service.buildArticleResponses(ctx, articles, reader)
+		}},
+	}
+
+	for _, builder := range builders {
+		b.Run(builder.name, func(b *testing.B) {
+			database.QueryStats().Reset()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := builder.build(); err != nil {
+					b.Fatalf("build error = %v", err)
+				}
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(countQueries(database))/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestArticleListViewerFlagErrors(t *testing.T) {
+	ctx := context.Background()
+	articleService, database := newTestArticleService(t)
+	authorID := createTestUser(t, database, "author")
+	readerID := createTestUser(t, database, "reader")
+	if _, err := articleService.CreateArticle(ctx, newArticleRequest("How to train your dragon"), authorID); err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	tests := []struct {
+		table string
+		want  string
+	}{
+		{"favorites", "failed to get favorited status"},
+		{"follows", "failed to get following status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			if _, err := database.Exec("ALTER TABLE " + tt.table + " RENAME TO broken_" + tt.table); err != nil {
+				t.Fatalf("Failed to break %s: %v", tt.table, err)
+			}
+			defer database.Exec("ALTER TABLE broken_" + tt.table + " RENAME TO " + tt.table)
+
+			if _, err := articleService.GetArticles(ctx, ArticleListParams{}, readerID); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("GetArticles() error = %v, want %s", err, tt.want)
+			}
+			if list, err := articleService.GetArticles(ctx, ArticleListParams{}, 0); err != nil || list.ArticlesCount != 1 {
+				t.Errorf("GetArticles() anonymously = %+v, %v; want the article", list, err)
+			}
+		})
+	}
+}
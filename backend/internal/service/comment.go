@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
@@ -8,39 +9,75 @@ import (
 )
 
 type CommentService struct {
-	commentRepo *repository.CommentRepository
-	userRepo    *repository.UserRepository
+	commentRepo repository.CommentStore
+	articleRepo repository.ArticleStore
+	userRepo    repository.UserStore
 }
 
-func NewCommentService(commentRepo *repository.CommentRepository, userRepo *repository.UserRepository) *CommentService {
+func NewCommentService(commentRepo repository.CommentStore, articleRepo repository.ArticleStore, userRepo repository.UserStore) *CommentService {
 	return &CommentService{
 		commentRepo: commentRepo,
+		articleRepo: articleRepo,
 		userRepo:    userRepo,
 	}
 }
 
-func (s *CommentService) GetCommentsByArticleSlug(slug string, currentUserID int) ([]*model.Comment, error) {
-	comments, err := s.commentRepo.GetByArticleSlug(slug)
+// visibleArticle retrieves an article the current user may see. Drafts and
+// scheduled articles of other users are reported as not found, as the
+// article service does.
+func (s *CommentService) visibleArticle(ctx context.Context, slug string, currentUserID int) (*model.Article, error) {
+	article, err := s.articleRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !visibleTo(article, currentUserID) {
+		return nil, fmt.Errorf("article not found")
+	}
+	return article, nil
+}
+
+func (s *CommentService) GetCommentsByArticleSlug(ctx context.Context, slug string, currentUserID int) ([]*model.Comment, error) {
+	if _, err := s.visibleArticle(ctx, slug, currentUserID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.GetByArticleSlug(ctx, slug)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments: %w", err)
 	}
 
-	// Set following status for each comment author (simplified for now)
+	if currentUserID <= 0 || len(comments) == 0 {
+		return comments, nil
+	}
+
+	// Set following status for each comment author, checking every author
+	// in one query
+	authorIDs := make([]int, 0, len(comments))
+	seenAuthors := map[int]bool{}
+	for _, comment := range comments {
+		if !seenAuthors[comment.AuthorID] {
+			seenAuthors[comment.AuthorID] = true
+			authorIDs = append(authorIDs, comment.AuthorID)
+		}
+	}
+	following, err := s.userRepo.GetFollowedIDs(ctx, currentUserID, authorIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get following status: %w", err)
+	}
 	for _, comment := range comments {
-		// TODO: Implement following functionality when user profile/follow features are added
-		comment.Author.Following = false
+		comment.Author.Following = following[comment.AuthorID]
 	}
 
 	return comments, nil
 }
 
-func (s *CommentService) CreateComment(articleSlug, body string, authorID int) (*model.Comment, error) {
+func (s *CommentService) CreateComment(ctx context.Context, articleSlug, body string, authorID int) (*model.Comment, error) {
 	if body == "" {
 		return nil, fmt.Errorf("comment body cannot be empty")
 	}
 
-	// Get article ID by slug
-	articleID, err := s.commentRepo.GetArticleIDBySlug(articleSlug)
+	// Only an article the author may see can be commented on
+	article, err := s.visibleArticle(ctx, articleSlug, authorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find article: %w", err)
 	}
@@ -49,16 +86,16 @@ func (s *CommentService) CreateComment(articleSlug, body string, authorID int) (
 	comment := &model.Comment{
 		Body:      body,
 		AuthorID:  authorID,
-		ArticleID: articleID,
+		ArticleID: article.ID,
 	}
 
-	err = s.commentRepo.Create(comment)
+	err = s.commentRepo.Create(ctx, comment)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create comment: %w", err)
 	}
 
 	// Get author information
-	author, err := s.userRepo.GetByID(authorID)
+	author, err := s.userRepo.GetByID(ctx, authorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get author information: %w", err)
 	}
@@ -67,15 +104,15 @@ func (s *CommentService) CreateComment(articleSlug, body string, authorID int) (
 		Username:  author.Username,
 		Bio:       author.Bio,
 		Image:     author.Image,
-		Following: false, // Default to false for newly created comment
+		Following: false, // Users cannot follow themselves
 	}
 
 	return comment, nil
 }
 
-func (s *CommentService) DeleteComment(commentID, authorID int) error {
+func (s *CommentService) DeleteComment(ctx context.Context, commentID, authorID int) error {
 	// Get comment to verify ownership
-	comment, err := s.commentRepo.GetByID(commentID)
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
 	if err != nil {
 		return fmt.Errorf("failed to get comment: %w", err)
 	}
@@ -86,7 +123,7 @@ func (s *CommentService) DeleteComment(commentID, authorID int) error {
 	}
 
 	// Delete comment
-	err = s.commentRepo.Delete(commentID)
+	err = s.commentRepo.Delete(ctx, commentID)
 	if err != nil {
 		return fmt.Errorf("failed to delete comment: %w", err)
 	}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestCommentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+
+	article, err := services.Articles.CreateArticle(ctx, newArticleRequest("How to train your dragon"), jake.ID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	if _, err := services.Comments.CreateComment(ctx, article.Slug, "", celeb.ID); err == nil || err.Error() != "comment body cannot be empty" {
+		t.Errorf("CreateComment() empty body error = %v, want comment body cannot be empty", err)
+	}
+	if _, err := services.Comments.CreateComment(ctx, "missing", "Nice", celeb.ID); err == nil || !strings.Contains(err.Error(), "article not found") {
+		t.Errorf("CreateComment() on missing article error = %v, want article not found", err)
+	}
+
+	comment, err := services.Comments.CreateComment(ctx, article.Slug, "Nice", celeb.ID)
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+	if comment.Author == nil || comment.Author.Username != "celeb" {
+		t.Errorf("CreateComment() author = %v, want celeb", comment.Author)
+	}
+
+	comments, err := services.Comments.GetCommentsByArticleSlug(ctx, article.Slug, jake.ID)
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("GetCommentsByArticleSlug() = %v, %v; want one comment", comments, err)
+	}
+
+	if err := services.Comments.DeleteComment(ctx, comment.ID, jake.ID); err == nil || !strings.HasPrefix(err.Error(), "unauthorized") {
+		t.Errorf("DeleteComment() by non-author error = %v, want unauthorized", err)
+	}
+	if err := services.Comments.DeleteComment(ctx, comment.ID, celeb.ID); err != nil {
+		t.Fatalf("DeleteComment() error = %v", err)
+	}
+	if err := services.Comments.DeleteComment(ctx, comment.ID, celeb.ID); err == nil || !strings.Contains(err.Error(), "comment not found") {
+		t.Errorf("DeleteComment() twice error = %v, want comment not found", err)
+	}
+}
+
+func TestCommentAuthorFollowing(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+	reader := services.register(t, "reader")
+
+	article, err := services.Articles.CreateArticle(ctx, newArticleRequest("How to train your dragon"), jake.ID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+	for _, authorID := range []int{celeb.ID, jake.ID, celeb.ID} {
+		if _, err := services.Comments.CreateComment(ctx, article.Slug, "Nice", authorID); err != nil {
+			t.Fatalf("CreateComment() error = %v", err)
+		}
+	}
+	if _, err := services.Profiles.FollowUser(ctx, reader.ID, "celeb"); err != nil {
+		t.Fatalf("FollowUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		viewer int
+		want   map[string]bool
+	}{
+		{"follower", reader.ID, map[string]bool{"celeb": true, "jake": false}},
+		{"non-follower", jake.ID, map[string]bool{"celeb": false, "jake": false}},
+		{"anonymous", 0, map[string]bool{"celeb": false, "jake": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments, err := services.Comments.GetCommentsByArticleSlug(ctx, article.Slug, tt.viewer)
+			if err != nil || len(comments) != 3 {
+				t.Fatalf("GetCommentsByArticleSlug() = %v, %v; want 3 comments", comments, err)
+			}
+			for _, comment := range comments {
+				if comment.Author.Following != tt.want[comment.Author.Username] {
+					t.Errorf("author %s following = %v, want %v", comment.Author.Username, comment.Author.Following, tt.want[comment.Author.Username])
+				}
+			}
+		})
+	}
+}
+
+func TestCommentsOnDrafts(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+
+	req := newArticleRequest("How to train your dragon")
+	req.Article.Status = model.ArticleDraft
+	draft, err := services.Articles.CreateArticle(ctx, req, jake.ID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	// The author may comment on and read the comments of their draft
+	if _, err := services.Comments.CreateComment(ctx, draft.Slug, "Note to self", jake.ID); err != nil {
+		t.Fatalf("CreateComment() by author error = %v", err)
+	}
+	if comments, err := services.Comments.GetCommentsByArticleSlug(ctx, draft.Slug, jake.ID); err != nil || len(comments) != 1 {
+		t.Errorf("GetCommentsByArticleSlug() by author = %v, %v; want one comment", comments, err)
+	}
+
+	// Anyone else sees the same error as for a missing article
+	for _, viewer := range []int{celeb.ID, 0} {
+		if _, err := services.Comments.GetCommentsByArticleSlug(ctx, draft.Slug, viewer); err == nil || err.Error() != "article not found" {
+			t.Errorf("GetCommentsByArticleSlug() by %d error = %v, want article not found", viewer, err)
+		}
+	}
+	if _, err := services.Comments.CreateComment(ctx, draft.Slug, "Nice", celeb.ID); err == nil || err.Error() != "failed to find article: article not found" {
+		t.Errorf("CreateComment() by non-author error = %v, want article not found", err)
+	}
+	if comments, err := services.Comments.GetCommentsByArticleSlug(ctx, draft.Slug, jake.ID); err != nil || len(comments) != 1 {
+		t.Errorf("GetCommentsByArticleSlug() = %v, %v; want only the author's comment", comments, err)
+	}
+}
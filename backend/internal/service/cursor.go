@@ -0,0 +1,105 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
+)
+
+// cursorToken is the JSON inside an opaque cursor
+type cursorToken struct {
+	Sort   string `json:"s"`
+	Time   string `json:"t,omitempty"`
+	Count  int    `json:"n,omitempty"`
+	ID     int    `json:"id"`
+	Before bool   `json:"b,omitempty"`
+}
+
+// encodeCursor turns a cursor into the opaque string handed to clients
+func encodeCursor(cursor *repository.Cursor) string {
+	token := cursorToken{Sort: string(cursor.Sort), Count: cursor.Count, ID: cursor.ID, Before: cursor.Before}
+	if !cursor.Time.IsZero() {
+		// RFC 3339 keeps the zone offset, so the time binds exactly as stored
+		token.Time = cursor.Time.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor produced by encodeCursor for a list read in
+// sort
+func decodeCursor(value string, sort repository.Sort) (*repository.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil || token.ID <= 0 || repository.Sort(token.Sort) != sort {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	cursor := &repository.Cursor{Sort: sort, Count: token.Count, ID: token.ID, Before: token.Before}
+	if token.Time != "" {
+		if cursor.Time, err = time.Parse(time.RFC3339Nano, token.Time); err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+	}
+
+	return cursor, nil
+}
+
+// listPage turns list parameters into the page to read. One article more
+// than the limit is read, to tell whether another page follows.
+func listPage(params ArticleListParams) (repository.Page, error) {
+	sort := repository.SortNewest
+	if params.Sort != "" {
+		sort = repository.Sort(params.Sort)
+		if !sort.Valid() {
+			return repository.Page{}, fmt.Errorf("invalid sort")
+		}
+	}
+
+	page := repository.Page{Limit: params.Limit + 1, Offset: params.Offset, Sort: sort}
+	if params.Cursor != "" {
+		cursor, err := decodeCursor(params.Cursor, sort)
+		if err != nil {
+			return repository.Page{}, err
+		}
+		page.Cursor = cursor
+	}
+	return page, nil
+}
+
+// trimPage drops the extra article read by listPage and returns the
+// cursors of the pages after and before the remaining ones, empty where no
+// such page exists
+func trimPage(articles []model.Article, page repository.Page, limit int) (trimmed []model.Article, next, prev string) {
+	more := len(articles) > limit
+	before := page.Cursor != nil && page.Cursor.Before
+	if more && before {
+		// Pages before a cursor read the extra article at the newest end
+		articles = articles[len(articles)-limit:]
+	} else if more {
+		articles = articles[:limit]
+	}
+	if len(articles) == 0 {
+		return articles, "", ""
+	}
+
+	// A cursor page always has the page it was reached from on one side
+	hasNext := more || before
+	hasPrev := (more && before) || (page.Cursor != nil && !before) || (page.Cursor == nil && page.Offset > 0)
+
+	if hasNext {
+		next = encodeCursor(repository.CursorFor(articles[len(articles)-1], page.Sort, false))
+	}
+	if hasPrev {
+		prev = encodeCursor(repository.CursorFor(articles[0], page.Sort, true))
+	}
+	return articles, next, prev
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
@@ -8,17 +9,17 @@ import (
 )
 
 type ProfileService struct {
-	userRepo *repository.UserRepository
+	userRepo repository.UserStore
 }
 
-func NewProfileService(userRepo *repository.UserRepository) *ProfileService {
+func NewProfileService(userRepo repository.UserStore) *ProfileService {
 	return &ProfileService{
 		userRepo: userRepo,
 	}
 }
 
-func (s *ProfileService) GetProfile(username string, currentUserID *int) (*model.ProfileResponse, error) {
-	profile, err := s.userRepo.GetProfileByUsername(username, currentUserID)
+func (s *ProfileService) GetProfile(ctx context.Context, username string, currentUserID *int) (*model.ProfileResponse, error) {
+	profile, err := s.userRepo.GetProfileByUsername(ctx, username, currentUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get profile: %w", err)
 	}
@@ -26,9 +27,9 @@ func (s *ProfileService) GetProfile(username string, currentUserID *int) (*model
 	return profile, nil
 }
 
-func (s *ProfileService) FollowUser(followerID int, username string) (*model.ProfileResponse, error) {
+func (s *ProfileService) FollowUser(ctx context.Context, followerID int, username string) (*model.ProfileResponse, error) {
 	// Get the user to follow
-	followed, err := s.userRepo.GetByUsername(username)
+	followed, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
@@ -39,7 +40,7 @@ func (s *ProfileService) FollowUser(followerID int, username string) (*model.Pro
 	}
 
 	// Check if already following
-	isFollowing, err := s.userRepo.IsFollowing(followerID, followed.ID)
+	isFollowing, err := s.userRepo.IsFollowing(ctx, followerID, followed.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check follow status: %w", err)
 	}
@@ -49,7 +50,7 @@ func (s *ProfileService) FollowUser(followerID int, username string) (*model.Pro
 	}
 
 	// Create follow relationship
-	err = s.userRepo.FollowUser(followerID, followed.ID)
+	err = s.userRepo.FollowUser(ctx, followerID, followed.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to follow user: %w", err)
 	}
@@ -65,15 +66,15 @@ func (s *ProfileService) FollowUser(followerID int, username string) (*model.Pro
 	return profile, nil
 }
 
-func (s *ProfileService) UnfollowUser(followerID int, username string) (*model.ProfileResponse, error) {
+func (s *ProfileService) UnfollowUser(ctx context.Context, followerID int, username string) (*model.ProfileResponse, error) {
 	// Get the user to unfollow
-	followed, err := s.userRepo.GetByUsername(username)
+	followed, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
 	// Remove follow relationship
-	err = s.userRepo.UnfollowUser(followerID, followed.ID)
+	err = s.userRepo.UnfollowUser(ctx, followerID, followed.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unfollow user: %w", err)
 	}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFollowAndUnfollow(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	services.register(t, "celeb")
+
+	profile, err := services.Profiles.FollowUser(ctx, jake.ID, "celeb")
+	if err != nil || !profile.Following {
+		t.Fatalf("FollowUser() = %v, %v; want following", profile, err)
+	}
+	profile, err = services.Profiles.GetProfile(ctx, "celeb", &jake.ID)
+	if err != nil || !profile.Following {
+		t.Errorf("GetProfile() = %v, %v; want following", profile, err)
+	}
+
+	if _, err := services.Profiles.FollowUser(ctx, jake.ID, "celeb"); err == nil || err.Error() != "already following this user" {
+		t.Errorf("FollowUser() twice error = %v, want already following this user", err)
+	}
+	if _, err := services.Profiles.FollowUser(ctx, jake.ID, "jake"); err == nil || err.Error() != "cannot follow yourself" {
+		t.Errorf("FollowUser() self error = %v, want cannot follow yourself", err)
+	}
+	if _, err := services.Profiles.FollowUser(ctx, jake.ID, "nobody"); err == nil || !strings.HasPrefix(err.Error(), "user not found") {
+		t.Errorf("FollowUser() unknown user error = %v, want user not found", err)
+	}
+
+	profile, err = services.Profiles.UnfollowUser(ctx, jake.ID, "celeb")
+	if err != nil || profile.Following {
+		t.Fatalf("UnfollowUser() = %v, %v; want not following", profile, err)
+	}
+	if _, err := services.Profiles.UnfollowUser(ctx, jake.ID, "celeb"); err == nil || !strings.Contains(err.Error(), "follow relationship not found") {
+		t.Errorf("UnfollowUser() twice error = %v, want follow relationship not found", err)
+	}
+	profile, _ = services.Profiles.GetProfile(ctx, "celeb", nil)
+	if profile.Following {
+		t.Error("Expected anonymous profile not to be followed")
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// PublishScheduled publishes the scheduled articles due at now. It returns
+// how many it published and when the next one is due, or the zero time when
+// none is scheduled.
+func (s *ArticleService) PublishScheduled(ctx context.Context, now time.Time) (int, time.Time, error) {
+	published, err := s.articleRepo.PublishScheduled(ctx, now)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to publish scheduled articles: %w", err)
+	}
+
+	next, err := s.articleRepo.NextScheduledAt(ctx)
+	if err != nil {
+		return published, time.Time{}, fmt.Errorf("failed to get next scheduled article: %w", err)
+	}
+
+	return published, next, nil
+}
+
+// RunPublisher publishes scheduled articles as they fall due, until ctx is
+// done. It sleeps until the next article is due, or until an article is
+// scheduled, but never longer than interval, so articles scheduled by other
+// instances are picked up too.
+func (s *ArticleService) RunPublisher(ctx context.Context, interval time.Duration) {
+	for {
+		published, next, err := s.PublishScheduled(ctx, time.Now())
+		if err != nil {
+			log.Printf("Publisher: %v", err)
+		} else if published > 0 {
+			log.Printf("Publisher: published %d scheduled articles", published)
+		}
+
+		wait := interval
+		if !next.IsZero() && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		if wait <= 0 {
+			// Due but not published, as when clocks disagree; don't spin
+			wait = time.Second
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.scheduled:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// wakePublisher makes a running publisher look at the schedule again
+func (s *ArticleService) wakePublisher() {
+	select {
+	case s.scheduled <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestRunPublisher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	services := newTestServices()
+	jake := services.register(t, "jake")
+
+	done := make(chan struct{})
+	go func() {
+		services.Articles.RunPublisher(ctx, time.Hour)
+		close(done)
+	}()
+
+	// Scheduling wakes the publisher, which then waits for the article to
+	// fall due rather than for the interval
+	publishAt := time.Now().Add(100 * time.Millisecond)
+	req := newArticleRequest("Dragons at dawn")
+	req.Article.Status = model.ArticleScheduled
+	req.Article.PublishAt = &publishAt
+	article, err := services.Articles.CreateArticle(ctx, req, jake.ID)
+	if err != nil {
+		t.Fatalf("CreateArticle(scheduled) error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := services.Articles.GetArticleBySlug(ctx, article.Slug, 0)
+		if err == nil && got.Status == model.ArticlePublished {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("article not published by the publisher: %+v, %v", got, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunPublisher() did not return after cancel")
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/markdown"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+// excerptLength is the most characters of an excerpt
+const excerptLength = 200
+
+// renderBodies fills in the sanitized HTML body and the excerpt of the
+// responses of articles, given in the same order. Renders are cached per
+// revision, so each version of an article is rendered once.
+func (s *ArticleService) renderBodies(ctx context.Context, articles []model.Article, responses []model.ArticleResponse) error {
+	articleIDs := make([]int, 0, len(articles))
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ID)
+	}
+
+	renders, err := s.articleRepo.GetRenders(ctx, markdown.Version, articleIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get rendered bodies: %w", err)
+	}
+
+	for i, article := range articles {
+		render, ok := renders[article.ID]
+		if !ok || render.Revision != article.Revision {
+			bodyHTML, err := markdown.Render(article.Body)
+			if err != nil {
+				return err
+			}
+			render = model.ArticleRender{
+				ArticleID: article.ID,
+				Revision:  article.Revision,
+				Renderer:  markdown.Version,
+				BodyHTML:  bodyHTML,
+				Excerpt:   markdown.Excerpt(bodyHTML, excerptLength),
+			}
+			if err := s.articleRepo.SaveRender(ctx, &render); err != nil {
+				return fmt.Errorf("failed to cache rendered body: %w", err)
+			}
+		}
+
+		responses[i].BodyHTML = render.BodyHTML
+		responses[i].Excerpt = render.Excerpt
+	}
+
+	return nil
+}
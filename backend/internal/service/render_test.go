@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestRenderedBodies(t *testing.T) {
+	ctx := context.Background()
+	articleService, database := newTestArticleService(t)
+	authorID := createTestUser(t, database, "author")
+
+	req := newArticleRequest("How to train your dragon", "dragons")
+	req.Article.Body = "# Training\n\nBe **patient**.<script>alert(1)</script> [More](javascript:alert(1))"
+	article, err := articleService.CreateArticle(ctx, req, authorID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+	if article.BodyHTML != "" || article.Excerpt != "" {
+		t.Errorf("CreateArticle() = %+v, want no rendered body unless asked for", article)
+	}
+
+	rendered, err := articleService.GetRenderedArticleBySlug(ctx, article.Slug, 0)
+	if err != nil {
+		t.Fatalf("GetRenderedArticleBySlug() error = %v", err)
+	}
+	want := "<h1>Training</h1>\n<p>Be <strong>patient</strong>.alert(1) More</p>\n"
+	if rendered.BodyHTML != want || rendered.Excerpt != "Training Be patient.alert(1) More" || rendered.Body != req.Article.Body {
+		t.Errorf("GetRenderedArticleBySlug() = %q, %q; want %q", rendered.BodyHTML, rendered.Excerpt, want)
+	}
+	if got := countRows(t, database, "article_renders"); got != 1 {
+		t.Errorf("article_renders = %d, want 1", got)
+	}
+
+	// The cached render is reused by lists, and an update renders anew
+	list, err := articleService.GetArticles(ctx, ArticleListParams{BodyHTML: true}, 0)
+	if err != nil || list.ArticlesCount != 1 || list.Articles[0].BodyHTML != want {
+		t.Fatalf("GetArticles(bodyHtml) = %+v, %v; want the rendered body", list, err)
+	}
+	if got := countRows(t, database, "article_renders"); got != 1 {
+		t.Errorf("article_renders after listing = %d, want 1", got)
+	}
+
+	body := strings.Repeat("Dragons breathe fire. ", 20)
+	var update model.UpdateArticleRequest
+	update.Article.Body = &body
+	if _, err := articleService.UpdateArticle(ctx, article.Slug, update, authorID); err != nil {
+		t.Fatalf("UpdateArticle() error = %v", err)
+	}
+	list, err = articleService.GetArticles(ctx, ArticleListParams{BodyHTML: true}, 0)
+	if err != nil || !strings.HasPrefix(list.Articles[0].BodyHTML, "<p>Dragons breathe fire.") {
+		t.Fatalf("GetArticles(bodyHtml) after update = %+v, %v; want the new body", list, err)
+	}
+	if excerpt := list.Articles[0].Excerpt; len([]rune(excerpt)) > excerptLength || !strings.HasSuffix(excerpt, "fire…") {
+		t.Errorf("excerpt = %q, want at most %d characters cut after a word", excerpt, excerptLength)
+	}
+	if got := countRows(t, database, "article_renders"); got != 2 {
+		t.Errorf("article_renders after update = %d, want one per revision", got)
+	}
+
+	if list, err := articleService.GetArticles(ctx, ArticleListParams{}, 0); err != nil || list.Articles[0].BodyHTML != "" {
+		t.Errorf("GetArticles() = %+v, %v; want no rendered body unless asked for", list, err)
+	}
+}
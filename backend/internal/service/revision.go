@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/utils"
+)
+
+// authoredArticle retrieves an article whose revision history the current
+// user may see, which only its author may
+func (s *ArticleService) authoredArticle(ctx context.Context, slug string, currentUserID int) (*model.Article, error) {
+	article, err := s.articleRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !visibleTo(article, currentUserID) {
+		return nil, fmt.Errorf("article not found")
+	}
+	if article.AuthorID != currentUserID {
+		return nil, fmt.Errorf("unauthorized: only the author can see the revisions of an article")
+	}
+
+	return article, nil
+}
+
+// GetRevisions retrieves the revisions of an article, newest first
+func (s *ArticleService) GetRevisions(ctx context.Context, slug string, currentUserID int) (*model.RevisionsResponse, error) {
+	article, err := s.authoredArticle(ctx, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.articleRepo.GetRevisions(ctx, article.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+
+	responses, err := s.buildRevisionResponses(ctx, revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.RevisionsResponse{
+		Revisions:      responses,
+		RevisionsCount: len(responses),
+	}, nil
+}
+
+// GetRevision retrieves one revision of an article
+func (s *ArticleService) GetRevision(ctx context.Context, slug string, number int, currentUserID int) (*model.RevisionResponse, error) {
+	article, err := s.authoredArticle(ctx, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.articleRepo.GetRevision(ctx, article.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.buildRevisionResponses(ctx, []model.ArticleRevision{*revision})
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses[0], nil
+}
+
+// DiffRevisions returns a unified diff from one revision of an article to
+// another, or to the current article when to is 0
+func (s *ArticleService) DiffRevisions(ctx context.Context, slug string, from, to int, currentUserID int) (*model.RevisionDiff, error) {
+	article, err := s.authoredArticle(ctx, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := s.articleRepo.GetRevision(ctx, article.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	fromName := fmt.Sprintf("revision %d", from)
+	fromText := revisionText(fromRevision.Title, fromRevision.Description, fromRevision.Body, fromRevision.TagList)
+
+	var toName, toText string
+	if to == 0 {
+		tags, err := s.tagService.GetTagsForArticle(ctx, article.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get article tags: %w", err)
+		}
+		toName = "current"
+		toText = revisionText(article.Title, article.Description, article.Body, tags)
+	} else {
+		toRevision, err := s.articleRepo.GetRevision(ctx, article.ID, to)
+		if err != nil {
+			return nil, err
+		}
+		toName = fmt.Sprintf("revision %d", to)
+		toText = revisionText(toRevision.Title, toRevision.Description, toRevision.Body, toRevision.TagList)
+	}
+
+	return &model.RevisionDiff{
+		From: from,
+		To:   to,
+		Diff: utils.UnifiedDiff(fromName, toName, fromText, toText),
+	}, nil
+}
+
+// RestoreRevision brings back the fields and tags of an earlier revision.
+// It is an update like any other, so the version it replaces becomes a new
+// revision and no history is lost.
+func (s *ArticleService) RestoreRevision(ctx context.Context, slug string, number int, currentUserID int) (*model.ArticleResponse, error) {
+	article, err := s.authoredArticle(ctx, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.articleRepo.GetRevision(ctx, article.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var req model.UpdateArticleRequest
+	// An unchanged title keeps the slug, which a new title would replace
+	if revision.Title != article.Title {
+		req.Article.Title = &revision.Title
+	}
+	req.Article.Description = &revision.Description
+	req.Article.Body = &revision.Body
+	req.Article.TagList = append([]string{}, revision.TagList...)
+
+	return s.UpdateArticle(ctx, slug, req, currentUserID)
+}
+
+// buildRevisionResponses builds the responses of revisions, loading their
+// editors in one query
+func (s *ArticleService) buildRevisionResponses(ctx context.Context, revisions []model.ArticleRevision) ([]model.RevisionResponse, error) {
+	editorIDs := make([]int, 0, len(revisions))
+	for _, revision := range revisions {
+		editorIDs = append(editorIDs, revision.EditorID)
+	}
+	editors, err := s.userRepo.GetByIDs(ctx, editorIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get editors: %w", err)
+	}
+
+	responses := make([]model.RevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		response := model.RevisionResponse{
+			Revision:    revision.Revision,
+			Title:       revision.Title,
+			Description: revision.Description,
+			Body:        revision.Body,
+			TagList:     revision.TagList,
+			CreatedAt:   revision.CreatedAt,
+		}
+		if editor, ok := editors[revision.EditorID]; ok {
+			response.Editor = editor.Username
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// revisionText lays out a version of an article as the text that is diffed
+func revisionText(title, description, body string, tags []string) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Title: %s\n", title)
+	fmt.Fprintf(&text, "Description: %s\n", description)
+	fmt.Fprintf(&text, "Tags: %s\n", strings.Join(tags, ", "))
+	text.WriteString("\n")
+	text.WriteString(body)
+	if !strings.HasSuffix(body, "\n") {
+		text.WriteString("\n")
+	}
+	return text.String()
+}
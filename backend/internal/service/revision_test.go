@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestArticleRevisions(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	celeb := services.register(t, "celeb")
+
+	article, err := services.Articles.CreateArticle(ctx, newArticleRequest("How to train your dragon", "dragons"), jake.ID)
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+	slug := article.Slug
+
+	if revisions, err := services.Articles.GetRevisions(ctx, slug, jake.ID); err != nil || revisions.RevisionsCount != 0 {
+		t.Errorf("GetRevisions() of a new article = %+v, %v; want none", revisions, err)
+	}
+
+	// Every update stores the version it replaces
+	body := "You have to believe\nin dragons"
+	var req model.UpdateArticleRequest
+	req.Article.Body = &body
+	req.Article.TagList = []string{"dragons", "training"}
+	if _, err := services.Articles.UpdateArticle(ctx, slug, req, jake.ID); err != nil {
+		t.Fatalf("UpdateArticle() error = %v", err)
+	}
+	description := "Ever wonder why?"
+	req = model.UpdateArticleRequest{}
+	req.Article.Description = &description
+	if _, err := services.Articles.UpdateArticle(ctx, slug, req, jake.ID); err != nil {
+		t.Fatalf("UpdateArticle() error = %v", err)
+	}
+
+	revisions, err := services.Articles.GetRevisions(ctx, slug, jake.ID)
+	if err != nil || revisions.RevisionsCount != 2 {
+		t.Fatalf("GetRevisions() = %+v, %v; want 2 revisions", revisions, err)
+	}
+	first := revisions.Revisions[1]
+	if first.Revision != 1 || first.Body != "You have to believe" || !reflect.DeepEqual(first.TagList, []string{"dragons"}) || first.Editor != "jake" {
+		t.Errorf("GetRevisions()[1] = %+v, want the original article edited by jake", first)
+	}
+	if second := revisions.Revisions[0]; second.Revision != 2 || second.Body != body || second.Description != "Ever wonder how?" {
+		t.Errorf("GetRevisions()[0] = %+v, want the first edit", second)
+	}
+
+	diff, err := services.Articles.DiffRevisions(ctx, slug, 1, 2, jake.ID)
+	want := "--- revision 1\n+++ revision 2\n@@ -1,5 +1,6 @@\n Title: How to train your dragon\n Description: Ever wonder how?\n-Tags: dragons\n+Tags: dragons, training\n \n You have to believe\n+in dragons\n"
+	if err != nil || diff.Diff != want {
+		t.Errorf("DiffRevisions(1, 2) = %q, %v; want %q", diff.Diff, err, want)
+	}
+	diff, err = services.Articles.DiffRevisions(ctx, slug, 2, 0, jake.ID)
+	if err != nil || !strings.Contains(diff.Diff, "+++ current\n") || !strings.Contains(diff.Diff, "+Description: Ever wonder why?\n") {
+		t.Errorf("DiffRevisions(2, current) = %q, %v; want the description change", diff.Diff, err)
+	}
+	if _, err := services.Articles.DiffRevisions(ctx, slug, 1, 5, jake.ID); err == nil || err.Error() != "revision not found" {
+		t.Errorf("DiffRevisions(1, 5) error = %v, want revision not found", err)
+	}
+
+	// Restoring is an update, so the replaced version is kept too
+	restored, err := services.Articles.RestoreRevision(ctx, slug, 1, jake.ID)
+	if err != nil {
+		t.Fatalf("RestoreRevision() error = %v", err)
+	}
+	if restored.Slug != slug || restored.Body != "You have to believe" || restored.Description != "Ever wonder how?" || !reflect.DeepEqual(restored.TagList, []string{"dragons"}) {
+		t.Errorf("RestoreRevision() = %+v, want the original article under the same slug", restored)
+	}
+	if revision, err := services.Articles.GetRevision(ctx, slug, 3, jake.ID); err != nil || revision.Description != description {
+		t.Errorf("GetRevision(3) = %+v, %v; want the version replaced by the restore", revision, err)
+	}
+	if diff, err := services.Articles.DiffRevisions(ctx, slug, 1, 0, jake.ID); err != nil || diff.Diff != "" {
+		t.Errorf("DiffRevisions(1, current) after restore = %q, %v; want no difference", diff.Diff, err)
+	}
+
+	// Only the author sees the history
+	for name, call := range map[string]func() error{
+		"GetRevisions":    func() error { _, err := services.Articles.GetRevisions(ctx, slug, celeb.ID); return err },
+		"GetRevision":     func() error { _, err := services.Articles.GetRevision(ctx, slug, 1, celeb.ID); return err },
+		"DiffRevisions":   func() error { _, err := services.Articles.DiffRevisions(ctx, slug, 1, 0, celeb.ID); return err },
+		"RestoreRevision": func() error { _, err := services.Articles.RestoreRevision(ctx, slug, 1, celeb.ID); return err },
+	} {
+		if err := call(); err == nil || !strings.HasPrefix(err.Error(), "unauthorized") {
+			t.Errorf("%s() by another user error = %v, want unauthorized", name, err)
+		}
+	}
+	if _, err := services.Articles.GetRevisions(ctx, "missing", jake.ID); err == nil || err.Error() != "article not found" {
+		t.Errorf("GetRevisions() of a missing article error = %v, want article not found", err)
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository/memory"
+)
+
+// testServices wires every service to one in-memory store
+type testServices struct {
+	Users    *UserService
+	Profiles *ProfileService
+	Articles *ArticleService
+	Comments *CommentService
+	Tags     *TagService
+}
+
+// newTestServices creates services backed by an empty in-memory store
+func newTestServices() *testServices {
+	store := memory.NewStore()
+	tagService := NewTagService(store.Tags())
+
+	return &testServices{
+		Users:    NewUserService(store.Users()),
+		Profiles: NewProfileService(store.Users()),
+		Articles: NewArticleService(store, store.Articles(), store.Users(), tagService),
+		Comments: NewCommentService(store.Comments(), store.Articles(), store.Users()),
+		Tags:     tagService,
+	}
+}
+
+// register signs up a user with the given username through the user service
+func (s *testServices) register(t *testing.T, username string) *model.User {
+	t.Helper()
+
+	var req model.CreateUserRequest
+	req.User.Email = username + "@example.com"
+	req.User.Username = username
+	req.User.Password = "password1"
+
+	user, err := s.Users.CreateUser(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to register %s: %v", username, err)
+	}
+	return user
+}
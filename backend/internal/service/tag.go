@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/repository"
@@ -9,18 +10,18 @@ import (
 
 // TagService handles tag business logic
 type TagService struct {
-	tagRepo *repository.TagRepository
+	tagRepo repository.TagStore
 }
 
 // NewTagService creates a new tag service
-func NewTagService(tagRepo *repository.TagRepository) *TagService {
+func NewTagService(tagRepo repository.TagStore) *TagService {
 	return &TagService{
 		tagRepo: tagRepo,
 	}
 }
 
 // GetPopularTags retrieves popular tags ordered by usage count
-func (s *TagService) GetPopularTags(limit int) ([]string, error) {
+func (s *TagService) GetPopularTags(ctx context.Context, limit int) ([]string, error) {
 	if limit <= 0 {
 		limit = 20 // Default limit
 	}
@@ -28,7 +29,7 @@ func (s *TagService) GetPopularTags(limit int) ([]string, error) {
 		limit = 100 // Maximum limit
 	}
 
-	tags, err := s.tagRepo.GetPopularTags(limit)
+	tags, err := s.tagRepo.GetPopularTags(ctx, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get popular tags: %w", err)
 	}
@@ -37,8 +38,8 @@ func (s *TagService) GetPopularTags(limit int) ([]string, error) {
 }
 
 // GetAllTags retrieves all unique tags
-func (s *TagService) GetAllTags() ([]string, error) {
-	tags, err := s.tagRepo.GetAllTags()
+func (s *TagService) GetAllTags(ctx context.Context) ([]string, error) {
+	tags, err := s.tagRepo.GetAllTags(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all tags: %w", err)
 	}
@@ -47,26 +48,14 @@ func (s *TagService) GetAllTags() ([]string, error) {
 }
 
 // CreateTagsForArticle creates and associates tags with an article
-func (s *TagService) CreateTagsForArticle(articleID int, tagNames []string) error {
-	// Normalize tags
-	normalizedTags := utils.NormalizeTags(tagNames)
-
-	// Validate each tag
-	var validTags []string
-	for _, tag := range normalizedTags {
-		if utils.ValidateTag(tag) {
-			sanitized := utils.SanitizeTag(tag)
-			if sanitized != "" {
-				validTags = append(validTags, sanitized)
-			}
-		}
-	}
+func (s *TagService) CreateTagsForArticle(ctx context.Context, articleID int, tagNames []string) error {
+	validTags := cleanTags(tagNames)
 
 	if len(validTags) == 0 {
 		return nil // No valid tags to create
 	}
 
-	err := s.tagRepo.CreateTagsForArticle(articleID, validTags)
+	err := s.tagRepo.CreateTagsForArticle(ctx, articleID, validTags)
 	if err != nil {
 		return fmt.Errorf("failed to create tags for article: %w", err)
 	}
@@ -75,22 +64,10 @@ func (s *TagService) CreateTagsForArticle(articleID int, tagNames []string) erro
 }
 
 // UpdateTagsForArticle updates tags associated with an article
-func (s *TagService) UpdateTagsForArticle(articleID int, tagNames []string) error {
-	// Normalize tags
-	normalizedTags := utils.NormalizeTags(tagNames)
-
-	// Validate each tag
-	var validTags []string
-	for _, tag := range normalizedTags {
-		if utils.ValidateTag(tag) {
-			sanitized := utils.SanitizeTag(tag)
-			if sanitized != "" {
-				validTags = append(validTags, sanitized)
-			}
-		}
-	}
+func (s *TagService) UpdateTagsForArticle(ctx context.Context, articleID int, tagNames []string) error {
+	validTags := cleanTags(tagNames)
 
-	err := s.tagRepo.UpdateTagsForArticle(articleID, validTags)
+	err := s.tagRepo.UpdateTagsForArticle(ctx, articleID, validTags)
 	if err != nil {
 		return fmt.Errorf("failed to update tags for article: %w", err)
 	}
@@ -99,8 +76,8 @@ func (s *TagService) UpdateTagsForArticle(articleID int, tagNames []string) erro
 }
 
 // GetTagsForArticle retrieves all tags for a specific article
-func (s *TagService) GetTagsForArticle(articleID int) ([]string, error) {
-	tags, err := s.tagRepo.GetTagsForArticle(articleID)
+func (s *TagService) GetTagsForArticle(ctx context.Context, articleID int) ([]string, error) {
+	tags, err := s.tagRepo.GetTagsForArticle(ctx, articleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags for article: %w", err)
 	}
@@ -108,9 +85,20 @@ func (s *TagService) GetTagsForArticle(articleID int) ([]string, error) {
 	return tags, nil
 }
 
+// GetTagsForArticles retrieves the tags of several articles, keyed by
+// article ID
+func (s *TagService) GetTagsForArticles(ctx context.Context, articleIDs []int) (map[int][]string, error) {
+	tags, err := s.tagRepo.GetTagsForArticles(ctx, articleIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags for articles: %w", err)
+	}
+
+	return tags, nil
+}
+
 // GetArticleCountByTag gets the number of articles for a specific tag
-func (s *TagService) GetArticleCountByTag(tagName string) (int, error) {
-	count, err := s.tagRepo.GetArticleCountByTag(tagName)
+func (s *TagService) GetArticleCountByTag(ctx context.Context, tagName string) (int, error) {
+	count, err := s.tagRepo.GetArticleCountByTag(ctx, tagName)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get article count for tag: %w", err)
 	}
@@ -119,11 +107,30 @@ func (s *TagService) GetArticleCountByTag(tagName string) (int, error) {
 }
 
 // DeleteUnusedTags removes tags that are not associated with any articles
-func (s *TagService) DeleteUnusedTags() error {
-	err := s.tagRepo.DeleteUnusedTags()
+func (s *TagService) DeleteUnusedTags(ctx context.Context) error {
+	err := s.tagRepo.DeleteUnusedTags(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to delete unused tags: %w", err)
 	}
 
 	return nil
 }
+
+// cleanTags normalizes tag names and drops the invalid ones
+func cleanTags(tagNames []string) []string {
+	// Normalize tags
+	normalizedTags := utils.NormalizeTags(tagNames)
+
+	// Validate each tag
+	var validTags []string
+	for _, tag := range normalizedTags {
+		if utils.ValidateTag(tag) {
+			sanitized := utils.SanitizeTag(tag)
+			if sanitized != "" {
+				validTags = append(validTags, sanitized)
+			}
+		}
+	}
+
+	return validTags
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net/mail"
 
@@ -12,25 +13,25 @@ import (
 
 // UserService handles user business logic
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo repository.UserStore
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo *repository.UserRepository) *UserService {
+func NewUserService(userRepo repository.UserStore) *UserService {
 	return &UserService{
 		userRepo: userRepo,
 	}
 }
 
 // CreateUser creates a new user with validation
-func (s *UserService) CreateUser(req model.CreateUserRequest) (*model.User, error) {
+func (s *UserService) CreateUser(ctx context.Context, req model.CreateUserRequest) (*model.User, error) {
 	// Validate input
 	if err := s.validateCreateUserRequest(req); err != nil {
 		return nil, err
 	}
 
 	// Check if email already exists
-	emailExists, err := s.userRepo.EmailExists(req.User.Email)
+	emailExists, err := s.userRepo.EmailExists(ctx, req.User.Email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check email existence: %w", err)
 	}
@@ -39,7 +40,7 @@ func (s *UserService) CreateUser(req model.CreateUserRequest) (*model.User, erro
 	}
 
 	// Check if username already exists
-	usernameExists, err := s.userRepo.UsernameExists(req.User.Username)
+	usernameExists, err := s.userRepo.UsernameExists(ctx, req.User.Username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check username existence: %w", err)
 	}
@@ -63,7 +64,7 @@ func (s *UserService) CreateUser(req model.CreateUserRequest) (*model.User, erro
 	}
 
 	// Save to database
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -71,25 +72,29 @@ func (s *UserService) CreateUser(req model.CreateUserRequest) (*model.User, erro
 }
 
 // GetUserByEmail retrieves a user by email
-func (s *UserService) GetUserByEmail(email string) (*model.User, error) {
-	return s.userRepo.GetByEmail(email)
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return s.userRepo.GetByEmail(ctx, email)
 }
 
 // GetUserByID retrieves a user by ID
-func (s *UserService) GetUserByID(id int) (*model.User, error) {
-	return s.userRepo.GetByID(id)
+func (s *UserService) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	return s.userRepo.GetByID(ctx, id)
 }
 
 // GetUserByUsername retrieves a user by username
-func (s *UserService) GetUserByUsername(username string) (*model.User, error) {
-	return s.userRepo.GetByUsername(username)
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	return s.userRepo.GetByUsername(ctx, username)
 }
 
 // AuthenticateUser authenticates a user with email and password
-func (s *UserService) AuthenticateUser(email, password string) (*model.User, error) {
+func (s *UserService) AuthenticateUser(ctx context.Context, email, password string) (*model.User, error) {
 	// Get user by email
-	user, err := s.userRepo.GetByEmail(email)
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
+		// A cancelled request is not a failed login
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return nil, fmt.Errorf("invalid email or password")
 	}
 
@@ -102,10 +107,13 @@ func (s *UserService) AuthenticateUser(email, password string) (*model.User, err
 }
 
 // UpdateUser updates user information
-func (s *UserService) UpdateUser(userID int, req model.UpdateUserRequest) (*model.User, error) {
+func (s *UserService) UpdateUser(ctx context.Context, userID int, req model.UpdateUserRequest) (*model.User, error) {
 	// Get existing user
-	user, err := s.userRepo.GetByID(userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return nil, fmt.Errorf("user not found")
 	}
 
@@ -116,7 +124,7 @@ func (s *UserService) UpdateUser(userID int, req model.UpdateUserRequest) (*mode
 		}
 		// Check if new email already exists (excluding current user)
 		if *req.User.Email != user.Email {
-			emailExists, err := s.userRepo.EmailExists(*req.User.Email)
+			emailExists, err := s.userRepo.EmailExists(ctx, *req.User.Email)
 			if err != nil {
 				return nil, fmt.Errorf("failed to check email existence: %w", err)
 			}
@@ -133,7 +141,7 @@ func (s *UserService) UpdateUser(userID int, req model.UpdateUserRequest) (*mode
 		}
 		// Check if new username already exists (excluding current user)
 		if *req.User.Username != user.Username {
-			usernameExists, err := s.userRepo.UsernameExists(*req.User.Username)
+			usernameExists, err := s.userRepo.UsernameExists(ctx, *req.User.Username)
 			if err != nil {
 				return nil, fmt.Errorf("failed to check username existence: %w", err)
 			}
@@ -164,7 +172,7 @@ func (s *UserService) UpdateUser(userID int, req model.UpdateUserRequest) (*mode
 	}
 
 	// Update in database
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
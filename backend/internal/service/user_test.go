@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hands-on-vibe-coding/realworld-vibe-coding/backend/internal/model"
+)
+
+func TestCreateUserValidation(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	services.register(t, "jake")
+
+	tests := []struct {
+		name     string
+		email    string
+		username string
+		password string
+		wantErr  string
+	}{
+		{"missing email", "", "someone", "password1", "email is required"},
+		{"invalid email", "not-an-email", "someone", "password1", "invalid email format"},
+		{"short username", "a@example.com", "ab", "password1", "username must be at least 3 characters long"},
+		{"invalid username", "a@example.com", "some one", "password1", "username can only contain letters, numbers, and underscores"},
+		{"short password", "a@example.com", "someone", "12345", "password must be at least 6 characters long"},
+		{"taken email", "jake@example.com", "someone", "password1", "email already exists"},
+		{"taken username", "a@example.com", "jake", "password1", "username already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req model.CreateUserRequest
+			req.User.Email = tt.email
+			req.User.Username = tt.username
+			req.User.Password = tt.password
+
+			_, err := services.Users.CreateUser(ctx, req)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("CreateUser() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticateUser(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+
+	user, err := services.Users.AuthenticateUser(ctx, "jake@example.com", "password1")
+	if err != nil || user.ID != jake.ID {
+		t.Fatalf("AuthenticateUser() = %v, %v; want user %d", user, err, jake.ID)
+	}
+
+	for _, creds := range [][2]string{{"jake@example.com", "wrong"}, {"nobody@example.com", "password1"}} {
+		if _, err := services.Users.AuthenticateUser(ctx, creds[0], creds[1]); err == nil || err.Error() != "invalid email or password" {
+			t.Errorf("AuthenticateUser(%s, %s) error = %v, want invalid email or password", creds[0], creds[1], err)
+		}
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := services.Users.AuthenticateUser(canceled, "jake@example.com", "password1"); err != context.Canceled {
+		t.Errorf("AuthenticateUser() with canceled context error = %v, want context canceled", err)
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+	jake := services.register(t, "jake")
+	services.register(t, "celeb")
+
+	var req model.UpdateUserRequest
+	bio, username := "I work at statefarm", "jacob"
+	req.User.Bio = &bio
+	req.User.Username = &username
+
+	updated, err := services.Users.UpdateUser(ctx, jake.ID, req)
+	if err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if updated.Bio != bio || updated.Username != username {
+		t.Errorf("UpdateUser() = %+v, want bio and username changed", updated)
+	}
+	if _, err := services.Users.GetUserByUsername(ctx, "jacob"); err != nil {
+		t.Errorf("GetUserByUsername() after rename error = %v", err)
+	}
+
+	taken := "celeb"
+	req = model.UpdateUserRequest{}
+	req.User.Username = &taken
+	if _, err := services.Users.UpdateUser(ctx, jake.ID, req); err == nil || err.Error() != "username already exists" {
+		t.Errorf("UpdateUser() to taken username error = %v, want username already exists", err)
+	}
+
+	if _, err := services.Users.UpdateUser(ctx, 999, model.UpdateUserRequest{}); err == nil || err.Error() != "user not found" {
+		t.Errorf("UpdateUser() of unknown user error = %v, want user not found", err)
+	}
+}
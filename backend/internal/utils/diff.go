@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around each change
+	diffContext = 3
+
+	// maxDiffCells bounds the longest common subsequence table, which needs
+	// a cell per pair of differing lines. Beyond it the differing lines are
+	// shown as removed and added wholesale rather than matched up.
+	maxDiffCells = 1 << 22
+)
+
+// diffLine is a line of a diff: kept (' '), removed ('-') or added ('+')
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// UnifiedDiff returns the line-by-line differences between from and to in
+// unified diff format, labelled with fromName and toName. It returns an
+// empty string when the texts are the same.
+func UnifiedDiff(fromName, toName, from, to string) string {
+	lines := diffLines(splitLines(from), splitLines(to))
+
+	var changes []int
+	for i, line := range lines {
+		if line.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	// Line numbers in from and to of each diff line
+	fromLine, toLine := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	for i, line := range lines {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if line.kind != '+' {
+			fromLine[i+1]++
+		}
+		if line.kind != '-' {
+			toLine[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Changes closer than twice the context share a hunk
+	for first := 0; first < len(changes); {
+		last := first
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*diffContext {
+			last++
+		}
+
+		start := changes[first] - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := changes[last] + diffContext + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(fromLine[start], fromLine[end]-fromLine[start]),
+			hunkRange(toLine[start], toLine[end]-toLine[start]))
+		for _, line := range lines[start:end] {
+			out.WriteByte(line.kind)
+			out.WriteString(line.text)
+			out.WriteByte('\n')
+		}
+
+		first = last + 1
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the range of a hunk given the number of lines before it
+// and the number of lines in it, the way diff -u does
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	default:
+		return fmt.Sprintf("%d,%d", before+1, count)
+	}
+}
+
+// splitLines splits text into lines without their line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns a shortest edit turning a into b, from the longest
+// common subsequence of their lines, unless the lines that differ are too
+// many to match up within maxDiffCells. Removals come before additions.
+func diffLines(a, b []string) []diffLine {
+	// Only the middle that differs needs the quadratic table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i*width+j] is the length of the longest common subsequence of
+	// am[i:] and bm[j:]. When the table would be too large it is skipped
+	// and the middle is replaced wholesale.
+	width := len(bm) + 1
+	var lcs []int32
+	if (len(am)+1)*width <= maxDiffCells {
+		lcs = make([]int32, (len(am)+1)*width)
+		for i := len(am) - 1; i >= 0; i-- {
+			for j := len(bm) - 1; j >= 0; j-- {
+				switch {
+				case am[i] == bm[j]:
+					lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+				case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+					lcs[i*width+j] = lcs[(i+1)*width+j]
+				default:
+					lcs[i*width+j] = lcs[i*width+j+1]
+				}
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		lines = append(lines, diffLine{' ', text})
+	}
+	if lcs == nil {
+		for _, text := range am {
+			lines = append(lines, diffLine{'-', text})
+		}
+		for _, text := range bm {
+			lines = append(lines, diffLine{'+', text})
+		}
+		am, bm = nil, nil
+	}
+	for i, j := 0, 0; i < len(am) || j < len(bm); {
+		switch {
+		case i < len(am) && j < len(bm) && am[i] == bm[j]:
+			lines = append(lines, diffLine{' ', am[i]})
+			i++
+			j++
+		case j < len(bm) && (i == len(am) || lcs[i*width+j+1] > lcs[(i+1)*width+j]):
+			lines = append(lines, diffLine{'+', bm[j]})
+			j++
+		default:
+			lines = append(lines, diffLine{'-', am[i]})
+			i++
+		}
+	}
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', text})
+	}
+
+	return lines
+}
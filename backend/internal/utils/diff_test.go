@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		expected string
+	}{
+		{
+			name:     "same text",
+			from:     "a\nb\n",
+			to:       "a\nb\n",
+			expected: "",
+		},
+		{
+			name:     "changed line",
+			from:     "a\nb\nc\n",
+			to:       "a\nB\nc\n",
+			expected: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:     "from empty",
+			from:     "",
+			to:       "a\n",
+			expected: "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:     "to empty",
+			from:     "a\nb\n",
+			to:       "",
+			expected: "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name:     "context is limited",
+			from:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			to:       "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			expected: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:     "distant changes make separate hunks",
+			from:     "a\n1\n2\n3\n4\n5\n6\n7\nb\n",
+			to:       "A\n1\n2\n3\n4\n5\n6\n7\nB\n",
+			expected: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+B\n",
+		},
+		{
+			name:     "nearby changes share a hunk",
+			from:     "a\n1\n2\n3\nb\n",
+			to:       "A\n1\n2\n3\nB\n",
+			expected: "--- old\n+++ new\n@@ -1,5 +1,5 @@\n-a\n+A\n 1\n 2\n 3\n-b\n+B\n",
+		},
+		{
+			name:     "insertion keeps common lines",
+			from:     "a\nc\n",
+			to:       "a\nb\nc\n",
+			expected: "--- old\n+++ new\n@@ -1,2 +1,3 @@\n a\n+b\n c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := UnifiedDiff("old", "new", tt.from, tt.to); result != tt.expected {
+				t.Errorf("UnifiedDiff() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffOfLargeTexts(t *testing.T) {
+	// Two bodies of 100k short lines that differ throughout would need a
+	// table of 10^10 cells to match up
+	var from, to strings.Builder
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&from, "a%d\n", i)
+		fmt.Fprintf(&to, "b%d\n", i)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	diff := UnifiedDiff("old", "new", from.String(), to.String())
+	runtime.ReadMemStats(&after)
+
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 256<<20 {
+		t.Errorf("UnifiedDiff() allocated %d MB, want at most 256 MB", allocated>>20)
+	}
+	if !strings.HasPrefix(diff, "--- old\n+++ new\n@@ -1,100000 +1,100000 @@\n-a0\n") || !strings.HasSuffix(diff, "+b99999\n") {
+		t.Errorf("UnifiedDiff() = %.60q…, want every line replaced", diff)
+	}
+}
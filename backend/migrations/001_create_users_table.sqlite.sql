@@ -1,6 +1,7 @@
 -- Create users table
 -- Migration: 001_create_users_table.sql
 
+-- migrate:up
 CREATE TABLE IF NOT EXISTS users (
     id INTEGER PRIMARY KEY,
     email TEXT UNIQUE NOT NULL,
@@ -23,3 +24,9 @@ CREATE TRIGGER IF NOT EXISTS update_users_updated_at
 BEGIN
     UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
 END;
+
+-- migrate:down
+DROP TRIGGER IF EXISTS update_users_updated_at;
+DROP INDEX IF EXISTS idx_users_username;
+DROP INDEX IF EXISTS idx_users_email;
+DROP TABLE IF EXISTS users;
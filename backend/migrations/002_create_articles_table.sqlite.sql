@@ -1,6 +1,7 @@
 -- Create articles table
 -- Migration: 002_create_articles_table.sql
 
+-- migrate:up
 CREATE TABLE IF NOT EXISTS articles (
     id INTEGER PRIMARY KEY,
     slug TEXT UNIQUE NOT NULL,
@@ -25,3 +26,10 @@ CREATE TRIGGER IF NOT EXISTS update_articles_updated_at
 BEGIN
     UPDATE articles SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
 END;
+
+-- migrate:down
+DROP TRIGGER IF EXISTS update_articles_updated_at;
+DROP INDEX IF EXISTS idx_articles_created_at;
+DROP INDEX IF EXISTS idx_articles_author_id;
+DROP INDEX IF EXISTS idx_articles_slug;
+DROP TABLE IF EXISTS articles;
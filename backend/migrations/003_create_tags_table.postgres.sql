@@ -0,0 +1,16 @@
+-- Create tags table
+-- Migration: 003_create_tags_table.sql (PostgreSQL)
+
+-- migrate:up
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for performance
+CREATE INDEX IF NOT EXISTS idx_tags_name ON tags(name);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_tags_name;
+DROP TABLE IF EXISTS tags;
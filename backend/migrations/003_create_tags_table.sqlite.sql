@@ -1,6 +1,7 @@
 -- Create tags table
 -- Migration: 003_create_tags_table.sql
 
+-- migrate:up
 CREATE TABLE IF NOT EXISTS tags (
     id INTEGER PRIMARY KEY,
     name TEXT UNIQUE NOT NULL,
@@ -8,4 +9,8 @@ CREATE TABLE IF NOT EXISTS tags (
 );
 
 -- Create index for performance
-CREATE INDEX IF NOT EXISTS idx_tags_name ON tags(name);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_tags_name ON tags(name);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_tags_name;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,22 @@
+-- Create article_tags table (many-to-many relationship)
+-- Migration: 004_create_article_tags_table.sql (PostgreSQL)
+
+-- migrate:up
+CREATE TABLE IF NOT EXISTS article_tags (
+    id SERIAL PRIMARY KEY,
+    article_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE,
+    UNIQUE(article_id, tag_id)
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_article_tags_article_id ON article_tags(article_id);
+CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags(tag_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_article_tags_tag_id;
+DROP INDEX IF EXISTS idx_article_tags_article_id;
+DROP TABLE IF EXISTS article_tags;
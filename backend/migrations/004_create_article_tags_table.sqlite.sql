@@ -1,6 +1,7 @@
 -- Create article_tags table (many-to-many relationship)
 -- Migration: 004_create_article_tags_table.sql
 
+-- migrate:up
 CREATE TABLE IF NOT EXISTS article_tags (
     id INTEGER PRIMARY KEY,
     article_id INTEGER NOT NULL,
@@ -13,4 +14,9 @@ CREATE TABLE IF NOT EXISTS article_tags (
 
 -- Create indexes for performance
 CREATE INDEX IF NOT EXISTS idx_article_tags_article_id ON article_tags(article_id);
-CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags(tag_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags(tag_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_article_tags_tag_id;
+DROP INDEX IF EXISTS idx_article_tags_article_id;
+DROP TABLE IF EXISTS article_tags;
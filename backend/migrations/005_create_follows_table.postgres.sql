@@ -0,0 +1,23 @@
+-- Create follows table (user relationships)
+-- Migration: 005_create_follows_table.sql (PostgreSQL)
+
+-- migrate:up
+CREATE TABLE IF NOT EXISTS follows (
+    id SERIAL PRIMARY KEY,
+    follower_id INTEGER NOT NULL,
+    followed_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (followed_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(follower_id, followed_id),
+    CHECK(follower_id != followed_id)
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_follows_follower_id ON follows(follower_id);
+CREATE INDEX IF NOT EXISTS idx_follows_followed_id ON follows(followed_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_follows_followed_id;
+DROP INDEX IF EXISTS idx_follows_follower_id;
+DROP TABLE IF EXISTS follows;
@@ -1,6 +1,7 @@
 -- Create follows table (user relationships)
 -- Migration: 005_create_follows_table.sql
 
+-- migrate:up
 CREATE TABLE IF NOT EXISTS follows (
     id INTEGER PRIMARY KEY,
     follower_id INTEGER NOT NULL,
@@ -14,4 +15,9 @@ CREATE TABLE IF NOT EXISTS follows (
 
 -- Create indexes for performance
 CREATE INDEX IF NOT EXISTS idx_follows_follower_id ON follows(follower_id);
-CREATE INDEX IF NOT EXISTS idx_follows_followed_id ON follows(followed_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_follows_followed_id ON follows(followed_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_follows_followed_id;
+DROP INDEX IF EXISTS idx_follows_follower_id;
+DROP TABLE IF EXISTS follows;
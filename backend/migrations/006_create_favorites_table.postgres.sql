@@ -0,0 +1,22 @@
+-- Create favorites table (user-article relationships)
+-- Migration: 006_create_favorites_table.sql (PostgreSQL)
+
+-- migrate:up
+CREATE TABLE IF NOT EXISTS favorites (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    article_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+    UNIQUE(user_id, article_id)
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_favorites_user_id ON favorites(user_id);
+CREATE INDEX IF NOT EXISTS idx_favorites_article_id ON favorites(article_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_favorites_article_id;
+DROP INDEX IF EXISTS idx_favorites_user_id;
+DROP TABLE IF EXISTS favorites;
@@ -1,6 +1,7 @@
 -- Create favorites table (user-article relationships)
 -- Migration: 006_create_favorites_table.sql
 
+-- migrate:up
 CREATE TABLE IF NOT EXISTS favorites (
     id INTEGER PRIMARY KEY,
     user_id INTEGER NOT NULL,
@@ -13,4 +14,9 @@ CREATE TABLE IF NOT EXISTS favorites (
 
 -- Create indexes for performance
 CREATE INDEX IF NOT EXISTS idx_favorites_user_id ON favorites(user_id);
-CREATE INDEX IF NOT EXISTS idx_favorites_article_id ON favorites(article_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_favorites_article_id ON favorites(article_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_favorites_article_id;
+DROP INDEX IF EXISTS idx_favorites_user_id;
+DROP TABLE IF EXISTS favorites;
@@ -1,6 +1,7 @@
 -- Create comments table
 -- Migration: 007_create_comments_table.sql
 
+-- migrate:up
 CREATE TABLE IF NOT EXISTS comments (
     id INTEGER PRIMARY KEY,
     body TEXT NOT NULL,
@@ -24,3 +25,10 @@ CREATE TRIGGER IF NOT EXISTS update_comments_updated_at
 BEGIN
     UPDATE comments SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
 END;
+
+-- migrate:down
+DROP TRIGGER IF EXISTS update_comments_updated_at;
+DROP INDEX IF EXISTS idx_comments_created_at;
+DROP INDEX IF EXISTS idx_comments_article_id;
+DROP INDEX IF EXISTS idx_comments_author_id;
+DROP TABLE IF EXISTS comments;
@@ -1,5 +1,6 @@
 -- Performance indexes for article listing queries
 
+-- migrate:up
 -- Index for articles ordering by created_at (most recent first)
 CREATE INDEX IF NOT EXISTS idx_articles_created_at ON articles(created_at DESC);
 
@@ -39,4 +40,15 @@ ANALYZE article_tags;
 ANALYZE tags;
 ANALYZE users;
 ANALYZE follows;
-ANALYZE favorites;
\ No newline at end of file
+ANALYZE favorites;
+
+-- migrate:down
+-- Only drop indexes introduced by this migration; the others belong to
+-- the table migrations and are recreated here with IF NOT EXISTS
+DROP INDEX IF EXISTS idx_articles_author_created;
+DROP INDEX IF EXISTS idx_favorites_article;
+DROP INDEX IF EXISTS idx_favorites_user_article;
+DROP INDEX IF EXISTS idx_follows_followed;
+DROP INDEX IF EXISTS idx_follows_follower;
+DROP INDEX IF EXISTS idx_article_tags_tag_article;
+DROP INDEX IF EXISTS idx_article_tags_article_tag;
@@ -1,7 +1,12 @@
 -- Add favorites_count column to articles table
 -- Migration: 009_add_favorites_count_to_articles.sql
 
+-- migrate:up
 ALTER TABLE articles ADD COLUMN favorites_count INTEGER DEFAULT 0 NOT NULL;
 
 -- Create index for favorites_count for performance
-CREATE INDEX IF NOT EXISTS idx_articles_favorites_count ON articles(favorites_count DESC);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_articles_favorites_count ON articles(favorites_count DESC);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_articles_favorites_count;
+ALTER TABLE articles DROP COLUMN favorites_count;
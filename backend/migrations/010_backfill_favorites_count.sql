@@ -0,0 +1,9 @@
+-- Backfill favorites_count from the favorites table
+-- Migration: 010_backfill_favorites_count.sql
+
+-- migrate:up
+UPDATE articles
+SET favorites_count = (SELECT COUNT(*) FROM favorites f WHERE f.article_id = articles.id);
+
+-- migrate:down
+UPDATE articles SET favorites_count = 0;
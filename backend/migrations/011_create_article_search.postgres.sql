@@ -0,0 +1,74 @@
+-- Create the full-text search index over articles
+-- Migration: 011_create_article_search.sql (PostgreSQL)
+--
+-- Each article has one weighted tsvector: title A, tags and description B,
+-- body C. Triggers keep it in step with articles and their tags.
+
+-- migrate:up
+CREATE TABLE IF NOT EXISTS article_search (
+    article_id INTEGER PRIMARY KEY,
+    document TSVECTOR NOT NULL,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_search_document ON article_search USING GIN (document);
+
+CREATE OR REPLACE FUNCTION refresh_article_search(target INTEGER)
+RETURNS VOID AS $$
+BEGIN
+    INSERT INTO article_search (article_id, document)
+    SELECT a.id,
+           setweight(to_tsvector('english', a.title), 'A') ||
+           setweight(to_tsvector('english', COALESCE((SELECT string_agg(t.name, ' ')
+                                                      FROM article_tags at JOIN tags t ON t.id = at.tag_id
+                                                      WHERE at.article_id = a.id), '')), 'B') ||
+           setweight(to_tsvector('english', a.description), 'B') ||
+           setweight(to_tsvector('english', a.body), 'C')
+    FROM articles a
+    WHERE a.id = target
+    ON CONFLICT (article_id) DO UPDATE SET document = EXCLUDED.document;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION refresh_article_search_for_article()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_article_search(NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION refresh_article_search_for_tag()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM refresh_article_search(OLD.article_id);
+    ELSE
+        PERFORM refresh_article_search(NEW.article_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT refresh_article_search(id) FROM articles;
+
+DROP TRIGGER IF EXISTS article_search_article ON articles;
+CREATE TRIGGER article_search_article
+    AFTER INSERT OR UPDATE OF title, description, body ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION refresh_article_search_for_article();
+
+DROP TRIGGER IF EXISTS article_search_tag ON article_tags;
+CREATE TRIGGER article_search_tag
+    AFTER INSERT OR DELETE ON article_tags
+    FOR EACH ROW
+    EXECUTE FUNCTION refresh_article_search_for_tag();
+
+-- migrate:down
+DROP TRIGGER IF EXISTS article_search_tag ON article_tags;
+DROP TRIGGER IF EXISTS article_search_article ON articles;
+DROP FUNCTION IF EXISTS refresh_article_search_for_tag();
+DROP FUNCTION IF EXISTS refresh_article_search_for_article();
+DROP FUNCTION IF EXISTS refresh_article_search(INTEGER);
+DROP INDEX IF EXISTS idx_article_search_document;
+DROP TABLE IF EXISTS article_search;
@@ -0,0 +1,66 @@
+-- Create the full-text search index over articles
+-- Migration: 011_create_article_search.sql (SQLite)
+--
+-- FTS5 is only compiled into go-sqlite3 with the sqlite_fts5 build tag;
+-- without it this migration fails with "no such module: fts5". The rowid is
+-- the article ID; triggers keep the index in step with articles and their
+-- tags.
+
+-- migrate:up
+CREATE VIRTUAL TABLE IF NOT EXISTS article_search USING fts5(title, description, body, tags, tokenize='porter');
+
+INSERT INTO article_search (rowid, title, description, body, tags)
+SELECT a.id, a.title, a.description, a.body,
+       COALESCE((SELECT group_concat(t.name, ' ')
+                 FROM article_tags at JOIN tags t ON t.id = at.tag_id
+                 WHERE at.article_id = a.id), '')
+FROM articles a;
+
+CREATE TRIGGER IF NOT EXISTS article_search_insert
+    AFTER INSERT ON articles
+BEGIN
+    INSERT INTO article_search (rowid, title, description, body, tags)
+    VALUES (NEW.id, NEW.title, NEW.description, NEW.body, '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS article_search_update
+    AFTER UPDATE OF title, description, body ON articles
+BEGIN
+    UPDATE article_search
+    SET title = NEW.title, description = NEW.description, body = NEW.body
+    WHERE rowid = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS article_search_delete
+    AFTER DELETE ON articles
+BEGIN
+    DELETE FROM article_search WHERE rowid = OLD.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS article_search_tag_insert
+    AFTER INSERT ON article_tags
+BEGIN
+    UPDATE article_search
+    SET tags = COALESCE((SELECT group_concat(t.name, ' ')
+                         FROM article_tags at JOIN tags t ON t.id = at.tag_id
+                         WHERE at.article_id = NEW.article_id), '')
+    WHERE rowid = NEW.article_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS article_search_tag_delete
+    AFTER DELETE ON article_tags
+BEGIN
+    UPDATE article_search
+    SET tags = COALESCE((SELECT group_concat(t.name, ' ')
+                         FROM article_tags at JOIN tags t ON t.id = at.tag_id
+                         WHERE at.article_id = OLD.article_id), '')
+    WHERE rowid = OLD.article_id;
+END;
+
+-- migrate:down
+DROP TRIGGER IF EXISTS article_search_tag_delete;
+DROP TRIGGER IF EXISTS article_search_tag_insert;
+DROP TRIGGER IF EXISTS article_search_delete;
+DROP TRIGGER IF EXISTS article_search_update;
+DROP TRIGGER IF EXISTS article_search_insert;
+DROP TABLE IF EXISTS article_search;
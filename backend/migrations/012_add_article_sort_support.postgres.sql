@@ -0,0 +1,25 @@
+-- Support the article list sorts
+-- Migration: 012_add_article_sort_support.sql (PostgreSQL)
+--
+-- "Recently updated" orders by updated_at, so it must only move when the
+-- article is edited, not when favorites_count changes.
+
+-- migrate:up
+DROP TRIGGER IF EXISTS update_articles_updated_at ON articles;
+CREATE TRIGGER update_articles_updated_at
+    BEFORE UPDATE OF title, description, body ON articles
+    FOR EACH ROW
+    WHEN (NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at)
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Newest, oldest and most favorited use the indexes on created_at and
+-- favorites_count
+CREATE INDEX IF NOT EXISTS idx_articles_updated_at ON articles(updated_at DESC);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_articles_updated_at;
+DROP TRIGGER IF EXISTS update_articles_updated_at ON articles;
+CREATE TRIGGER update_articles_updated_at
+    BEFORE UPDATE ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,30 @@
+-- Support the article list sorts
+-- Migration: 012_add_article_sort_support.sql (SQLite)
+--
+-- "Recently updated" orders by updated_at, so it must only move when the
+-- article is edited. The trigger used to overwrite it on every UPDATE,
+-- including favorites_count changes, with a second-resolution timestamp in
+-- a different format from the one the application writes; it now only
+-- fills it in for content edits that did not set it.
+
+-- migrate:up
+DROP TRIGGER IF EXISTS update_articles_updated_at;
+CREATE TRIGGER IF NOT EXISTS update_articles_updated_at
+    AFTER UPDATE OF title, description, body ON articles
+    WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE articles SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Newest, oldest and most favorited use the indexes on created_at and
+-- favorites_count
+CREATE INDEX IF NOT EXISTS idx_articles_updated_at ON articles(updated_at DESC);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_articles_updated_at;
+DROP TRIGGER IF EXISTS update_articles_updated_at;
+CREATE TRIGGER IF NOT EXISTS update_articles_updated_at
+    AFTER UPDATE ON articles
+BEGIN
+    UPDATE articles SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,20 @@
+-- Article drafts and scheduled publishing
+-- Migration: 013_add_article_status.sql (SQLite)
+--
+-- Drafts and scheduled articles are only visible to their author until
+-- they are published. publish_at is when a scheduled article goes out, and
+-- the publication time once it is published.
+
+-- migrate:up
+ALTER TABLE articles ADD COLUMN status TEXT NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'scheduled', 'published'));
+ALTER TABLE articles ADD COLUMN publish_at DATETIME;
+UPDATE articles SET publish_at = created_at;
+
+-- The lists filter on status, and the publisher looks for due articles
+CREATE INDEX IF NOT EXISTS idx_articles_status_publish_at ON articles(status, publish_at);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_articles_status_publish_at;
+ALTER TABLE articles DROP COLUMN publish_at;
+ALTER TABLE articles DROP COLUMN status;
@@ -0,0 +1,28 @@
+-- Create article revisions table
+-- Migration: 014_create_article_revisions.sql (PostgreSQL)
+--
+-- Every update of an article first stores the article as it was: revision
+-- n holds the fields and tags replaced by the n-th update, and who made it
+-- when. tag_list is a JSON array.
+
+-- migrate:up
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id SERIAL PRIMARY KEY,
+    article_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    editor_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    body TEXT NOT NULL,
+    tag_list TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (article_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_revisions_editor_id ON article_revisions(editor_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_article_revisions_editor_id;
+DROP TABLE IF EXISTS article_revisions;
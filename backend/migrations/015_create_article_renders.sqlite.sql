@@ -0,0 +1,29 @@
+-- Cache rendered article bodies
+-- Migration: 015_create_article_renders.sql (SQLite)
+--
+-- articles.revision numbers the versions of an article: it starts at 1 and
+-- every update adds one, so revision n of article_revisions is the article's
+-- n-th version. article_renders caches the sanitized HTML and plaintext
+-- excerpt of a version's Markdown body; renderer is the version of the
+-- renderer that produced them, so a changed renderer redoes the cache.
+
+-- migrate:up
+ALTER TABLE articles ADD COLUMN revision INTEGER NOT NULL DEFAULT 1;
+UPDATE articles SET revision = 1 + (
+    SELECT COUNT(*) FROM article_revisions WHERE article_revisions.article_id = articles.id
+);
+
+CREATE TABLE IF NOT EXISTS article_renders (
+    article_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    renderer INTEGER NOT NULL,
+    body_html TEXT NOT NULL,
+    excerpt TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (article_id, revision),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS article_renders;
+ALTER TABLE articles DROP COLUMN revision;
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migration files into the server binary
+package migrations
+
+import "embed"
+
+// FS holds the SQL migration files shipped with the binary
+//
+//go:embed *.sql
+var FS embed.FS